@@ -5,8 +5,10 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	"github.com/pna/order-app-backend/internal/utils/env"
 
 	"github.com/gin-gonic/gin"
 
@@ -25,6 +27,18 @@ type Server struct {
 	orderHandler            *v1.OrderHandler
 	orderImageHandler       *v1.OrderImageHandler
 	statisticsHandler       *v1.StatisticsHandler
+	paymentHandler          *v1.PaymentHandler
+	orderReturnHandler      *v1.OrderReturnHandler
+	exportHandler           *v1.ExportHandler
+	documentHandler         *v1.DocumentHandler
+	importHandler           *v1.ImportHandler
+	auditLogHandler         *v1.AuditLogHandler
+	supplierHandler         *v1.SupplierHandler
+	purchaseOrderHandler    *v1.PurchaseOrderHandler
+	warehouseHandler        *v1.WarehouseHandler
+	stockTransferHandler    *v1.StockTransferHandler
+	stocktakeHandler        *v1.StocktakeHandler
+	priceListHandler        *v1.PriceListHandler
 }
 
 func NewServer(
@@ -39,6 +53,18 @@ func NewServer(
 	orderHandler *v1.OrderHandler,
 	orderImageHandler *v1.OrderImageHandler,
 	statisticsHandler *v1.StatisticsHandler,
+	paymentHandler *v1.PaymentHandler,
+	orderReturnHandler *v1.OrderReturnHandler,
+	exportHandler *v1.ExportHandler,
+	documentHandler *v1.DocumentHandler,
+	importHandler *v1.ImportHandler,
+	auditLogHandler *v1.AuditLogHandler,
+	supplierHandler *v1.SupplierHandler,
+	purchaseOrderHandler *v1.PurchaseOrderHandler,
+	warehouseHandler *v1.WarehouseHandler,
+	stockTransferHandler *v1.StockTransferHandler,
+	stocktakeHandler *v1.StocktakeHandler,
+	priceListHandler *v1.PriceListHandler,
 ) *Server {
 	return &Server{
 		healthHandler:           healthHandler,
@@ -52,11 +78,29 @@ func NewServer(
 		orderHandler:            orderHandler,
 		orderImageHandler:       orderImageHandler,
 		statisticsHandler:       statisticsHandler,
+		paymentHandler:          paymentHandler,
+		orderReturnHandler:      orderReturnHandler,
+		exportHandler:           exportHandler,
+		documentHandler:         documentHandler,
+		importHandler:           importHandler,
+		auditLogHandler:         auditLogHandler,
+		supplierHandler:         supplierHandler,
+		purchaseOrderHandler:    purchaseOrderHandler,
+		warehouseHandler:        warehouseHandler,
+		stockTransferHandler:    stockTransferHandler,
+		stocktakeHandler:        stocktakeHandler,
+		priceListHandler:        priceListHandler,
 	}
 }
 
 func (s *Server) Run() {
 	router := gin.New()
+	// Only the proxies listed in TRUSTED_PROXIES may set X-Forwarded-For, otherwise the client IP used for
+	// login lockouts could be picked by the client itself
+	if err := router.SetTrustedProxies(trustedProxies()); err != nil {
+		fmt.Println("There is error: " + err.Error())
+		return
+	}
 	port, _ := strconv.Atoi(os.Getenv("PORT"))
 	httpServerInstance := &http.Server{
 		Addr:    fmt.Sprintf(":%d", port),
@@ -76,6 +120,18 @@ func (s *Server) Run() {
 		s.orderHandler,
 		s.orderImageHandler,
 		s.statisticsHandler,
+		s.paymentHandler,
+		s.orderReturnHandler,
+		s.exportHandler,
+		s.documentHandler,
+		s.importHandler,
+		s.auditLogHandler,
+		s.supplierHandler,
+		s.purchaseOrderHandler,
+		s.warehouseHandler,
+		s.stockTransferHandler,
+		s.stocktakeHandler,
+		s.priceListHandler,
 		s.authMiddleware,
 	)
 	err := httpServerInstance.ListenAndServe()
@@ -84,3 +140,19 @@ func (s *Server) Run() {
 		return
 	}
 }
+
+// trustedProxies reads the comma-separated proxy IPs or CIDRs from TRUSTED_PROXIES, trusting none by default
+func trustedProxies() []string {
+	value, err := env.GetEnv("TRUSTED_PROXIES")
+	if err != nil {
+		return nil
+	}
+
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
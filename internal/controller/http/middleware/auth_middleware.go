@@ -3,6 +3,8 @@ package middleware
 import (
 	"strings"
 
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
 	"github.com/pna/order-app-backend/internal/utils/error_utils"
 	log "github.com/sirupsen/logrus"
 
@@ -12,10 +14,13 @@ import (
 )
 
 type AuthMiddleware struct {
+	userRepository repository.UserRepository
 }
 
-func NewAuthMiddleware() *AuthMiddleware {
-	return &AuthMiddleware{}
+func NewAuthMiddleware(userRepository repository.UserRepository) *AuthMiddleware {
+	return &AuthMiddleware{
+		userRepository: userRepository,
+	}
 }
 
 func getAccessToken(c *gin.Context) (token string) {
@@ -36,6 +41,21 @@ func GetUserIdHelper(c *gin.Context) int64 {
 	return userId.(int64)
 }
 
+// GetUserRoleHelper returns the role of the signed-in user
+func GetUserRoleHelper(c *gin.Context) string {
+	role, exists := c.Get("userRole")
+	if !exists {
+		return ""
+	}
+	return role.(string)
+}
+
+// CanViewCostHelper reports whether the current user may see original prices, costs and profit figures
+func CanViewCostHelper(c *gin.Context) bool {
+	role := GetUserRoleHelper(c)
+	return role == entity.UserRole.OWNER || role == entity.UserRole.ACCOUNTANT
+}
+
 func (a *AuthMiddleware) VerifyAccessToken(c *gin.Context) {
 	// Get the JWT secret from the environment
 	jwtSecret, err := env.GetEnv("JWT_SECRET")
@@ -54,12 +74,47 @@ func (a *AuthMiddleware) VerifyAccessToken(c *gin.Context) {
 		// If the access token is valid, extract user Id and proceed
 		if payload, ok := claims.Payload.(map[string]interface{}); ok {
 			userId := int64(payload["id"].(float64))
-			c.Set("userId", userId)
-			c.Next()
-			return
+
+			// Load the account so disabled users are locked out, revoked tokens are refused and role changes apply without a new token
+			user, err := a.userRepository.FindByIDQuery(c, int(userId), nil)
+			if err != nil {
+				log.Error("AuthMiddleware.VerifyAccessToken Error getting user: " + err.Error())
+				statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.DB_DOWN, "")
+				c.AbortWithStatusJSON(statusCode, errResponse)
+				return
+			}
+			// Tokens issued before the last logout-all or password change carry an older version and are revoked
+			tokenVersion, hasTokenVersion := payload["token_version"].(float64)
+			if user != nil && hasTokenVersion && int(tokenVersion) == user.TokenVersion {
+				if !user.IsActive {
+					statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.USER_DISABLED, "")
+					c.AbortWithStatusJSON(statusCode, errResponse)
+					return
+				}
+				c.Set("userId", userId)
+				c.Set("userRole", user.Role)
+				c.Next()
+				return
+			}
 		}
 	}
 
 	statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.ACCESS_TOKEN_INVALID, "accessToken")
 	c.AbortWithStatusJSON(statusCode, errResponse)
 }
+
+// RequireRoles only lets users with one of the given roles through; it must run after VerifyAccessToken
+func (a *AuthMiddleware) RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRole := GetUserRoleHelper(c)
+		for _, role := range roles {
+			if userRole == role {
+				c.Next()
+				return
+			}
+		}
+
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.FORBIDDEN, "")
+		c.AbortWithStatusJSON(statusCode, errResponse)
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RequestIdHeader = "X-Request-ID"
+
+// RequestIdMiddleware tags every request with an ID, reusing the one sent by the client or a proxy when present,
+// and echoes it back in the response so a request can be matched with its audit log rows.
+func RequestIdMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(RequestIdHeader)
+		if requestId == "" || len(requestId) > 64 {
+			requestId = uuid.New().String()
+		}
+
+		c.Set("requestId", requestId)
+		c.Writer.Header().Set(RequestIdHeader, requestId)
+		c.Next()
+	}
+}
+
+func GetRequestIdHelper(c *gin.Context) string {
+	requestId, exists := c.Get("requestId")
+	if !exists {
+		return ""
+	}
+	return requestId.(string)
+}
@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+)
+
+type AuditLogHandler struct {
+	auditLogService service.AuditLogService
+}
+
+func NewAuditLogHandler(auditLogService service.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{
+		auditLogService: auditLogService,
+	}
+}
+
+// @Summary Get Audit Logs
+// @Description Who created, changed or deleted products, customers, orders, order images and inventory, with the data before and after each change. Newest first. Owner only.
+// @Tags Audit Logs
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param user_id query int false "Filter by the user who made the change"
+// @Param action query string false "Filter by action: CREATE, UPDATE, DELETE"
+// @Param entity_type query string false "Filter by entity type: PRODUCT, CUSTOMER, ORDER, ORDER_IMAGE, INVENTORY"
+// @Param entity_id query int false "Filter by entity ID, use together with entity_type"
+// @Param request_id query string false "Filter by request ID (the X-Request-ID response header)"
+// @Param from_date query string false "Filter from date (format: YYYY-MM-DD)"
+// @Param to_date query string false "Filter to date (format: YYYY-MM-DD)"
+// @Param limit query int false "Maximum rows (default: 100, max: 500)"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllAuditLogsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 403 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /audit-logs [get]
+func (h *AuditLogHandler) GetAll(ctx *gin.Context) {
+	request := model.GetAllAuditLogsRequest{
+		Action:     ctx.Query("action"),
+		EntityType: ctx.Query("entity_type"),
+		RequestID:  ctx.Query("request_id"),
+	}
+
+	// Parse numeric filters
+	for field, target := range map[string]*int{
+		"user_id":   &request.UserID,
+		"entity_id": &request.EntityID,
+		"limit":     &request.Limit,
+	} {
+		valueStr := ctx.Query(field)
+		if valueStr == "" {
+			continue
+		}
+		value, err := strconv.Atoi(valueStr)
+		if err != nil || value <= 0 {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, field)
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		*target = value
+	}
+
+	var ok bool
+	if request.FromDate, ok = parseDateQuery(ctx, "from_date"); !ok {
+		return
+	}
+	if request.ToDate, ok = parseDateQuery(ctx, "to_date"); !ok {
+		return
+	}
+
+	response, errCode := h.auditLogService.GetAll(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
@@ -0,0 +1,35 @@
+package v1
+
+import "github.com/pna/order-app-backend/internal/domain/model"
+
+// Helpers to drop original prices, costs and profit figures from responses sent to roles that may not see them.
+// See middleware.CanViewCostHelper for which roles can.
+
+func hideProductCost(product *model.ProductResponse) {
+	product.OriginalPrice = nil
+	product.AverageCost = nil
+}
+
+func hideOrderCost(order *model.OrderResponse) {
+	order.TotalProfitLoss = nil
+	order.TotalProfitLossPercentage = nil
+	for i := range order.OrderItems {
+		order.OrderItems[i].OriginalPrice = nil
+		order.OrderItems[i].ProfitLoss = nil
+		order.OrderItems[i].ProfitLossPercentage = nil
+	}
+}
+
+func hideOrderReturnCost(orderReturn *model.OrderReturnResponse) {
+	orderReturn.TotalOriginalCost = nil
+	for i := range orderReturn.Items {
+		orderReturn.Items[i].OriginalCost = nil
+	}
+}
+
+func hidePurchaseOrderCost(purchaseOrder *model.PurchaseOrderResponse) {
+	purchaseOrder.TotalAmount = nil
+	for i := range purchaseOrder.Items {
+		purchaseOrder.Items[i].PurchasePrice = nil
+	}
+}
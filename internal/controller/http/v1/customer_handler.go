@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
 	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
 	"github.com/pna/order-app-backend/internal/domain/model"
 	"github.com/pna/order-app-backend/internal/service"
@@ -85,15 +86,16 @@ func (h *CustomerHandler) Update(ctx *gin.Context) {
 }
 
 // @Summary Get All Customers
-// @Description Retrieve all customers
+// @Description Retrieve the customers that are not deleted. The search matches every word of q against the name and address, ignoring Vietnamese accents and case, and against the phone number in any format.
 // @Tags Customers
 // @Produce json
 // @Param  Authorization header string true "Authorization: Bearer"
+// @Param q query string false "Search by name, phone or address (e.g. nguyen van a, 0912 345 678)"
 // @Success 200 {object} httpcommon.HttpResponse[model.GetAllCustomersResponse]
 // @Failure 500 {object} httpcommon.HttpResponse[any]
 // @Router /customers [get]
 func (h *CustomerHandler) GetAll(ctx *gin.Context) {
-	response, errCode := h.customerService.GetAll(ctx)
+	response, errCode := h.customerService.GetAll(ctx, ctx.Query("q"))
 	if errCode != "" {
 		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
 		ctx.JSON(statusCode, errResponse)
@@ -132,3 +134,123 @@ func (h *CustomerHandler) GetOne(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
 }
+
+// @Summary Get Customer Summary
+// @Description Retrieve a customer's standing: their orders, lifetime revenue and profit, average order value, last order date, most bought products and the amount still unpaid. Cancelled orders are listed but not counted. Profit figures are only shown to roles that can see costs.
+// @Tags Customers
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param customerId path int true "Customer ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.CustomerSummaryResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /customers/{customerId}/summary [get]
+func (h *CustomerHandler) GetSummary(ctx *gin.Context) {
+	customerID, err := strconv.Atoi(ctx.Param("customerId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "customerId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.customerService.GetSummary(ctx, customerID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	if !middleware.CanViewCostHelper(ctx) {
+		response.LifetimeProfit = nil
+		for i := range response.Orders {
+			hideOrderCost(&response.Orders[i])
+		}
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Duplicate Customers
+// @Description Find customers that are probably the same shop entered more than once: customers with the same phone number once normalized, and customers with the same name ignoring accents and case. Each group lists its oldest customer first.
+// @Tags Customers
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetCustomerDuplicatesResponse]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /customers/duplicates [get]
+func (h *CustomerHandler) GetDuplicates(ctx *gin.Context) {
+	response, errCode := h.customerService.GetDuplicates(ctx)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Merge Customers
+// @Description Merge a duplicate customer into this one in a single transaction: all orders of the duplicate are moved to this customer, as are its price overrides and default discounts unless this customer already has one starting on the same date. The duplicate is then deleted.
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param customerId path int true "Customer ID to keep"
+// @Param request body model.MergeCustomerRequest true "Duplicate customer"
+// @Success 200 {object} httpcommon.HttpResponse[model.MergeCustomerResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /customers/{customerId}/merge [post]
+func (h *CustomerHandler) Merge(ctx *gin.Context) {
+	customerID, err := strconv.Atoi(ctx.Param("customerId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "customerId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.MergeCustomerRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.customerService.Merge(ctx, customerID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Delete Customer
+// @Description Soft delete a customer. The customer disappears from listings and cannot be used on new orders or price lists, while their existing orders are kept.
+// @Tags Customers
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param customerId path int true "Customer ID"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /customers/{customerId} [delete]
+func (h *CustomerHandler) Delete(ctx *gin.Context) {
+	customerID, err := strconv.Atoi(ctx.Param("customerId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "customerId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	errCode := h.customerService.Delete(ctx, customerID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+)
+
+type DocumentHandler struct {
+	documentService service.DocumentService
+}
+
+func NewDocumentHandler(documentService service.DocumentService) *DocumentHandler {
+	return &DocumentHandler{
+		documentService: documentService,
+	}
+}
+
+// @Summary Print order invoice
+// @Description Render the order as a PDF invoice with customer details, items, additional cost, tax, grand total and the amount in words
+// @Tags Orders
+// @Produce application/pdf
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Success 200 {file} file
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/invoice.pdf [get]
+func (h *DocumentHandler) RenderInvoice(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	file, errCode := h.documentService.RenderInvoice(ctx, orderID)
+	writeDocumentFile(ctx, file, errCode)
+}
+
+// @Summary Print order delivery note
+// @Description Render the order as a PDF delivery note: customer details and items without prices
+// @Tags Orders
+// @Produce application/pdf
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Success 200 {file} file
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/delivery-note.pdf [get]
+func (h *DocumentHandler) RenderDeliveryNote(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	file, errCode := h.documentService.RenderDeliveryNote(ctx, orderID)
+	writeDocumentFile(ctx, file, errCode)
+}
+
+// Helper to send a rendered document inline so browsers open it for printing
+func writeDocumentFile(ctx *gin.Context, file *model.ExportFile, errCode string) {
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `inline; filename="`+file.FileName+`"`)
+	ctx.Data(http.StatusOK, file.ContentType, file.Content)
+}
@@ -0,0 +1,186 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	exportutils "github.com/pna/order-app-backend/internal/utils/export_utils"
+)
+
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// @Summary Export Orders
+// @Description Download every order matching the same filters as the order listing. The xlsx file has an order sheet with a totals row and an item breakdown sheet; the CSV file only has the order sheet.
+// @Tags Exports
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce text/csv
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param format query string false "File format: xlsx, csv (default: xlsx)"
+// @Param customer_id query int false "Filter by customer ID"
+// @Param delivery_statuses query string false "Filter by delivery statuses (comma-separated, e.g., PENDING,DELIVERED)"
+// @Param debt_statuses query string false "Filter by debt statuses (comma-separated, e.g., UNPAID,PARTIAL)"
+// @Param q query string false "Search by customer name or phone"
+// @Param min_amount query int false "Minimum total amount (VND)"
+// @Param max_amount query int false "Maximum total amount (VND)"
+// @Param sort_by query string false "Sort by: order_date_asc, order_date_desc (default: id DESC)"
+// @Param from_date query string false "Filter from date (format: YYYY-MM-DD)"
+// @Param to_date query string false "Filter to date (format: YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/export [get]
+func (h *ExportHandler) ExportOrders(ctx *gin.Context) {
+	format, ok := parseExportFormat(ctx)
+	if !ok {
+		return
+	}
+
+	request, ok := parseGetAllOrdersRequest(ctx)
+	if !ok {
+		return
+	}
+
+	file, errCode := h.exportService.ExportOrders(ctx, request, format)
+	writeExportFile(ctx, file, errCode)
+}
+
+// @Summary Export Inventory
+// @Description Download the current stock of every product with its value at original price
+// @Tags Exports
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce text/csv
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param format query string false "File format: xlsx, csv (default: xlsx)"
+// @Success 200 {file} file
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /inventory/export [get]
+func (h *ExportHandler) ExportInventory(ctx *gin.Context) {
+	format, ok := parseExportFormat(ctx)
+	if !ok {
+		return
+	}
+
+	file, errCode := h.exportService.ExportInventory(ctx, format)
+	writeExportFile(ctx, file, errCode)
+}
+
+// @Summary Export revenue report by period
+// @Description Download the revenue report grouped by day, week or month. Defaults to the current month.
+// @Tags Exports
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce text/csv
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param format query string false "File format: xlsx, csv (default: xlsx)"
+// @Param group_by query string false "Group by: day, week, month (default: day)"
+// @Param from_date query string false "From date (format: YYYY-MM-DD)"
+// @Param to_date query string false "To date (format: YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /statistics/revenue/export [get]
+func (h *ExportHandler) ExportReportByPeriod(ctx *gin.Context) {
+	format, ok := parseExportFormat(ctx)
+	if !ok {
+		return
+	}
+
+	fromDate, toDate, ok := parseReportDateRange(ctx)
+	if !ok {
+		return
+	}
+
+	file, errCode := h.exportService.ExportReportByPeriod(ctx, ctx.DefaultQuery("group_by", "day"), fromDate, toDate, format)
+	writeExportFile(ctx, file, errCode)
+}
+
+// @Summary Export revenue report by customer
+// @Description Download the revenue report per customer. Defaults to the current month.
+// @Tags Exports
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce text/csv
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param format query string false "File format: xlsx, csv (default: xlsx)"
+// @Param from_date query string false "From date (format: YYYY-MM-DD)"
+// @Param to_date query string false "To date (format: YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /statistics/customers/export [get]
+func (h *ExportHandler) ExportReportByCustomer(ctx *gin.Context) {
+	format, ok := parseExportFormat(ctx)
+	if !ok {
+		return
+	}
+
+	fromDate, toDate, ok := parseReportDateRange(ctx)
+	if !ok {
+		return
+	}
+
+	file, errCode := h.exportService.ExportReportByCustomer(ctx, fromDate, toDate, format)
+	writeExportFile(ctx, file, errCode)
+}
+
+// @Summary Export revenue report by product
+// @Description Download the revenue report per product. Defaults to the current month.
+// @Tags Exports
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Produce text/csv
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param format query string false "File format: xlsx, csv (default: xlsx)"
+// @Param from_date query string false "From date (format: YYYY-MM-DD)"
+// @Param to_date query string false "To date (format: YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /statistics/products/export [get]
+func (h *ExportHandler) ExportReportByProduct(ctx *gin.Context) {
+	format, ok := parseExportFormat(ctx)
+	if !ok {
+		return
+	}
+
+	fromDate, toDate, ok := parseReportDateRange(ctx)
+	if !ok {
+		return
+	}
+
+	file, errCode := h.exportService.ExportReportByProduct(ctx, fromDate, toDate, format)
+	writeExportFile(ctx, file, errCode)
+}
+
+// parseExportFormat reads the format query parameter, xlsx by default
+func parseExportFormat(ctx *gin.Context) (string, bool) {
+	format := ctx.DefaultQuery("format", exportutils.FormatXLSX)
+	if format != exportutils.FormatXLSX && format != exportutils.FormatCSV {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "format")
+		ctx.JSON(statusCode, errResponse)
+		return "", false
+	}
+	return format, true
+}
+
+// writeExportFile sends the generated file as a download, or the error response.
+// The file is fully generated by the service before any status is written, so generation errors still get a proper error response.
+func writeExportFile(ctx *gin.Context, file *model.ExportFile, errCode string) {
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="`+file.FileName+`"`)
+	ctx.Data(http.StatusOK, file.ContentType, file.Content)
+}
@@ -0,0 +1,141 @@
+package v1
+
+import (
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	exportutils "github.com/pna/order-app-backend/internal/utils/export_utils"
+)
+
+type ImportHandler struct {
+	importService service.ImportService
+}
+
+func NewImportHandler(importService service.ImportService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// @Summary Import products
+// @Description Create products with their opening stock from an xlsx or CSV file. The header row needs the columns name, original_price and optionally spec, opening_quantity (the Vietnamese titles of the inventory export are accepted too). Every row is validated first; nothing is written unless all rows are valid. With dry_run=true the rows are only validated and previewed.
+// @Tags Imports
+// @Accept multipart/form-data
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param file formData file true "xlsx or csv file"
+// @Param dry_run query bool false "Only validate and preview the rows (default: false)"
+// @Success 200 {object} httpcommon.HttpResponse[model.ImportProductsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /products/import [post]
+func (h *ImportHandler) ImportProducts(ctx *gin.Context) {
+	file, format, dryRun, ok := parseImportRequest(ctx)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	resp, errCode := h.importService.ImportProducts(ctx, file, format, dryRun)
+	if errCode != "" {
+		var rowErrors []model.ImportRowError
+		if resp != nil {
+			rowErrors = resp.Errors
+		}
+		writeImportError(ctx, errCode, rowErrors)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(resp))
+}
+
+// @Summary Import customers
+// @Description Create customers from an xlsx or CSV file with the columns name, phone and address. Phone numbers must not repeat within the file or match an existing customer. Nothing is written unless all rows are valid; with dry_run=true the rows are only validated and previewed.
+// @Tags Imports
+// @Accept multipart/form-data
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param file formData file true "xlsx or csv file"
+// @Param dry_run query bool false "Only validate and preview the rows (default: false)"
+// @Success 200 {object} httpcommon.HttpResponse[model.ImportCustomersResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /customers/import [post]
+func (h *ImportHandler) ImportCustomers(ctx *gin.Context) {
+	file, format, dryRun, ok := parseImportRequest(ctx)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	resp, errCode := h.importService.ImportCustomers(ctx, file, format, dryRun)
+	if errCode != "" {
+		var rowErrors []model.ImportRowError
+		if resp != nil {
+			rowErrors = resp.Errors
+		}
+		writeImportError(ctx, errCode, rowErrors)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(resp))
+}
+
+// parseImportRequest opens the uploaded file and derives its format from the file extension
+func parseImportRequest(ctx *gin.Context) (multipart.File, string, bool, bool) {
+	dryRun := false
+	if dryRunStr := ctx.Query("dry_run"); dryRunStr != "" {
+		parsed, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "dry_run")
+			ctx.JSON(statusCode, errResponse)
+			return nil, "", false, false
+		}
+		dryRun = parsed
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "file")
+		ctx.JSON(statusCode, errResponse)
+		return nil, "", false, false
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+	if format != exportutils.FormatXLSX && format != exportutils.FormatCSV {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.IMPORT_FILE_INVALID, "file")
+		ctx.JSON(statusCode, errResponse)
+		return nil, "", false, false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.IMPORT_FILE_INVALID, "file")
+		ctx.JSON(statusCode, errResponse)
+		return nil, "", false, false
+	}
+	return file, format, dryRun, true
+}
+
+// writeImportError reports rejected rows one error per cell, or the plain error response
+func writeImportError(ctx *gin.Context, errCode string, rowErrors []model.ImportRowError) {
+	statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+	if errCode == error_utils.ErrorCode.IMPORT_ROWS_INVALID {
+		for _, rowError := range rowErrors {
+			errResponse.Errors = append(errResponse.Errors, httpcommon.Error{
+				Message: "Row " + strconv.Itoa(rowError.Row) + ": " + rowError.Message,
+				Code:    errCode,
+				Field:   rowError.Column,
+			})
+		}
+	}
+	ctx.JSON(statusCode, errResponse)
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
 	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
 	"github.com/pna/order-app-backend/internal/domain/model"
 	"github.com/pna/order-app-backend/internal/service"
@@ -12,6 +13,9 @@ import (
 	"github.com/pna/order-app-backend/internal/utils/validation"
 )
 
+// defaultLowStockSalesDays is how many past days of sales the low-stock list looks at when none is given
+const defaultLowStockSalesDays = 30
+
 type InventoryHandler struct {
 	inventoryService service.InventoryService
 }
@@ -27,13 +31,66 @@ func NewInventoryHandler(inventoryService service.InventoryService) *InventoryHa
 // @Tags Inventory
 // @Produce json
 // @Param  Authorization header string true "Authorization: Bearer"
+// @Param warehouse_id query int false "Only show the inventory of this warehouse, all warehouses when omitted"
 // @Success 200 {object} httpcommon.HttpResponse[model.GetAllInventoryResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
 // @Failure 500 {object} httpcommon.HttpResponse[any]
 // @Router /inventory [get]
 func (h *InventoryHandler) GetAll(ctx *gin.Context) {
 	context := ctx.Request.Context()
 
-	response, errCode := h.inventoryService.GetAll(context)
+	warehouseID, ok := parseIntQuery(ctx, "warehouse_id")
+	if !ok {
+		return
+	}
+	filterWarehouseID := 0
+	if warehouseID != nil {
+		filterWarehouseID = *warehouseID
+	}
+
+	response, errCode := h.inventoryService.GetAll(context, filterWarehouseID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	if !middleware.CanViewCostHelper(ctx) {
+		for i := range response.Inventories {
+			response.Inventories[i].Product.OriginalPrice = nil
+			response.Inventories[i].Product.AverageCost = nil
+		}
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Low Stock Products
+// @Description List products whose available stock over all warehouses is below their minimum stock, with a reorder quantity suggested from recent sales
+// @Tags Inventory
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param days query int false "Number of past days used to measure sales velocity, default 30, at most 365"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetLowStockProductsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /inventory/low-stock [get]
+func (h *InventoryHandler) GetLowStock(ctx *gin.Context) {
+	days, ok := parseIntQuery(ctx, "days")
+	if !ok {
+		return
+	}
+	salesDays := defaultLowStockSalesDays
+	if days != nil {
+		if *days <= 0 || *days > 365 {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "days")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		salesDays = *days
+	}
+
+	response, errCode := h.inventoryService.GetLowStock(ctx.Request.Context(), salesDays)
 	if errCode != "" {
 		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
 		ctx.JSON(statusCode, errResponse)
@@ -49,6 +106,7 @@ func (h *InventoryHandler) GetAll(ctx *gin.Context) {
 // @Produce json
 // @Param  Authorization header string true "Authorization: Bearer"
 // @Param productId path int true "Product ID"
+// @Param warehouse_id query int false "Warehouse ID, the default warehouse when omitted"
 // @Success 200 {object} httpcommon.HttpResponse[model.InventoryResponse]
 // @Failure 400 {object} httpcommon.HttpResponse[any]
 // @Failure 404 {object} httpcommon.HttpResponse[any]
@@ -63,7 +121,12 @@ func (h *InventoryHandler) GetByProductID(ctx *gin.Context) {
 		return
 	}
 
-	response, errCode := h.inventoryService.GetByProductID(ctx, productID)
+	warehouseID, ok := parseIntQuery(ctx, "warehouse_id")
+	if !ok {
+		return
+	}
+
+	response, errCode := h.inventoryService.GetByProductID(ctx, productID, warehouseID)
 	if errCode != "" {
 		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
 		ctx.JSON(statusCode, errResponse)
@@ -83,6 +146,7 @@ func (h *InventoryHandler) GetByProductID(ctx *gin.Context) {
 // @Param request body model.UpdateInventoryQuantityRequest true "Quantity update information"
 // @Success 200 {object} httpcommon.HttpResponse[model.InventoryResponse]
 // @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 403 {object} httpcommon.HttpResponse[any]
 // @Failure 404 {object} httpcommon.HttpResponse[any]
 // @Failure 500 {object} httpcommon.HttpResponse[any]
 // @Router /products/{productId}/inventories/quantity [put]
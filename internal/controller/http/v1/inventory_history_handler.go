@@ -3,6 +3,7 @@ package v1
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
@@ -26,6 +27,7 @@ func NewInventoryHistoryHandler(inventoryHistoryService service.InventoryHistory
 // @Produce json
 // @Param  Authorization header string true "Authorization: Bearer"
 // @Param productId path int true "Product ID"
+// @Param warehouse_id query int false "Only show the history of this warehouse, all warehouses when omitted"
 // @Success 200 {object} httpcommon.HttpResponse[model.GetAllInventoryHistoriesResponse]
 // @Failure 500 {object} httpcommon.HttpResponse[any]
 // @Router /products/{productId}/inventories/histories [get]
@@ -36,7 +38,80 @@ func (h *InventoryHistoryHandler) GetAll(ctx *gin.Context) {
 		ctx.JSON(statusCode, errResponse)
 		return
 	}
-	response, errCode := h.inventoryHistoryService.GetAll(ctx, productID)
+	warehouseID, ok := parseIntQuery(ctx, "warehouse_id")
+	if !ok {
+		return
+	}
+	filterWarehouseID := 0
+	if warehouseID != nil {
+		filterWarehouseID = *warehouseID
+	}
+
+	response, errCode := h.inventoryHistoryService.GetAll(ctx, productID, filterWarehouseID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Inventory Movements
+// @Description Per product, the opening balance, stock in and out split by sales, returns, purchases, transfers and adjustments, and the closing balance for the period. Balances come from the final quantity of the inventory history; is_consistent is false when a history row does not follow from the previous one or the last one does not match the current stock. Defaults to the current month.
+// @Tags Inventory History
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param from query string false "From date (format: YYYY-MM-DD)"
+// @Param to query string false "To date, included (format: YYYY-MM-DD)"
+// @Param product_id query int false "Only report this product"
+// @Param warehouse_id query int false "Only report this warehouse, all warehouses when omitted"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetInventoryMovementsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /inventory/movements [get]
+func (h *InventoryHistoryHandler) GetMovements(ctx *gin.Context) {
+	now := time.Now()
+	toDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	fromDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	from, ok := parseDateQuery(ctx, "from")
+	if !ok {
+		return
+	}
+	if from != nil {
+		fromDate = *from
+	}
+	to, ok := parseDateQuery(ctx, "to")
+	if !ok {
+		return
+	}
+	if to != nil {
+		toDate = *to
+	}
+	if fromDate.After(toDate) {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "from")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	productID, ok := parseIntQuery(ctx, "product_id")
+	if !ok {
+		return
+	}
+	warehouseID, ok := parseIntQuery(ctx, "warehouse_id")
+	if !ok {
+		return
+	}
+	filterProductID, filterWarehouseID := 0, 0
+	if productID != nil {
+		filterProductID = *productID
+	}
+	if warehouseID != nil {
+		filterWarehouseID = *warehouseID
+	}
+
+	response, errCode := h.inventoryHistoryService.GetMovements(ctx, fromDate, toDate, filterProductID, filterWarehouseID)
 	if errCode != "" {
 		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
 		ctx.JSON(statusCode, errResponse)
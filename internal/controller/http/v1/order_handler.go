@@ -3,9 +3,11 @@ package v1
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
 	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
 	"github.com/pna/order-app-backend/internal/domain/model"
 	"github.com/pna/order-app-backend/internal/service"
@@ -87,68 +89,176 @@ func (h *OrderHandler) Update(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
 }
 
+// @Summary Update Order Items
+// @Description Replace the items of an existing order. Inventory is reconciled for items exported from inventory and the order cost/revenue are recalculated.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Param request body model.UpdateOrderItemsRequest true "Full list of desired order items"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 401 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/items [put]
+func (h *OrderHandler) UpdateItems(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdateOrderItemsRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	errCode := h.orderService.UpdateItems(ctx, orderID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
 // @Summary Get All Orders
-// @Description Retrieve all orders with optional filters and sorting
+// @Description Retrieve orders page by page with optional filters, search and sorting. The totals cover every order matching the filters, not only the current page.
 // @Tags Orders
 // @Produce json
 // @Param  Authorization header string true "Authorization: Bearer"
 // @Param customer_id query int false "Filter by customer ID"
 // @Param delivery_statuses query string false "Filter by delivery statuses (comma-separated, e.g., PENDING,DELIVERED)"
+// @Param debt_statuses query string false "Filter by debt statuses (comma-separated, e.g., UNPAID,PARTIAL)"
+// @Param q query string false "Search by customer name or phone"
+// @Param min_amount query int false "Minimum total amount (VND)"
+// @Param max_amount query int false "Maximum total amount (VND)"
 // @Param sort_by query string false "Sort by: order_date_asc, order_date_desc (default: id DESC)"
 // @Param from_date query string false "Filter from date (format: YYYY-MM-DD)"
 // @Param to_date query string false "Filter to date (format: YYYY-MM-DD)"
+// @Param limit query int false "Page size (default: 50, max: 200)"
+// @Param cursor query string false "Cursor of the next page, taken from next_cursor of the previous page"
 // @Success 200 {object} httpcommon.HttpResponse[model.GetAllOrdersResponse]
 // @Failure 400 {object} httpcommon.HttpResponse[any]
 // @Failure 500 {object} httpcommon.HttpResponse[any]
 // @Router /orders [get]
 func (h *OrderHandler) GetAll(ctx *gin.Context) {
+	request, ok := parseGetAllOrdersRequest(ctx)
+	if !ok {
+		return
+	}
+
+	response, errCode := h.orderService.GetAll(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	if !middleware.CanViewCostHelper(ctx) {
+		response.AllOrderTotalProfitLoss = nil
+		for i := range response.Orders {
+			hideOrderCost(&response.Orders[i])
+		}
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(&response))
+}
+
+// parseGetAllOrdersRequest reads the order listing filters from the query string.
+// It writes the error response itself and returns false when a parameter is malformed.
+func parseGetAllOrdersRequest(ctx *gin.Context) (model.GetAllOrdersRequest, bool) {
 	// Get query parameters
 	customerIDStr := ctx.Query("customer_id")
-	deliveryStatuses := ctx.Query("delivery_statuses")
-	sortBy := ctx.Query("sort_by")
 	fromDateStr := ctx.Query("from_date")
 	toDateStr := ctx.Query("to_date")
 
+	request := model.GetAllOrdersRequest{
+		DeliveryStatuses: splitCommaSeparated(ctx.Query("delivery_statuses")),
+		DebtStatuses:     splitCommaSeparated(ctx.Query("debt_statuses")),
+		Search:           strings.TrimSpace(ctx.Query("q")),
+		SortBy:           ctx.Query("sort_by"),
+		Cursor:           ctx.Query("cursor"),
+	}
+
 	// Parse customer ID if provided
-	customerID := 0
 	if customerIDStr != "" {
 		if id, err := strconv.Atoi(customerIDStr); err == nil {
-			customerID = id
+			request.CustomerID = id
 		}
 	}
 
-	// Parse date filters
-	var fromDate *time.Time
-	var toDate *time.Time
+	// Parse amount range and page size
+	if minAmountStr := ctx.Query("min_amount"); minAmountStr != "" {
+		minAmount, err := strconv.Atoi(minAmountStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "min_amount")
+			ctx.JSON(statusCode, errResponse)
+			return request, false
+		}
+		request.MinAmount = &minAmount
+	}
+
+	if maxAmountStr := ctx.Query("max_amount"); maxAmountStr != "" {
+		maxAmount, err := strconv.Atoi(maxAmountStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "max_amount")
+			ctx.JSON(statusCode, errResponse)
+			return request, false
+		}
+		request.MaxAmount = &maxAmount
+	}
+
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "limit")
+			ctx.JSON(statusCode, errResponse)
+			return request, false
+		}
+		request.Limit = limit
+	}
 
+	// Parse date filters
 	if fromDateStr != "" {
 		if parsedDate, err := time.Parse("2006-01-02", fromDateStr); err == nil {
-			fromDate = &parsedDate
+			request.FromDate = &parsedDate
 		} else {
 			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "from_date format should be YYYY-MM-DD")
 			ctx.JSON(statusCode, errResponse)
-			return
+			return request, false
 		}
 	}
 
 	if toDateStr != "" {
 		if parsedDate, err := time.Parse("2006-01-02", toDateStr); err == nil {
-			toDate = &parsedDate
+			request.ToDate = &parsedDate
 		} else {
 			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "to_date format should be YYYY-MM-DD")
 			ctx.JSON(statusCode, errResponse)
-			return
+			return request, false
 		}
 	}
 
-	response, errCode := h.orderService.GetAll(ctx, 0, customerID, deliveryStatuses, sortBy, fromDate, toDate)
-	if errCode != "" {
-		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
-		ctx.JSON(statusCode, errResponse)
-		return
-	}
+	return request, true
+}
 
-	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(&response))
+// splitCommaSeparated splits a comma-separated query parameter, dropping empty values
+func splitCommaSeparated(value string) []string {
+	if value == "" {
+		return nil
+	}
+	values := make([]string, 0)
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
 }
 
 // @Summary Get Order by ID
@@ -178,6 +288,113 @@ func (h *OrderHandler) GetOne(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.CanViewCostHelper(ctx) {
+		hideOrderCost(&response.Order)
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(&response))
+}
+
+// @Summary Update Order Status
+// @Description Move an order to a new delivery status. Only transitions allowed by the order status flow are accepted, and each change is recorded in the status history.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Param request body model.UpdateOrderStatusRequest true "New delivery status"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 401 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/status [post]
+func (h *OrderHandler) UpdateStatus(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdateOrderStatusRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	errCode := h.orderService.UpdateStatus(ctx, orderID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Cancel Order
+// @Description Cancel an order. Goods exported from inventory are put back into inventory and the order is kept with its cancellation reason for audit.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Param request body model.CancelOrderRequest true "Cancellation reason"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 401 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/cancel [post]
+func (h *OrderHandler) Cancel(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.CancelOrderRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	errCode := h.orderService.Cancel(ctx, orderID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Get Order Status History
+// @Description Retrieve every delivery status change of an order
+// @Tags Orders
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllOrderStatusHistoriesResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/status-history [get]
+func (h *OrderHandler) GetStatusHistory(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.orderService.GetStatusHistory(ctx, orderID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
 	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(&response))
 }
 
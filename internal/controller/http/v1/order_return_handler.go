@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	"github.com/pna/order-app-backend/internal/utils/validation"
+)
+
+type OrderReturnHandler struct {
+	orderReturnService service.OrderReturnService
+}
+
+func NewOrderReturnHandler(orderReturnService service.OrderReturnService) *OrderReturnHandler {
+	return &OrderReturnHandler{
+		orderReturnService: orderReturnService,
+	}
+}
+
+// @Summary Record Order Return
+// @Description Record goods the customer sent back. Items exported from inventory are restocked, and the credit note reduces the order's revenue and profit.
+// @Tags Order Returns
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Param request body model.CreateOrderReturnRequest true "Returned order items"
+// @Success 201 {object} httpcommon.HttpResponse[model.OrderReturnResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 401 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/returns [post]
+func (h *OrderReturnHandler) Create(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.CreateOrderReturnRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.orderReturnService.Create(ctx, orderID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	if !middleware.CanViewCostHelper(ctx) {
+		hideOrderReturnCost(response)
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Order Returns
+// @Description Retrieve all returns of an order with their returned items
+// @Tags Order Returns
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllOrderReturnsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/returns [get]
+func (h *OrderReturnHandler) GetAllByOrderID(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.orderReturnService.GetAllByOrderID(ctx, orderID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	if !middleware.CanViewCostHelper(ctx) {
+		response.TotalReturnedCost = nil
+		for i := range response.Returns {
+			hideOrderReturnCost(&response.Returns[i])
+		}
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
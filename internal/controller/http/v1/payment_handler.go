@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	"github.com/pna/order-app-backend/internal/utils/validation"
+)
+
+type PaymentHandler struct {
+	paymentService service.PaymentService
+}
+
+func NewPaymentHandler(paymentService service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+	}
+}
+
+// @Summary Record Payment
+// @Description Record a partial or full payment against an order. The order debt status is derived automatically.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Param request body model.CreatePaymentRequest true "Payment information"
+// @Success 201 {object} httpcommon.HttpResponse[model.PaymentResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 401 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/payments [post]
+func (h *PaymentHandler) Create(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.CreatePaymentRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.paymentService.Create(ctx, orderID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Order Payments
+// @Description Retrieve all payments of an order together with its outstanding balance
+// @Tags Payments
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllPaymentsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/payments [get]
+func (h *PaymentHandler) GetAllByOrderID(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.paymentService.GetAllByOrderID(ctx, orderID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Delete Payment
+// @Description Delete a payment recorded by mistake. The order debt status is derived again.
+// @Tags Payments
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Param paymentId path int true "Payment ID"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/payments/{paymentId} [delete]
+func (h *PaymentHandler) Delete(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	paymentID, err := strconv.Atoi(ctx.Param("paymentId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "paymentId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	errCode := h.paymentService.Delete(ctx, orderID, paymentID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Get Customer Balance
+// @Description Summarize total, paid and outstanding amounts of all orders of a customer, with the list of orders still owing
+// @Tags Payments
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param customerId path int true "Customer ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.CustomerBalanceResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /customers/{customerId}/balance [get]
+func (h *PaymentHandler) GetCustomerBalance(ctx *gin.Context) {
+	customerID, err := strconv.Atoi(ctx.Param("customerId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "customerId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.paymentService.GetCustomerBalance(ctx, customerID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
@@ -0,0 +1,264 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	"github.com/pna/order-app-backend/internal/utils/validation"
+)
+
+type PriceListHandler struct {
+	priceListService service.PriceListService
+}
+
+func NewPriceListHandler(priceListService service.PriceListService) *PriceListHandler {
+	return &PriceListHandler{
+		priceListService: priceListService,
+	}
+}
+
+// @Summary Create Product Price
+// @Description Add a selling price of a product starting on a date. Without customer_id it is the default price of the product, otherwise it overrides the default price for that customer. A price keeps applying until a later entry takes effect.
+// @Tags Price Lists
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreateProductPriceRequest true "Product price"
+// @Success 201 {object} httpcommon.HttpResponse[model.ProductPriceResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 409 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /price-lists/prices [post]
+func (h *PriceListHandler) CreateProductPrice(ctx *gin.Context) {
+	var request model.CreateProductPriceRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.priceListService.CreateProductPrice(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Product Prices
+// @Description Retrieve price list entries, newest effective date first
+// @Tags Price Lists
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param product_id query int false "Filter by product ID"
+// @Param customer_id query int false "Filter by customer ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllProductPricesResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /price-lists/prices [get]
+func (h *PriceListHandler) GetAllProductPrices(ctx *gin.Context) {
+	productID, ok := parseIntQuery(ctx, "product_id")
+	if !ok {
+		return
+	}
+	customerID, ok := parseIntQuery(ctx, "customer_id")
+	if !ok {
+		return
+	}
+
+	filterProductID, filterCustomerID := 0, 0
+	if productID != nil {
+		filterProductID = *productID
+	}
+	if customerID != nil {
+		filterCustomerID = *customerID
+	}
+
+	response, errCode := h.priceListService.GetAllProductPrices(ctx, filterProductID, filterCustomerID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Delete Product Price
+// @Description Remove a price list entry, the previous entry applies again from its effective date
+// @Tags Price Lists
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param priceId path int true "Product price ID"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /price-lists/prices/{priceId} [delete]
+func (h *PriceListHandler) DeleteProductPrice(ctx *gin.Context) {
+	priceID, err := strconv.Atoi(ctx.Param("priceId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "priceId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	errCode := h.priceListService.DeleteProductPrice(ctx, priceID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Create Customer Discount
+// @Description Add the default discount percentage a customer gets on default prices starting on a date. It does not apply to customer-specific prices.
+// @Tags Price Lists
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreateCustomerDiscountRequest true "Customer discount"
+// @Success 201 {object} httpcommon.HttpResponse[model.CustomerDiscountResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 409 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /price-lists/discounts [post]
+func (h *PriceListHandler) CreateCustomerDiscount(ctx *gin.Context) {
+	var request model.CreateCustomerDiscountRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.priceListService.CreateCustomerDiscount(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Customer Discounts
+// @Description Retrieve default discount entries, newest effective date first
+// @Tags Price Lists
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param customer_id query int false "Filter by customer ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllCustomerDiscountsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /price-lists/discounts [get]
+func (h *PriceListHandler) GetAllCustomerDiscounts(ctx *gin.Context) {
+	customerID, ok := parseIntQuery(ctx, "customer_id")
+	if !ok {
+		return
+	}
+
+	filterCustomerID := 0
+	if customerID != nil {
+		filterCustomerID = *customerID
+	}
+
+	response, errCode := h.priceListService.GetAllCustomerDiscounts(ctx, filterCustomerID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Delete Customer Discount
+// @Description Remove a default discount entry, the previous entry applies again from its effective date
+// @Tags Price Lists
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param discountId path int true "Customer discount ID"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /price-lists/discounts/{discountId} [delete]
+func (h *PriceListHandler) DeleteCustomerDiscount(ctx *gin.Context) {
+	discountID, err := strconv.Atoi(ctx.Param("discountId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "discountId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	errCode := h.priceListService.DeleteCustomerDiscount(ctx, discountID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Get Suggested Price
+// @Description Suggest the selling price and discount of a product for a customer, to prefill the order form. A customer-specific price is suggested without discount; otherwise the default price is suggested with the customer's default discount. price_type is NONE when the product has no price yet.
+// @Tags Price Lists
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param customer_id query int true "Customer ID"
+// @Param product_id query int true "Product ID"
+// @Param date query string false "Order date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} httpcommon.HttpResponse[model.SuggestedPriceResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /price-lists/suggestion [get]
+func (h *PriceListHandler) GetSuggestedPrice(ctx *gin.Context) {
+	customerID, ok := parseIntQuery(ctx, "customer_id")
+	if !ok {
+		return
+	}
+	if customerID == nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "customer_id")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	productID, ok := parseIntQuery(ctx, "product_id")
+	if !ok {
+		return
+	}
+	if productID == nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "product_id")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	date, ok := parseDateQuery(ctx, "date")
+	if !ok {
+		return
+	}
+	if date == nil {
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		date = &today
+	}
+
+	response, errCode := h.priceListService.GetSuggestedPrice(ctx, *customerID, *productID, *date)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
 	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
 	"github.com/pna/order-app-backend/internal/domain/model"
 	"github.com/pna/order-app-backend/internal/service"
@@ -93,6 +94,12 @@ func (h *ProductHandler) GetAll(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.CanViewCostHelper(ctx) {
+		for i := range response.Products {
+			hideProductCost(&response.Products[i])
+		}
+	}
+
 	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
 }
 
@@ -123,5 +130,38 @@ func (h *ProductHandler) GetOne(ctx *gin.Context) {
 		return
 	}
 
+	if !middleware.CanViewCostHelper(ctx) {
+		hideProductCost(&response.Product)
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Product Cost Histories
+// @Description Retrieve the current weighted-average cost of a product and every receipt that moved it, newest first
+// @Tags Products
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param productId path int true "Product ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetProductCostHistoriesResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /products/{productId}/cost-histories [get]
+func (h *ProductHandler) GetCostHistories(ctx *gin.Context) {
+	productID, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "productId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.productService.GetCostHistories(ctx, productID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
 	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
 }
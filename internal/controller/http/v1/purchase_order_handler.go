@@ -0,0 +1,194 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	"github.com/pna/order-app-backend/internal/utils/validation"
+)
+
+type PurchaseOrderHandler struct {
+	purchaseOrderService service.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(purchaseOrderService service.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		purchaseOrderService: purchaseOrderService,
+	}
+}
+
+// @Summary Create Purchase Order
+// @Description Order goods from a supplier. Stock does not change until the goods are received.
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreatePurchaseOrderRequest true "Supplier and ordered products"
+// @Success 201 {object} httpcommon.HttpResponse[model.PurchaseOrderResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /purchase-orders [post]
+func (h *PurchaseOrderHandler) Create(ctx *gin.Context) {
+	var request model.CreatePurchaseOrderRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.purchaseOrderService.Create(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Purchase Orders
+// @Description Retrieve purchase orders, newest first, without their lines
+// @Tags Purchase Orders
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param supplier_id query int false "Filter by supplier ID"
+// @Param statuses query string false "Filter by statuses (comma-separated, e.g., PENDING,PARTIALLY_RECEIVED)"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllPurchaseOrdersResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /purchase-orders [get]
+func (h *PurchaseOrderHandler) GetAll(ctx *gin.Context) {
+	supplierID := 0
+	if supplierIDStr := ctx.Query("supplier_id"); supplierIDStr != "" {
+		id, err := strconv.Atoi(supplierIDStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "supplier_id")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		supplierID = id
+	}
+
+	response, errCode := h.purchaseOrderService.GetAll(ctx, supplierID, splitCommaSeparated(ctx.Query("statuses")))
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	if !middleware.CanViewCostHelper(ctx) {
+		for i := range response.PurchaseOrders {
+			hidePurchaseOrderCost(&response.PurchaseOrders[i])
+		}
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Purchase Order by ID
+// @Description Retrieve a purchase order with its lines and how much of each has been received
+// @Tags Purchase Orders
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param purchaseOrderId path int true "Purchase order ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.PurchaseOrderResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /purchase-orders/{purchaseOrderId} [get]
+func (h *PurchaseOrderHandler) GetOne(ctx *gin.Context) {
+	purchaseOrderID, err := strconv.Atoi(ctx.Param("purchaseOrderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "purchaseOrderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.purchaseOrderService.GetOne(ctx, purchaseOrderID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	if !middleware.CanViewCostHelper(ctx) {
+		hidePurchaseOrderCost(response)
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Receive Purchase Order
+// @Description Receive some or all of the ordered goods into inventory. Each product's stock goes up and an inventory history row referencing the purchase order is written. With update_original_price the products' original price is set to the purchase price (owner and accountant only).
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param purchaseOrderId path int true "Purchase order ID"
+// @Param request body model.ReceivePurchaseOrderRequest true "Received quantities"
+// @Success 200 {object} httpcommon.HttpResponse[model.PurchaseOrderResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 403 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /purchase-orders/{purchaseOrderId}/receive [post]
+func (h *PurchaseOrderHandler) Receive(ctx *gin.Context) {
+	purchaseOrderID, err := strconv.Atoi(ctx.Param("purchaseOrderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "purchaseOrderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.ReceivePurchaseOrderRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.purchaseOrderService.Receive(ctx, purchaseOrderID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	if !middleware.CanViewCostHelper(ctx) {
+		hidePurchaseOrderCost(response)
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Cancel Purchase Order
+// @Description Stop waiting for the goods not received yet. Goods already received stay in inventory.
+// @Tags Purchase Orders
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param purchaseOrderId path int true "Purchase order ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.PurchaseOrderResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /purchase-orders/{purchaseOrderId}/cancel [post]
+func (h *PurchaseOrderHandler) Cancel(ctx *gin.Context) {
+	purchaseOrderID, err := strconv.Atoi(ctx.Param("purchaseOrderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "purchaseOrderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.purchaseOrderService.Cancel(ctx, purchaseOrderID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+)
+
+// parseDateQuery reads an optional YYYY-MM-DD query parameter.
+// It writes the error response itself and returns false when the value is malformed.
+func parseDateQuery(ctx *gin.Context, name string) (*time.Time, bool) {
+	dateStr := ctx.Query(name)
+	if dateStr == "" {
+		return nil, true
+	}
+
+	parsedDate, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, name+" format should be YYYY-MM-DD")
+		ctx.JSON(statusCode, errResponse)
+		return nil, false
+	}
+	return &parsedDate, true
+}
+
+// parseIntQuery reads an optional integer query parameter.
+// It writes the error response itself and returns false when the value is malformed.
+func parseIntQuery(ctx *gin.Context, name string) (*int, bool) {
+	valueStr := ctx.Query(name)
+	if valueStr == "" {
+		return nil, true
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, name)
+		ctx.JSON(statusCode, errResponse)
+		return nil, false
+	}
+	return &value, true
+}
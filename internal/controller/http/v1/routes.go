@@ -3,6 +3,7 @@ package v1
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	"github.com/pna/order-app-backend/internal/domain/entity"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -18,10 +19,33 @@ func MapRoutes(router *gin.Engine,
 	orderHandler *OrderHandler,
 	orderImageHandler *OrderImageHandler,
 	statisticsHandler *StatisticsHandler,
+	paymentHandler *PaymentHandler,
+	orderReturnHandler *OrderReturnHandler,
+	exportHandler *ExportHandler,
+	documentHandler *DocumentHandler,
+	importHandler *ImportHandler,
+	auditLogHandler *AuditLogHandler,
+	supplierHandler *SupplierHandler,
+	purchaseOrderHandler *PurchaseOrderHandler,
+	warehouseHandler *WarehouseHandler,
+	stockTransferHandler *StockTransferHandler,
+	stocktakeHandler *StocktakeHandler,
+	priceListHandler *PriceListHandler,
 	authMiddleware *middleware.AuthMiddleware,
 ) {
 	// Apply CORS middleware to all routes
 	router.Use(middleware.CorsMiddleware())
+	router.Use(middleware.RequestIdMiddleware())
+
+	// Role checks, applied after VerifyAccessToken; routes without one are open to every signed-in user.
+	// Only OWNER and ACCOUNTANT see original prices and profit, see middleware.CanViewCostHelper
+	owners := authMiddleware.RequireRoles(entity.UserRole.OWNER)
+	costManagers := authMiddleware.RequireRoles(entity.UserRole.OWNER, entity.UserRole.ACCOUNTANT)
+	sellers := authMiddleware.RequireRoles(entity.UserRole.OWNER, entity.UserRole.SALES)
+	stockKeepers := authMiddleware.RequireRoles(entity.UserRole.OWNER, entity.UserRole.WAREHOUSE)
+	deliveryStaff := authMiddleware.RequireRoles(entity.UserRole.OWNER, entity.UserRole.SALES, entity.UserRole.WAREHOUSE)
+	cashiers := authMiddleware.RequireRoles(entity.UserRole.OWNER, entity.UserRole.SALES, entity.UserRole.ACCOUNTANT)
+	purchasers := authMiddleware.RequireRoles(entity.UserRole.OWNER, entity.UserRole.WAREHOUSE, entity.UserRole.ACCOUNTANT)
 
 	v1 := router.Group("/api/v1")
 	{
@@ -36,43 +60,136 @@ func MapRoutes(router *gin.Engine,
 		users := v1.Group("/users")
 		{
 			users.POST("/login", userHandler.Login)
+			users.POST("/refresh", userHandler.Refresh)
+			users.POST("/logout", userHandler.Logout)
+			users.PUT("/me/password", authMiddleware.VerifyAccessToken, userHandler.ChangePassword)
+			users.POST("", authMiddleware.VerifyAccessToken, owners, userHandler.Create)
+			users.GET("", authMiddleware.VerifyAccessToken, owners, userHandler.GetAll)
+			users.GET("/login-attempts", authMiddleware.VerifyAccessToken, owners, userHandler.GetLoginAttempts)
+			users.PUT("/:userId/status", authMiddleware.VerifyAccessToken, owners, userHandler.UpdateStatus)
+			users.PUT("/:userId/password", authMiddleware.VerifyAccessToken, owners, userHandler.ResetPassword)
 		}
 		products := v1.Group("/products")
 		{
-			products.POST("", authMiddleware.VerifyAccessToken, productHandler.Create)
-			products.PUT("", authMiddleware.VerifyAccessToken, productHandler.Update)
+			products.POST("", authMiddleware.VerifyAccessToken, costManagers, productHandler.Create)
+			products.PUT("", authMiddleware.VerifyAccessToken, costManagers, productHandler.Update)
+			products.POST("/import", authMiddleware.VerifyAccessToken, owners, importHandler.ImportProducts)
 			products.GET("", authMiddleware.VerifyAccessToken, productHandler.GetAll)
 			products.GET("/:productId", authMiddleware.VerifyAccessToken, productHandler.GetOne)
 			products.GET("/:productId/inventories", authMiddleware.VerifyAccessToken, inventoryHandler.GetByProductID)
-			products.PUT("/:productId/inventories/quantity", authMiddleware.VerifyAccessToken, inventoryHandler.UpdateQuantity)
+			products.PUT("/:productId/inventories/quantity", authMiddleware.VerifyAccessToken, stockKeepers, inventoryHandler.UpdateQuantity)
 			products.GET("/:productId/inventories/histories", authMiddleware.VerifyAccessToken, inventoryHistoryHandler.GetAll)
+			products.GET("/:productId/cost-histories", authMiddleware.VerifyAccessToken, costManagers, productHandler.GetCostHistories)
 		}
 		customers := v1.Group("/customers")
 		{
-			customers.POST("", authMiddleware.VerifyAccessToken, customerHandler.Create)
-			customers.PUT("/:customerId", authMiddleware.VerifyAccessToken, customerHandler.Update)
+			customers.POST("", authMiddleware.VerifyAccessToken, sellers, customerHandler.Create)
+			customers.PUT("/:customerId", authMiddleware.VerifyAccessToken, sellers, customerHandler.Update)
+			customers.POST("/import", authMiddleware.VerifyAccessToken, sellers, importHandler.ImportCustomers)
 			customers.GET("", authMiddleware.VerifyAccessToken, customerHandler.GetAll)
+			customers.GET("/duplicates", authMiddleware.VerifyAccessToken, sellers, customerHandler.GetDuplicates)
+			customers.POST("/:customerId/merge", authMiddleware.VerifyAccessToken, owners, customerHandler.Merge)
+			customers.DELETE("/:customerId", authMiddleware.VerifyAccessToken, sellers, customerHandler.Delete)
 			customers.GET("/:customerId", authMiddleware.VerifyAccessToken, customerHandler.GetOne)
+			customers.GET("/:customerId/summary", authMiddleware.VerifyAccessToken, customerHandler.GetSummary)
+			customers.GET("/:customerId/balance", authMiddleware.VerifyAccessToken, cashiers, paymentHandler.GetCustomerBalance)
 		}
 		orders := v1.Group("/orders")
 		{
-			orders.POST("", authMiddleware.VerifyAccessToken, orderHandler.Create)
-			orders.PUT("/:orderId", authMiddleware.VerifyAccessToken, orderHandler.Update)
+			orders.POST("", authMiddleware.VerifyAccessToken, sellers, orderHandler.Create)
+			orders.PUT("/:orderId", authMiddleware.VerifyAccessToken, sellers, orderHandler.Update)
+			orders.PUT("/:orderId/items", authMiddleware.VerifyAccessToken, sellers, orderHandler.UpdateItems)
+			orders.POST("/:orderId/status", authMiddleware.VerifyAccessToken, deliveryStaff, orderHandler.UpdateStatus)
+			orders.GET("/:orderId/status-history", authMiddleware.VerifyAccessToken, orderHandler.GetStatusHistory)
+			orders.POST("/:orderId/cancel", authMiddleware.VerifyAccessToken, sellers, orderHandler.Cancel)
 			orders.GET("", authMiddleware.VerifyAccessToken, orderHandler.GetAll)
+			orders.GET("/export", authMiddleware.VerifyAccessToken, costManagers, exportHandler.ExportOrders)
 			orders.GET("/:orderId", authMiddleware.VerifyAccessToken, orderHandler.GetOne)
-			orders.DELETE("/:orderId", authMiddleware.VerifyAccessToken, orderHandler.Delete)
+			orders.DELETE("/:orderId", authMiddleware.VerifyAccessToken, owners, orderHandler.Delete)
 
 			// Order images endpoints
-			orders.POST("/:orderId/images/upload-url", authMiddleware.VerifyAccessToken, orderImageHandler.GenerateSignedUploadURL)
-			orders.DELETE("/:orderId/images/:imageId", authMiddleware.VerifyAccessToken, orderImageHandler.DeleteImage)
+			orders.POST("/:orderId/images/upload-url", authMiddleware.VerifyAccessToken, deliveryStaff, orderImageHandler.GenerateSignedUploadURL)
+			orders.DELETE("/:orderId/images/:imageId", authMiddleware.VerifyAccessToken, deliveryStaff, orderImageHandler.DeleteImage)
+
+			// Payments endpoints
+			orders.POST("/:orderId/payments", authMiddleware.VerifyAccessToken, cashiers, paymentHandler.Create)
+			orders.GET("/:orderId/payments", authMiddleware.VerifyAccessToken, cashiers, paymentHandler.GetAllByOrderID)
+			orders.DELETE("/:orderId/payments/:paymentId", authMiddleware.VerifyAccessToken, costManagers, paymentHandler.Delete)
+
+			// Returns endpoints
+			orders.POST("/:orderId/returns", authMiddleware.VerifyAccessToken, sellers, orderReturnHandler.Create)
+			orders.GET("/:orderId/returns", authMiddleware.VerifyAccessToken, orderReturnHandler.GetAllByOrderID)
+
+			// Printable documents
+			orders.GET("/:orderId/invoice.pdf", authMiddleware.VerifyAccessToken, cashiers, documentHandler.RenderInvoice)
+			orders.GET("/:orderId/delivery-note.pdf", authMiddleware.VerifyAccessToken, documentHandler.RenderDeliveryNote)
 		}
 		inventory := v1.Group("/inventory")
 		{
 			inventory.GET("", authMiddleware.VerifyAccessToken, inventoryHandler.GetAll)
+			inventory.GET("/export", authMiddleware.VerifyAccessToken, costManagers, exportHandler.ExportInventory)
+			inventory.GET("/low-stock", authMiddleware.VerifyAccessToken, purchasers, inventoryHandler.GetLowStock)
+			inventory.GET("/movements", authMiddleware.VerifyAccessToken, purchasers, inventoryHistoryHandler.GetMovements)
 		}
 		statistics := v1.Group("/statistics")
 		{
 			statistics.GET("/dashboard", authMiddleware.VerifyAccessToken, statisticsHandler.GetDashboardStats)
+			statistics.GET("/revenue", authMiddleware.VerifyAccessToken, costManagers, statisticsHandler.GetReportByPeriod)
+			statistics.GET("/customers", authMiddleware.VerifyAccessToken, costManagers, statisticsHandler.GetReportByCustomer)
+			statistics.GET("/products", authMiddleware.VerifyAccessToken, costManagers, statisticsHandler.GetReportByProduct)
+			statistics.GET("/revenue/export", authMiddleware.VerifyAccessToken, costManagers, exportHandler.ExportReportByPeriod)
+			statistics.GET("/customers/export", authMiddleware.VerifyAccessToken, costManagers, exportHandler.ExportReportByCustomer)
+			statistics.GET("/products/export", authMiddleware.VerifyAccessToken, costManagers, exportHandler.ExportReportByProduct)
+		}
+		suppliers := v1.Group("/suppliers")
+		{
+			suppliers.POST("", authMiddleware.VerifyAccessToken, purchasers, supplierHandler.Create)
+			suppliers.GET("", authMiddleware.VerifyAccessToken, purchasers, supplierHandler.GetAll)
+			suppliers.GET("/:supplierId", authMiddleware.VerifyAccessToken, purchasers, supplierHandler.GetOne)
+			suppliers.PUT("/:supplierId", authMiddleware.VerifyAccessToken, purchasers, supplierHandler.Update)
+		}
+		// Purchase prices are costs, so only cost managers place or cancel purchase orders; warehouse staff receive the goods
+		purchaseOrders := v1.Group("/purchase-orders")
+		{
+			purchaseOrders.POST("", authMiddleware.VerifyAccessToken, costManagers, purchaseOrderHandler.Create)
+			purchaseOrders.GET("", authMiddleware.VerifyAccessToken, purchasers, purchaseOrderHandler.GetAll)
+			purchaseOrders.GET("/:purchaseOrderId", authMiddleware.VerifyAccessToken, purchasers, purchaseOrderHandler.GetOne)
+			purchaseOrders.POST("/:purchaseOrderId/receive", authMiddleware.VerifyAccessToken, purchasers, purchaseOrderHandler.Receive)
+			purchaseOrders.POST("/:purchaseOrderId/cancel", authMiddleware.VerifyAccessToken, costManagers, purchaseOrderHandler.Cancel)
+		}
+		warehouses := v1.Group("/warehouses")
+		{
+			warehouses.POST("", authMiddleware.VerifyAccessToken, owners, warehouseHandler.Create)
+			warehouses.GET("", authMiddleware.VerifyAccessToken, warehouseHandler.GetAll)
+			warehouses.PUT("/:warehouseId", authMiddleware.VerifyAccessToken, owners, warehouseHandler.Update)
+		}
+		stockTransfers := v1.Group("/stock-transfers")
+		{
+			stockTransfers.POST("", authMiddleware.VerifyAccessToken, stockKeepers, stockTransferHandler.Create)
+			stockTransfers.GET("", authMiddleware.VerifyAccessToken, stockKeepers, stockTransferHandler.GetAll)
+		}
+		stocktakes := v1.Group("/stocktakes")
+		{
+			stocktakes.POST("", authMiddleware.VerifyAccessToken, stockKeepers, stocktakeHandler.Open)
+			stocktakes.GET("", authMiddleware.VerifyAccessToken, stockKeepers, stocktakeHandler.GetAll)
+			stocktakes.GET("/:stocktakeId", authMiddleware.VerifyAccessToken, stockKeepers, stocktakeHandler.GetOne)
+			stocktakes.PUT("/:stocktakeId/counts", authMiddleware.VerifyAccessToken, stockKeepers, stocktakeHandler.UpdateCounts)
+			stocktakes.POST("/:stocktakeId/post", authMiddleware.VerifyAccessToken, stockKeepers, stocktakeHandler.Post)
+			stocktakes.POST("/:stocktakeId/cancel", authMiddleware.VerifyAccessToken, stockKeepers, stocktakeHandler.Cancel)
+		}
+		priceLists := v1.Group("/price-lists")
+		{
+			priceLists.POST("/prices", authMiddleware.VerifyAccessToken, sellers, priceListHandler.CreateProductPrice)
+			priceLists.GET("/prices", authMiddleware.VerifyAccessToken, priceListHandler.GetAllProductPrices)
+			priceLists.DELETE("/prices/:priceId", authMiddleware.VerifyAccessToken, sellers, priceListHandler.DeleteProductPrice)
+			priceLists.POST("/discounts", authMiddleware.VerifyAccessToken, sellers, priceListHandler.CreateCustomerDiscount)
+			priceLists.GET("/discounts", authMiddleware.VerifyAccessToken, priceListHandler.GetAllCustomerDiscounts)
+			priceLists.DELETE("/discounts/:discountId", authMiddleware.VerifyAccessToken, sellers, priceListHandler.DeleteCustomerDiscount)
+			priceLists.GET("/suggestion", authMiddleware.VerifyAccessToken, priceListHandler.GetSuggestedPrice)
+		}
+		auditLogs := v1.Group("/audit-logs")
+		{
+			auditLogs.GET("", authMiddleware.VerifyAccessToken, owners, auditLogHandler.GetAll)
 		}
 	}
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
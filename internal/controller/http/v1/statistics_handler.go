@@ -2,6 +2,7 @@ package v1
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
@@ -42,3 +43,116 @@ func (h *StatisticsHandler) GetDashboardStats(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(&stats))
 }
+
+// @Summary Get revenue report by period
+// @Description Revenue, original cost, additional cost, tax and profit/loss of non-cancelled orders grouped by day, week or month. Defaults to the current month.
+// @Tags Statistics
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param group_by query string false "Group by: day, week, month (default: day)"
+// @Param from_date query string false "From date (format: YYYY-MM-DD)"
+// @Param to_date query string false "To date (format: YYYY-MM-DD)"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetPeriodReportResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /statistics/revenue [get]
+func (h *StatisticsHandler) GetReportByPeriod(ctx *gin.Context) {
+	fromDate, toDate, ok := parseReportDateRange(ctx)
+	if !ok {
+		return
+	}
+
+	groupBy := ctx.DefaultQuery("group_by", "day")
+
+	report, err := h.statisticsService.GetReportByPeriod(ctx.Request.Context(), groupBy, fromDate, toDate)
+	if err != "" {
+		log.Error("StatisticsHandler.GetReportByPeriod Error: " + err)
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(err, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(&report))
+}
+
+// @Summary Get revenue report by customer
+// @Description Revenue, original cost, additional cost, tax and profit/loss of non-cancelled orders per customer, most profitable first. Defaults to the current month.
+// @Tags Statistics
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param from_date query string false "From date (format: YYYY-MM-DD)"
+// @Param to_date query string false "To date (format: YYYY-MM-DD)"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetCustomerReportResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /statistics/customers [get]
+func (h *StatisticsHandler) GetReportByCustomer(ctx *gin.Context) {
+	fromDate, toDate, ok := parseReportDateRange(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := h.statisticsService.GetReportByCustomer(ctx.Request.Context(), fromDate, toDate)
+	if err != "" {
+		log.Error("StatisticsHandler.GetReportByCustomer Error: " + err)
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(err, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(&report))
+}
+
+// @Summary Get revenue report by product
+// @Description Quantity, revenue, original cost and profit/loss of non-cancelled orders per product, valued at the original price snapshot of each order item. Additional cost and tax belong to whole orders and are not split across products. Defaults to the current month.
+// @Tags Statistics
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param from_date query string false "From date (format: YYYY-MM-DD)"
+// @Param to_date query string false "To date (format: YYYY-MM-DD)"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetProductReportResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /statistics/products [get]
+func (h *StatisticsHandler) GetReportByProduct(ctx *gin.Context) {
+	fromDate, toDate, ok := parseReportDateRange(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := h.statisticsService.GetReportByProduct(ctx.Request.Context(), fromDate, toDate)
+	if err != "" {
+		log.Error("StatisticsHandler.GetReportByProduct Error: " + err)
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(err, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(&report))
+}
+
+// parseReportDateRange reads the from_date and to_date query parameters, defaulting to the current month up to today.
+// It writes the error response itself and returns false when a date is malformed.
+func parseReportDateRange(ctx *gin.Context) (fromDate time.Time, toDate time.Time, ok bool) {
+	now := time.Now()
+	toDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	fromDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	parsedFromDate, ok := parseDateQuery(ctx, "from_date")
+	if !ok {
+		return fromDate, toDate, false
+	}
+	if parsedFromDate != nil {
+		fromDate = *parsedFromDate
+	}
+
+	parsedToDate, ok := parseDateQuery(ctx, "to_date")
+	if !ok {
+		return fromDate, toDate, false
+	}
+	if parsedToDate != nil {
+		toDate = *parsedToDate
+	}
+
+	return fromDate, toDate, true
+}
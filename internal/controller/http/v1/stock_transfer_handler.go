@@ -0,0 +1,89 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	"github.com/pna/order-app-backend/internal/utils/validation"
+)
+
+type StockTransferHandler struct {
+	stockTransferService service.StockTransferService
+}
+
+func NewStockTransferHandler(stockTransferService service.StockTransferService) *StockTransferHandler {
+	return &StockTransferHandler{
+		stockTransferService: stockTransferService,
+	}
+}
+
+// @Summary Create Stock Transfer
+// @Description Move a quantity of a product from one warehouse to another, recorded as a pair of inventory history rows
+// @Tags Stock Transfers
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreateStockTransferRequest true "Stock transfer information"
+// @Success 201 {object} httpcommon.HttpResponse[model.StockTransferResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /stock-transfers [post]
+func (h *StockTransferHandler) Create(ctx *gin.Context) {
+	var request model.CreateStockTransferRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.stockTransferService.Create(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Stock Transfers
+// @Description Retrieve stock transfers, newest first
+// @Tags Stock Transfers
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param product_id query int false "Filter by product ID"
+// @Param warehouse_id query int false "Filter by source or destination warehouse ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllStockTransfersResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /stock-transfers [get]
+func (h *StockTransferHandler) GetAll(ctx *gin.Context) {
+	productID, ok := parseIntQuery(ctx, "product_id")
+	if !ok {
+		return
+	}
+	warehouseID, ok := parseIntQuery(ctx, "warehouse_id")
+	if !ok {
+		return
+	}
+
+	filterProductID, filterWarehouseID := 0, 0
+	if productID != nil {
+		filterProductID = *productID
+	}
+	if warehouseID != nil {
+		filterWarehouseID = *warehouseID
+	}
+
+	response, errCode := h.stockTransferService.GetAll(ctx, filterProductID, filterWarehouseID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
@@ -0,0 +1,235 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	"github.com/pna/order-app-backend/internal/utils/validation"
+)
+
+type StocktakeHandler struct {
+	stocktakeService service.StocktakeService
+}
+
+func NewStocktakeHandler(stocktakeService service.StocktakeService) *StocktakeHandler {
+	return &StocktakeHandler{
+		stocktakeService: stocktakeService,
+	}
+}
+
+// @Summary Open Stocktake
+// @Description Start a physical count of a warehouse. The book quantity of every product in the warehouse is snapshotted; stock does not change until the stocktake is posted. A warehouse can have one open stocktake at a time.
+// @Tags Stocktakes
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.OpenStocktakeRequest true "Warehouse to count"
+// @Success 201 {object} httpcommon.HttpResponse[model.StocktakeResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 409 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /stocktakes [post]
+func (h *StocktakeHandler) Open(ctx *gin.Context) {
+	var request model.OpenStocktakeRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.stocktakeService.Open(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Stocktakes
+// @Description Retrieve stocktakes, newest first, without their items
+// @Tags Stocktakes
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param warehouse_id query int false "Filter by warehouse ID"
+// @Param statuses query string false "Filter by statuses (comma-separated, e.g., OPEN,POSTED)"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllStocktakesResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /stocktakes [get]
+func (h *StocktakeHandler) GetAll(ctx *gin.Context) {
+	warehouseID, ok := parseIntQuery(ctx, "warehouse_id")
+	if !ok {
+		return
+	}
+
+	filterWarehouseID := 0
+	if warehouseID != nil {
+		filterWarehouseID = *warehouseID
+	}
+
+	response, errCode := h.stocktakeService.GetAll(ctx, filterWarehouseID, splitCommaSeparated(ctx.Query("statuses")))
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Stocktake by ID
+// @Description Retrieve a stocktake with the book and counted quantity of each product and their variance. While the stocktake is open, products whose stock moved after they were counted are flagged with needs_recount.
+// @Tags Stocktakes
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param stocktakeId path int true "Stocktake ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.StocktakeResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /stocktakes/{stocktakeId} [get]
+func (h *StocktakeHandler) GetOne(ctx *gin.Context) {
+	stocktakeID, err := strconv.Atoi(ctx.Param("stocktakeId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "stocktakeId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.stocktakeService.GetOne(ctx, stocktakeID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Enter Stocktake Counts
+// @Description Record the counted quantity of some products. Can be called as many times as needed while the stocktake is open; counting a product again replaces its previous count and compares it against the current book quantity.
+// @Tags Stocktakes
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param stocktakeId path int true "Stocktake ID"
+// @Param request body model.UpdateStocktakeCountsRequest true "Counted quantities"
+// @Success 200 {object} httpcommon.HttpResponse[model.StocktakeResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /stocktakes/{stocktakeId}/counts [put]
+func (h *StocktakeHandler) UpdateCounts(ctx *gin.Context) {
+	stocktakeID, err := strconv.Atoi(ctx.Param("stocktakeId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "stocktakeId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdateStocktakeCountsRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.stocktakeService.UpdateCounts(ctx, stocktakeID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Post Stocktake
+// @Description Adjust the stock of every counted product by its variance in one go, writing an inventory history row referencing the stocktake. Uncounted products are left as they are. If the stock of a counted product moved after it was counted, nothing is adjusted and one error per product to count again is returned. Likewise nothing is adjusted while a product is counted below the quantity reserved for pending orders.
+// @Tags Stocktakes
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param stocktakeId path int true "Stocktake ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.StocktakeResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 409 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /stocktakes/{stocktakeId}/post [post]
+func (h *StocktakeHandler) Post(ctx *gin.Context) {
+	stocktakeID, err := strconv.Atoi(ctx.Param("stocktakeId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "stocktakeId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.stocktakeService.Post(ctx, stocktakeID)
+	if errCode != "" {
+		writeStocktakeError(ctx, errCode, response)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Cancel Stocktake
+// @Description Close an open stocktake without touching the stock
+// @Tags Stocktakes
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param stocktakeId path int true "Stocktake ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.StocktakeResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /stocktakes/{stocktakeId}/cancel [post]
+func (h *StocktakeHandler) Cancel(ctx *gin.Context) {
+	stocktakeID, err := strconv.Atoi(ctx.Param("stocktakeId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "stocktakeId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.stocktakeService.Cancel(ctx, stocktakeID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// writeStocktakeError reports the products to count again one error each, or the plain error response
+func writeStocktakeError(ctx *gin.Context, errCode string, stocktake *model.StocktakeResponse) {
+	statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+	if errCode == error_utils.ErrorCode.STOCKTAKE_RECOUNT_REQUIRED && stocktake != nil {
+		for _, item := range stocktake.Items {
+			if item.NeedsRecount {
+				errResponse.Errors = append(errResponse.Errors, httpcommon.Error{
+					Message: "Product " + strconv.Itoa(item.ProductID) + " (" + item.ProductName + ") must be counted again",
+					Code:    errCode,
+					Field:   "product_id",
+				})
+			}
+		}
+	}
+	if errCode == error_utils.ErrorCode.STOCKTAKE_BELOW_RESERVED && stocktake != nil {
+		for _, item := range stocktake.Items {
+			if item.BelowReserved {
+				errResponse.Errors = append(errResponse.Errors, httpcommon.Error{
+					Message: "Product " + strconv.Itoa(item.ProductID) + " (" + item.ProductName + ") is counted below the " + strconv.Itoa(*item.ReservedQuantity) + " reserved for pending orders",
+					Code:    errCode,
+					Field:   "product_id",
+				})
+			}
+		}
+	}
+	ctx.JSON(statusCode, errResponse)
+}
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
@@ -22,14 +23,16 @@ func NewUserHandler(userService service.UserService) *UserHandler {
 }
 
 // @Summary User Login
-// @Description Login with username and password
+// @Description Login with username and password. Unknown usernames and wrong passwords return the same INVALID_CREDENTIALS error. Repeated failures for a username or client IP lock further attempts out for a growing period (LOGIN_LOCKED).
 // @Tags Users
 // @Accept json
 // @Produce json
 // @Param request body model.LoginRequest true "Login credentials"
 // @Success 200 {object} httpcommon.HttpResponse[model.LoginResponse]
 // @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 401 {object} httpcommon.HttpResponse[any]
 // @Failure 403 {object} httpcommon.HttpResponse[any]
+// @Failure 429 {object} httpcommon.HttpResponse[any]
 // @Failure 500 {object} httpcommon.HttpResponse[any]
 // @Router /users/login [post]
 func (h *UserHandler) Login(ctx *gin.Context) {
@@ -47,3 +50,268 @@ func (h *UserHandler) Login(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
 }
+
+// @Summary Refresh Tokens
+// @Description Exchange a refresh token for a new access token and a new refresh token. Each refresh token works once; replaying a used one ends every session of the user.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body model.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} httpcommon.HttpResponse[model.LoginResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 401 {object} httpcommon.HttpResponse[any]
+// @Failure 403 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /users/refresh [post]
+func (h *UserHandler) Refresh(ctx *gin.Context) {
+	var request model.RefreshTokenRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.userService.Refresh(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Logout
+// @Description Revoke the given refresh token. With all_devices=true every refresh token of the user is revoked and every access token already issued stops working.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body model.LogoutRequest true "Refresh token of the session"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 401 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /users/logout [post]
+func (h *UserHandler) Logout(ctx *gin.Context) {
+	var request model.LogoutRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	errCode := h.userService.Logout(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Create User
+// @Description Create a staff account with a role. Owner only.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreateUserRequest true "User details"
+// @Success 201 {object} httpcommon.HttpResponse[model.UserResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 403 {object} httpcommon.HttpResponse[any]
+// @Failure 409 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /users [post]
+func (h *UserHandler) Create(ctx *gin.Context) {
+	var request model.CreateUserRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.userService.Create(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Users
+// @Description List every account with its role and status. Owner only.
+// @Tags Users
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllUsersResponse]
+// @Failure 403 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /users [get]
+func (h *UserHandler) GetAll(ctx *gin.Context) {
+	response, errCode := h.userService.GetAll(ctx)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Disable or enable User
+// @Description Disable an account so it can no longer log in or use existing tokens, or enable it again. Owner only; owners cannot disable themselves.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param userId path int true "User ID"
+// @Param request body model.UpdateUserStatusRequest true "New status"
+// @Success 200 {object} httpcommon.HttpResponse[model.UserResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 403 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /users/{userId}/status [put]
+func (h *UserHandler) UpdateStatus(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "userId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdateUserStatusRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.userService.UpdateStatus(ctx, userID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Reset User Password
+// @Description Set a new password for another account without knowing the old one and end all of its sessions. Owner only.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param userId path int true "User ID"
+// @Param request body model.ResetPasswordRequest true "New password"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 403 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /users/{userId}/password [put]
+func (h *UserHandler) ResetPassword(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("userId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "userId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.ResetPasswordRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	errCode := h.userService.ResetPassword(ctx, userID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Change Password
+// @Description Change the signed-in user's own password after checking the current one. Every session of the user is ended, so the client has to log in again.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /users/me/password [put]
+func (h *UserHandler) ChangePassword(ctx *gin.Context) {
+	var request model.ChangePasswordRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	errCode := h.userService.ChangePassword(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Get Login Attempts
+// @Description Audit log of login attempts, newest first. Owner only.
+// @Tags Users
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param username query string false "Filter by the username that was entered"
+// @Param ip_address query string false "Filter by client IP address"
+// @Param success query bool false "Filter by result"
+// @Param from_date query string false "Filter from date (format: YYYY-MM-DD)"
+// @Param to_date query string false "Filter to date (format: YYYY-MM-DD)"
+// @Param limit query int false "Maximum rows (default: 100, max: 500)"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetLoginAttemptsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 403 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /users/login-attempts [get]
+func (h *UserHandler) GetLoginAttempts(ctx *gin.Context) {
+	request := model.GetLoginAttemptsRequest{
+		Username:  ctx.Query("username"),
+		IPAddress: ctx.Query("ip_address"),
+	}
+
+	if successStr := ctx.Query("success"); successStr != "" {
+		success, err := strconv.ParseBool(successStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "success")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		request.Success = &success
+	}
+
+	var ok bool
+	if request.FromDate, ok = parseDateQuery(ctx, "from_date"); !ok {
+		return
+	}
+	if request.ToDate, ok = parseDateQuery(ctx, "to_date"); !ok {
+		return
+	}
+
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "limit")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		request.Limit = limit
+	}
+
+	response, errCode := h.userService.GetLoginAttempts(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
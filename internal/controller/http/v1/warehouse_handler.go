@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	httpcommon "github.com/pna/order-app-backend/internal/domain/http_common"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	"github.com/pna/order-app-backend/internal/utils/validation"
+)
+
+type WarehouseHandler struct {
+	warehouseService service.WarehouseService
+}
+
+func NewWarehouseHandler(warehouseService service.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{
+		warehouseService: warehouseService,
+	}
+}
+
+// @Summary Create Warehouse
+// @Description Create a new warehouse; every product starts with an empty inventory in it
+// @Tags Warehouses
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreateWarehouseRequest true "Warehouse information"
+// @Success 201 {object} httpcommon.HttpResponse[model.WarehouseResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /warehouses [post]
+func (h *WarehouseHandler) Create(ctx *gin.Context) {
+	var request model.CreateWarehouseRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.warehouseService.Create(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Update Warehouse
+// @Description Update the name and address of an existing warehouse
+// @Tags Warehouses
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param warehouseId path int true "Warehouse ID"
+// @Param request body model.UpdateWarehouseRequest true "Updated warehouse information"
+// @Success 200 {object} httpcommon.HttpResponse[model.WarehouseResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /warehouses/{warehouseId} [put]
+func (h *WarehouseHandler) Update(ctx *gin.Context) {
+	warehouseID, err := strconv.Atoi(ctx.Param("warehouseId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "warehouseId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdateWarehouseRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.warehouseService.Update(ctx, warehouseID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Warehouses
+// @Description Retrieve all warehouses, the default warehouse first
+// @Tags Warehouses
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllWarehousesResponse]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /warehouses [get]
+func (h *WarehouseHandler) GetAll(ctx *gin.Context) {
+	response, errCode := h.warehouseService.GetAll(ctx)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
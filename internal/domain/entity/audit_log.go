@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+type AuditLog struct {
+	ID         int       `db:"id"`
+	UserID     *int      `db:"user_id"`
+	Username   *string   `db:"username"`
+	Action     string    `db:"action"`
+	EntityType string    `db:"entity_type"`
+	EntityID   int       `db:"entity_id"`
+	BeforeData *string   `db:"before_data"` // JSON snapshot before the change
+	AfterData  *string   `db:"after_data"`  // JSON snapshot after the change
+	RequestID  *string   `db:"request_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type auditAction struct {
+	CREATE string
+	UPDATE string
+	DELETE string
+}
+
+var AuditAction = auditAction{
+	CREATE: "CREATE",
+	UPDATE: "UPDATE",
+	DELETE: "DELETE",
+}
+
+type auditEntityType struct {
+	PRODUCT           string
+	CUSTOMER          string
+	ORDER             string
+	ORDER_IMAGE       string
+	INVENTORY         string
+	PRODUCT_PRICE     string
+	CUSTOMER_DISCOUNT string
+}
+
+var AuditEntityType = auditEntityType{
+	PRODUCT:           "PRODUCT",
+	CUSTOMER:          "CUSTOMER",
+	ORDER:             "ORDER",
+	ORDER_IMAGE:       "ORDER_IMAGE",
+	INVENTORY:         "INVENTORY",
+	PRODUCT_PRICE:     "PRODUCT_PRICE",
+	CUSTOMER_DISCOUNT: "CUSTOMER_DISCOUNT",
+}
@@ -1,10 +1,33 @@
 package entity
 
+import "time"
+
 type Customer struct {
-	ID      int    `db:"id"`
-	Name    string `db:"name"`
-	Phone   string `db:"phone"`
-	Address string `db:"address"`
+	ID              int        `db:"id"`
+	Name            string     `db:"name"`
+	Phone           string     `db:"phone"`
+	NormalizedPhone string     `db:"normalized_phone"` // Số điện thoại chỉ gồm chữ số, dùng để tìm kiếm và phát hiện trùng lặp
+	Address         string     `db:"address"`
+	DeletedAt       *time.Time `db:"deleted_at"`     // Thời điểm xoá, nil là đang hoạt động
+	MergedIntoID    *int       `db:"merged_into_id"` // Khách hàng được gộp vào khi xoá do trùng lặp
+}
+
+// CustomerDuplicate is an active customer that looks like the same shop as at least one other customer
+type CustomerDuplicate struct {
+	Customer
+	DuplicateOfID  int `db:"duplicate_of_id"` // The oldest customer of the group, the natural one to merge the others into
+	DuplicateCount int `db:"duplicate_count"`
+}
+
+type customerDuplicateReason struct {
+	PHONE string
+	NAME  string
+}
+
+// CustomerDuplicateReason tells what the customers of a duplicate group have in common
+var CustomerDuplicateReason = customerDuplicateReason{
+	PHONE: "PHONE",
+	NAME:  "NAME",
 }
 
 type customerLocationType struct {
@@ -1,8 +1,13 @@
 package entity
 
+// Inventory is the stock of one product in one warehouse. Quantity is what is on hand, ReservedQuantity is promised
+// to pending orders and not yet deducted, so only the difference is available to sell or move.
 type Inventory struct {
-	ID        int    `db:"id"`
-	ProductID int    `db:"product_id"`
-	Quantity  int    `db:"quantity"`
-	Version   string `db:"version"`
+	ID               int    `db:"id"`
+	WarehouseID      int    `db:"warehouse_id"`
+	WarehouseName    string `db:"warehouse_name"`
+	ProductID        int    `db:"product_id"`
+	Quantity         int    `db:"quantity"`
+	ReservedQuantity int    `db:"reserved_quantity"`
+	Version          string `db:"version"`
 }
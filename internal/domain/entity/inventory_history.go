@@ -5,10 +5,38 @@ import "time"
 type InventoryHistory struct {
 	ID            int       `db:"id"`
 	ProductID     int       `db:"product_id"`
+	WarehouseID   int       `db:"warehouse_id"`
+	WarehouseName string    `db:"warehouse_name"`
 	Quantity      int       `db:"quantity"`
 	FinalQuantity int       `db:"final_quantity"`
+	MovementType  string    `db:"movement_type"`
 	ImporterName  string    `db:"importer_name"`
 	ImportedAt    time.Time `db:"imported_at"`
 	Note          string    `db:"note"`
 	ReferenceID   *int      `db:"reference_id"`
 }
+
+// InventoryMovementSummary is the stock that came in and went out of a product for one kind of movement
+type InventoryMovementSummary struct {
+	ProductID    int    `db:"product_id"`
+	MovementType string `db:"movement_type"`
+	QuantityIn   int    `db:"quantity_in"`
+	QuantityOut  int    `db:"quantity_out"`
+}
+
+type inventoryMovementType struct {
+	SALE       string
+	RETURN     string
+	PURCHASE   string
+	TRANSFER   string
+	ADJUSTMENT string
+}
+
+// InventoryMovementType tells what moved the stock of an inventory history row
+var InventoryMovementType = inventoryMovementType{
+	SALE:       "SALE",
+	RETURN:     "RETURN",
+	PURCHASE:   "PURCHASE",
+	TRANSFER:   "TRANSFER",
+	ADJUSTMENT: "ADJUSTMENT",
+}
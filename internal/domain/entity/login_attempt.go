@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+type LoginAttempt struct {
+	ID            int       `db:"id"`
+	Username      string    `db:"username"`
+	IPAddress     string    `db:"ip_address"`
+	UserAgent     string    `db:"user_agent"`
+	UserID        *int      `db:"user_id"`
+	Success       bool      `db:"success"`
+	FailureReason *string   `db:"failure_reason"`
+	AttemptedAt   time.Time `db:"attempted_at"`
+}
+
+// LoginFailureStats summarises the failed attempts counted towards a lockout
+type LoginFailureStats struct {
+	FailureCount  int        `db:"failure_count"`
+	LastFailureAt *time.Time `db:"last_failure_at"`
+}
+
+type loginFailureReason struct {
+	UNKNOWN_USER   string
+	WRONG_PASSWORD string
+	DISABLED       string
+	LOCKED         string
+}
+
+var LoginFailureReason = loginFailureReason{
+	UNKNOWN_USER:   "UNKNOWN_USER",
+	WRONG_PASSWORD: "WRONG_PASSWORD",
+	DISABLED:       "DISABLED",
+	LOCKED:         "LOCKED",
+}
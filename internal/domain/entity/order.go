@@ -14,6 +14,10 @@ type Order struct {
 	AdditionalCost       int        `db:"additional_cost"`
 	AdditionalCostNote   *string    `db:"additonal_cost_note"`
 	TaxPercent           int        `db:"tax_percent"`
+	CancelledAt          *time.Time `db:"cancelled_at"`
+	CancellationReason   *string    `db:"cancellation_reason"`
+	TotalReturnedRevenue int        `db:"total_returned_revenue"`
+	TotalReturnedCost    int        `db:"total_returned_cost"`
 }
 
 type orderDeliveryStatus struct {
@@ -21,6 +25,7 @@ type orderDeliveryStatus struct {
 	DELIVERED string
 	UNPAID    string
 	COMPLETED string
+	CANCELLED string
 }
 
 var OrderDeliveryStatus = orderDeliveryStatus{
@@ -28,4 +33,65 @@ var OrderDeliveryStatus = orderDeliveryStatus{
 	DELIVERED: "DELIVERED",
 	UNPAID:    "UNPAID",
 	COMPLETED: "COMPLETED",
+	CANCELLED: "CANCELLED",
+}
+
+// OrderDeliveryStatusTransitions lists, for each delivery status, the statuses an order is allowed to move to.
+// CANCELLED is only reachable through the cancel flow, which also restores inventory.
+var OrderDeliveryStatusTransitions = map[string][]string{
+	OrderDeliveryStatus.PENDING:   {OrderDeliveryStatus.DELIVERED, OrderDeliveryStatus.UNPAID, OrderDeliveryStatus.COMPLETED},
+	OrderDeliveryStatus.DELIVERED: {OrderDeliveryStatus.UNPAID, OrderDeliveryStatus.COMPLETED},
+	OrderDeliveryStatus.UNPAID:    {OrderDeliveryStatus.COMPLETED},
+	OrderDeliveryStatus.COMPLETED: {},
+	OrderDeliveryStatus.CANCELLED: {},
+}
+
+func IsValidOrderDeliveryStatus(status string) bool {
+	_, ok := OrderDeliveryStatusTransitions[status]
+	return ok
+}
+
+func CanCancelOrder(status string) bool {
+	return status != OrderDeliveryStatus.COMPLETED && status != OrderDeliveryStatus.CANCELLED
+}
+
+func CanTransitionOrderDeliveryStatus(from string, to string) bool {
+	for _, allowed := range OrderDeliveryStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+type orderDebtStatus struct {
+	UNPAID  string
+	PARTIAL string
+	PAID    string
+}
+
+var OrderDebtStatus = orderDebtStatus{
+	UNPAID:  "UNPAID",
+	PARTIAL: "PARTIAL",
+	PAID:    "PAID",
+}
+
+// OrderWithSummary is an order row joined with its customer and the amounts aggregated from its items and payments
+type OrderWithSummary struct {
+	Order
+	CustomerName    string `db:"customer_name"`
+	CustomerPhone   string `db:"customer_phone"`
+	CustomerAddress string `db:"customer_address"`
+	ItemsAmount     int    `db:"items_amount"`  // Tổng tiền hàng sau chiết khấu (VND)
+	ProductCount    int    `db:"product_count"` // Số sản phẩm khác nhau trong đơn
+	PaidAmount      int    `db:"paid_amount"`   // Số tiền đã thanh toán (VND)
+	NetAmount       int    `db:"net_amount"`    // Tiền hàng trừ hàng trả, cộng chi phí phát sinh, chưa thuế (VND)
+	TotalAmount     int    `db:"total_amount"`  // Số tiền khách phải trả (VND)
+}
+
+// OrderListSummary holds the aggregates over every order matching a listing filter
+type OrderListSummary struct {
+	TotalCount      int `db:"total_count"`
+	TotalAmount     int `db:"total_amount"`
+	TotalProfitLoss int `db:"total_profit_loss"`
 }
@@ -11,7 +11,9 @@ type OrderItem struct {
 	OriginalPrice int    `db:"original_price"`  // Giá gốc của sản phẩm (VND)
 	Discount      int    `db:"discount"`        // Chiết khấu (%)
 	FinalAmount   *int   `db:"final_amount"`    // Số tiền cuối cùng sau khi trừ chiết khấu (VND)
-	ExportFrom    string `db:"export_from"`     // Nguồn xuất: từ xưởng hoặc từ bên ngoài
+	PriceSource   string `db:"price_source"`    // Giá theo bảng giá hay nhập tay
+	ExportFrom    string `db:"export_from"`     // Nguồn xuất: từ kho hoặc từ bên ngoài
+	WarehouseID   *int   `db:"warehouse_id"`    // Kho xuất hàng, chỉ có khi xuất từ kho
 }
 
 type orderExportFrom struct {
@@ -23,3 +25,23 @@ var OrderExportFrom = orderExportFrom{
 	INVENTORY: "INVENTORY",
 	EXTERNAL:  "EXTERNAL",
 }
+
+type orderItemPriceSource struct {
+	LIST   string
+	MANUAL string
+}
+
+// OrderItemPriceSource tells whether an order item was sold at the price list's suggestion or at a price typed by hand
+var OrderItemPriceSource = orderItemPriceSource{
+	LIST:   "LIST",
+	MANUAL: "MANUAL",
+}
+
+// CustomerProductPurchase is how much of one product a customer has bought over all their non-cancelled orders
+type CustomerProductPurchase struct {
+	ProductID   int    `db:"product_id"`
+	ProductName string `db:"product_name"`
+	Quantity    int    `db:"quantity"`    // Tổng số lượng đã mua
+	OrderCount  int    `db:"order_count"` // Số đơn hàng có sản phẩm
+	Amount      int    `db:"amount"`      // Tổng tiền hàng sau chiết khấu (VND)
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+type OrderReturn struct {
+	ID                int       `db:"id"`
+	OrderID           int       `db:"order_id"`
+	ReturnedAt        time.Time `db:"returned_at"`         // Ngày khách trả hàng
+	Reason            *string   `db:"reason"`              // Lý do trả hàng
+	TotalRefundAmount int       `db:"total_refund_amount"` // Tổng giá trị ghi có cho khách (VND)
+	TotalOriginalCost int       `db:"total_original_cost"` // Tổng giá gốc của hàng trả (VND)
+	CreatedBy         int       `db:"created_by"`          // ID người ghi nhận
+	CreatedByName     string    `db:"created_by_name"`
+	CreatedAt         time.Time `db:"created_at"`
+}
+
+type OrderReturnItem struct {
+	ID            int    `db:"id"`
+	OrderReturnID int    `db:"order_return_id"`
+	OrderItemID   int    `db:"order_item_id"`
+	ProductID     int    `db:"product_id"`
+	Quantity      int    `db:"quantity"`      // Số lượng trả lại
+	RefundAmount  int    `db:"refund_amount"` // Giá trị ghi có cho khách (VND)
+	OriginalCost  int    `db:"original_cost"` // Giá gốc của hàng trả (VND)
+	ExportFrom    string `db:"export_from"`   // Nguồn xuất của dòng hàng bị trả
+}
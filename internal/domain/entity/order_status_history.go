@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type OrderStatusHistory struct {
+	ID            int       `db:"id"`
+	OrderID       int       `db:"order_id"`
+	FromStatus    *string   `db:"from_status"` // Trạng thái trước khi chuyển
+	ToStatus      string    `db:"to_status"`   // Trạng thái sau khi chuyển
+	ChangedBy     int       `db:"changed_by"`  // ID người chuyển trạng thái
+	ChangedByName string    `db:"changed_by_name"`
+	ChangedAt     time.Time `db:"changed_at"`
+	Note          *string   `db:"note"`
+}
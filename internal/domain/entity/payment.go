@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+type Payment struct {
+	ID             int       `db:"id"`
+	OrderID        int       `db:"order_id"`
+	Amount         int       `db:"amount"`      // Số tiền thanh toán (VND)
+	Method         string    `db:"method"`      // Phương thức thanh toán
+	PaidAt         time.Time `db:"paid_at"`     // Ngày thanh toán
+	Note           *string   `db:"note"`        // Ghi chú
+	RecordedBy     int       `db:"recorded_by"` // ID người ghi nhận
+	RecordedByName string    `db:"recorded_by_name"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+type paymentMethod struct {
+	CASH          string
+	BANK_TRANSFER string
+	OTHER         string
+}
+
+var PaymentMethod = paymentMethod{
+	CASH:          "CASH",
+	BANK_TRANSFER: "BANK_TRANSFER",
+	OTHER:         "OTHER",
+}
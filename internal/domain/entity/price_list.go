@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// ProductPrice is a selling price of a product from a date on. Without a customer it is the default price,
+// otherwise it overrides the default price for that customer
+type ProductPrice struct {
+	ID            int       `db:"id"`
+	ProductID     int       `db:"product_id"`
+	ProductName   string    `db:"product_name"`
+	CustomerID    *int      `db:"customer_id"` // Khách hàng được áp giá riêng, nil là giá bán mặc định
+	CustomerName  *string   `db:"customer_name"`
+	SellingPrice  int       `db:"selling_price"`  // Giá bán (VND)
+	EffectiveFrom time.Time `db:"effective_from"` // Ngày bắt đầu áp dụng
+	CreatedBy     int       `db:"created_by"`
+	CreatedByName string    `db:"created_by_name"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// CustomerDiscount is the discount a customer gets on list prices from a date on
+type CustomerDiscount struct {
+	ID              int       `db:"id"`
+	CustomerID      int       `db:"customer_id"`
+	CustomerName    string    `db:"customer_name"`
+	DiscountPercent int       `db:"discount_percent"` // Chiết khấu mặc định (%)
+	EffectiveFrom   time.Time `db:"effective_from"`   // Ngày bắt đầu áp dụng
+	CreatedBy       int       `db:"created_by"`
+	CreatedByName   string    `db:"created_by_name"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+type productPriceType struct {
+	CUSTOMER_PRICE string
+	DEFAULT_PRICE  string
+	NONE           string
+}
+
+// ProductPriceType tells which price list entry a suggested price comes from
+var ProductPriceType = productPriceType{
+	CUSTOMER_PRICE: "CUSTOMER_PRICE",
+	DEFAULT_PRICE:  "DEFAULT_PRICE",
+	NONE:           "NONE",
+}
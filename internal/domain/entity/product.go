@@ -1,8 +1,14 @@
 package entity
 
+// DefaultProductMinStock is the low-stock threshold of products created without one
+const DefaultProductMinStock = 10
+
 type Product struct {
-	ID            int    `db:"id"`
-	Name          string `db:"name"`           // Tên sản phẩm
-	Spec          int    `db:"spec"`           // Quy cách
-	OriginalPrice int    `db:"original_price"` // Giá gốc của sản phẩm (VND)
+	ID              int    `db:"id"`
+	Name            string `db:"name"`             // Tên sản phẩm
+	Spec            int    `db:"spec"`             // Quy cách
+	OriginalPrice   int    `db:"original_price"`   // Giá gốc của sản phẩm (VND)
+	AverageCost     int    `db:"average_cost"`     // Giá vốn bình quân gia quyền (VND)
+	MinStock        int    `db:"min_stock"`        // Tồn kho tối thiểu
+	ReorderQuantity int    `db:"reorder_quantity"` // Số lượng nhập thêm tối thiểu mỗi lần
 }
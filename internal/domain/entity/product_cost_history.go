@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+type ProductCostHistory struct {
+	ID                  int       `db:"id"`
+	ProductID           int       `db:"product_id"`
+	Source              string    `db:"source"`
+	ReferenceID         *int      `db:"reference_id"`          // Mã phiếu nhập nếu nhập từ phiếu nhập
+	QuantityBefore      int       `db:"quantity_before"`       // Số lượng tồn trước khi nhập
+	ReceivedQuantity    int       `db:"received_quantity"`     // Số lượng nhập
+	UnitCost            int       `db:"unit_cost"`             // Giá nhập mỗi đơn vị (VND)
+	PreviousAverageCost int       `db:"previous_average_cost"` // Giá vốn bình quân trước khi nhập (VND)
+	AverageCost         int       `db:"average_cost"`          // Giá vốn bình quân sau khi nhập (VND)
+	CreatedBy           *int      `db:"created_by"`
+	CreatedByName       *string   `db:"created_by_name"`
+	CreatedAt           time.Time `db:"created_at"`
+}
+
+type productCostSource struct {
+	PURCHASE_ORDER       string
+	INVENTORY_ADJUSTMENT string
+	OPENING_STOCK        string
+}
+
+var ProductCostSource = productCostSource{
+	PURCHASE_ORDER:       "PURCHASE_ORDER",
+	INVENTORY_ADJUSTMENT: "INVENTORY_ADJUSTMENT",
+	OPENING_STOCK:        "OPENING_STOCK",
+}
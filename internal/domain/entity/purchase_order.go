@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+type PurchaseOrder struct {
+	ID            int        `db:"id"`
+	SupplierID    int        `db:"supplier_id"`
+	SupplierName  string     `db:"supplier_name"`
+	WarehouseID   int        `db:"warehouse_id"` // Kho nhận hàng
+	WarehouseName string     `db:"warehouse_name"`
+	Status        string     `db:"status"`
+	ExpectedDate  *time.Time `db:"expected_date"` // Ngày dự kiến nhận hàng
+	Note          *string    `db:"note"`
+	TotalAmount   int        `db:"total_amount"` // Tổng tiền hàng theo giá nhập (VND), tính từ các dòng hàng
+	CreatedBy     int        `db:"created_by"`
+	CreatedByName string     `db:"created_by_name"`
+	CreatedAt     time.Time  `db:"created_at"`
+	ReceivedAt    *time.Time `db:"received_at"`
+	CancelledAt   *time.Time `db:"cancelled_at"`
+}
+
+type PurchaseOrderItem struct {
+	ID               int    `db:"id"`
+	PurchaseOrderID  int    `db:"purchase_order_id"`
+	ProductID        int    `db:"product_id"`
+	ProductName      string `db:"product_name"`
+	Quantity         int    `db:"quantity"`          // Số lượng đặt mua
+	ReceivedQuantity int    `db:"received_quantity"` // Số lượng đã nhận vào kho
+	PurchasePrice    int    `db:"purchase_price"`    // Giá nhập mỗi đơn vị (VND)
+}
+
+type purchaseOrderStatus struct {
+	PENDING            string
+	PARTIALLY_RECEIVED string
+	RECEIVED           string
+	CANCELLED          string
+}
+
+var PurchaseOrderStatus = purchaseOrderStatus{
+	PENDING:            "PENDING",
+	PARTIALLY_RECEIVED: "PARTIALLY_RECEIVED",
+	RECEIVED:           "RECEIVED",
+	CANCELLED:          "CANCELLED",
+}
+
+// CanReceivePurchaseOrder reports whether goods can still be received against a purchase order in the given status
+func CanReceivePurchaseOrder(status string) bool {
+	return status == PurchaseOrderStatus.PENDING || status == PurchaseOrderStatus.PARTIALLY_RECEIVED
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+type RefreshToken struct {
+	ID           int        `db:"id"`
+	UserID       int        `db:"user_id"`
+	TokenHash    string     `db:"token_hash"`
+	ExpiresAt    time.Time  `db:"expires_at"`
+	RevokedAt    *time.Time `db:"revoked_at"`
+	ReplacedByID *int       `db:"replaced_by_id"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
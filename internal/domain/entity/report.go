@@ -0,0 +1,45 @@
+package entity
+
+// ReportFigures are the money figures of a group of orders, net of returned goods and excluding cancelled orders
+type ReportFigures struct {
+	OrderCount     int `db:"order_count"`
+	Revenue        int `db:"revenue"`         // Doanh thu sau chiết khấu, đã trừ hàng trả (VND)
+	OriginalCost   int `db:"original_cost"`   // Giá vốn theo giá gốc lúc bán, đã trừ hàng trả (VND)
+	AdditionalCost int `db:"additional_cost"` // Chi phí phát sinh (VND)
+	Tax            int `db:"tax"`             // Thuế (VND)
+	ProfitLoss     int `db:"profit_loss"`     // Lãi/lỗ (VND)
+}
+
+type PeriodReportRow struct {
+	Period string `db:"period"` // Ngày (YYYY-MM-DD), tuần (YYYY-Www) hoặc tháng (YYYY-MM)
+	ReportFigures
+}
+
+type CustomerReportRow struct {
+	CustomerID   int    `db:"customer_id"`
+	CustomerName string `db:"customer_name"`
+	ReportFigures
+}
+
+type ProductReportRow struct {
+	ProductID        int    `db:"product_id"`
+	ProductName      string `db:"product_name"`
+	OrderCount       int    `db:"order_count"`
+	QuantitySold     int    `db:"quantity_sold"`
+	QuantityReturned int    `db:"quantity_returned"`
+	Revenue          int    `db:"revenue"`       // Doanh thu sau chiết khấu, đã trừ hàng trả (VND)
+	OriginalCost     int    `db:"original_cost"` // Giá vốn theo giá gốc lúc bán, đã trừ hàng trả (VND)
+	ProfitLoss       int    `db:"profit_loss"`   // Lãi/lỗ (VND)
+}
+
+type reportPeriod struct {
+	DAY   string
+	WEEK  string
+	MONTH string
+}
+
+var ReportPeriod = reportPeriod{
+	DAY:   "day",
+	WEEK:  "week",
+	MONTH: "month",
+}
@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+type Stocktake struct {
+	ID            int        `db:"id"`
+	WarehouseID   int        `db:"warehouse_id"` // Kho được kiểm kê
+	WarehouseName string     `db:"warehouse_name"`
+	Status        string     `db:"status"`
+	Note          *string    `db:"note"`
+	CreatedBy     int        `db:"created_by"`
+	CreatedByName string     `db:"created_by_name"`
+	CreatedAt     time.Time  `db:"created_at"`
+	PostedBy      *int       `db:"posted_by"`
+	PostedAt      *time.Time `db:"posted_at"`
+	CancelledAt   *time.Time `db:"cancelled_at"`
+}
+
+// StocktakeItem is the count of one product: the book quantity and inventory version it is compared against,
+// and the quantity found on the shelf once it has been counted
+type StocktakeItem struct {
+	ID               int        `db:"id"`
+	StocktakeID      int        `db:"stocktake_id"`
+	ProductID        int        `db:"product_id"`
+	ProductName      string     `db:"product_name"`
+	SnapshotQuantity int        `db:"snapshot_quantity"` // Số lượng trên sổ sách lúc chụp
+	SnapshotVersion  string     `db:"snapshot_version"`  // Version tồn kho lúc chụp
+	CountedQuantity  *int       `db:"counted_quantity"`  // Số lượng đếm thực tế
+	CountedBy        *int       `db:"counted_by"`
+	CountedByName    *string    `db:"counted_by_name"`
+	CountedAt        *time.Time `db:"counted_at"`
+}
+
+type stocktakeStatus struct {
+	OPEN      string
+	POSTED    string
+	CANCELLED string
+}
+
+var StocktakeStatus = stocktakeStatus{
+	OPEN:      "OPEN",
+	POSTED:    "POSTED",
+	CANCELLED: "CANCELLED",
+}
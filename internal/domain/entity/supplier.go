@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+type Supplier struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`    // Tên nhà cung cấp
+	Phone     string    `db:"phone"`   // Số điện thoại
+	Address   string    `db:"address"` // Địa chỉ
+	Note      *string   `db:"note"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -1,7 +1,27 @@
 package entity
 
+import "time"
+
 type User struct {
-	ID       int    `db:"id"`
-	Username string `db:"username"`
-	Password string `db:"password"`
+	ID           int       `db:"id"`
+	Username     string    `db:"username"`
+	Password     string    `db:"password"`
+	Role         string    `db:"role"`
+	IsActive     bool      `db:"is_active"`
+	TokenVersion int       `db:"token_version"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type userRole struct {
+	OWNER      string
+	SALES      string
+	WAREHOUSE  string
+	ACCOUNTANT string
+}
+
+var UserRole = userRole{
+	OWNER:      "OWNER",
+	SALES:      "SALES",
+	WAREHOUSE:  "WAREHOUSE",
+	ACCOUNTANT: "ACCOUNTANT",
 }
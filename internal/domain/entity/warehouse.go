@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+type Warehouse struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`       // Tên kho
+	Address   string    `db:"address"`    // Địa chỉ kho
+	IsDefault bool      `db:"is_default"` // Kho mặc định khi không chọn kho
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type StockTransfer struct {
+	ID                int       `db:"id"`
+	ProductID         int       `db:"product_id"`
+	ProductName       string    `db:"product_name"`
+	FromWarehouseID   int       `db:"from_warehouse_id"` // Kho chuyển đi
+	FromWarehouseName string    `db:"from_warehouse_name"`
+	ToWarehouseID     int       `db:"to_warehouse_id"` // Kho nhận
+	ToWarehouseName   string    `db:"to_warehouse_name"`
+	Quantity          int       `db:"quantity"` // Số lượng chuyển
+	Note              *string   `db:"note"`
+	CreatedBy         int       `db:"created_by"`
+	CreatedByName     string    `db:"created_by_name"`
+	CreatedAt         time.Time `db:"created_at"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type GetAllAuditLogsRequest struct {
+	UserID     int        // Lọc theo người thực hiện
+	Action     string     // Lọc theo hành động: CREATE, UPDATE, DELETE
+	EntityType string     // Lọc theo loại dữ liệu: PRODUCT, CUSTOMER, ORDER, ORDER_IMAGE, INVENTORY
+	EntityID   int        // Lọc theo mã của dữ liệu, dùng cùng EntityType
+	RequestID  string     // Lọc theo mã request
+	FromDate   *time.Time // Từ ngày
+	ToDate     *time.Time // Đến ngày
+	Limit      int        // Số dòng tối đa
+}
+
+type AuditLogResponse struct {
+	ID         int             `json:"id"`
+	UserID     *int            `json:"user_id"`     // Người thực hiện
+	Username   *string         `json:"username"`    // Tên đăng nhập người thực hiện
+	Action     string          `json:"action"`      // CREATE, UPDATE, DELETE
+	EntityType string          `json:"entity_type"` // PRODUCT, CUSTOMER, ORDER, ORDER_IMAGE, INVENTORY
+	EntityID   int             `json:"entity_id"`   // Mã của dữ liệu bị thay đổi
+	Before     json.RawMessage `json:"before"`      // Dữ liệu trước khi thay đổi, null khi tạo mới
+	After      json.RawMessage `json:"after"`       // Dữ liệu sau khi thay đổi, null khi xoá
+	RequestID  *string         `json:"request_id"`  // Mã request, trùng với header X-Request-ID
+	CreatedAt  time.Time       `json:"created_at"`  // Thời điểm thay đổi
+}
+
+type GetAllAuditLogsResponse struct {
+	AuditLogs []AuditLogResponse `json:"audit_logs"`
+}
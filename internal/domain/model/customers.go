@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type CreateCustomerRequest struct {
 	Name    string `json:"name" binding:"required"`    // Tên khách hàng
 	Phone   string `json:"phone" binding:"required"`   // Số điện thoại
@@ -13,16 +15,56 @@ type UpdateCustomerRequest struct {
 }
 
 type CustomerResponse struct {
-	ID      int    `json:"id"`
-	Name    string `json:"name"`    // Tên khách hàng
-	Phone   string `json:"phone"`   // Số điện thoại
-	Address string `json:"address"` // Địa chỉ
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`                     // Tên khách hàng
+	Phone        string     `json:"phone"`                    // Số điện thoại
+	Address      string     `json:"address"`                  // Địa chỉ
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`     // Thời điểm xoá khách hàng
+	MergedIntoID *int       `json:"merged_into_id,omitempty"` // Khách hàng được gộp vào khi xoá do trùng lặp
 }
 
 type GetAllCustomersResponse struct {
 	Customers []CustomerResponse `json:"customers"`
 }
 
+type MergeCustomerRequest struct {
+	SourceCustomerID int `json:"source_customer_id" binding:"required"` // Khách hàng trùng lặp sẽ được gộp vào và bị xoá
+}
+
+type MergeCustomerResponse struct {
+	Customer        CustomerResponse `json:"customer"`          // Khách hàng được giữ lại
+	MovedOrderCount int              `json:"moved_order_count"` // Số đơn hàng được chuyển sang
+}
+
+type CustomerDuplicateGroupResponse struct {
+	Reason    string             `json:"reason"`    // PHONE: trùng số điện thoại, NAME: trùng tên
+	Customers []CustomerResponse `json:"customers"` // Các khách hàng trùng lặp, khách hàng cũ nhất đứng đầu
+}
+
+type GetCustomerDuplicatesResponse struct {
+	Groups []CustomerDuplicateGroupResponse `json:"groups"`
+}
+
 type GetOneCustomerResponse struct {
 	Customer CustomerResponse `json:"customer"`
 }
+
+type CustomerSummaryResponse struct {
+	Customer          CustomerResponse                  `json:"customer"`
+	OrderCount        int                               `json:"order_count"`         // Số đơn hàng không bị huỷ
+	LifetimeRevenue   int                               `json:"lifetime_revenue"`    // Tổng doanh thu sau chiết khấu, đã trừ hàng trả (VND)
+	LifetimeProfit    *int                              `json:"lifetime_profit"`     // Tổng lãi/lỗ (VND)
+	AverageOrderValue int                               `json:"average_order_value"` // Doanh thu trung bình mỗi đơn (VND)
+	LastOrderDate     *time.Time                        `json:"last_order_date"`     // Ngày đặt đơn gần nhất
+	UnpaidAmount      int                               `json:"unpaid_amount"`       // Tổng còn nợ (VND)
+	TopProducts       []CustomerProductPurchaseResponse `json:"top_products"`        // Các sản phẩm mua nhiều nhất
+	Orders            []OrderResponse                   `json:"orders"`              // Các đơn hàng, mới nhất trước, kể cả đơn huỷ
+}
+
+type CustomerProductPurchaseResponse struct {
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name"` // Tên sản phẩm
+	Quantity    int    `json:"quantity"`     // Tổng số lượng đã mua
+	OrderCount  int    `json:"order_count"`  // Số đơn hàng có sản phẩm
+	Amount      int    `json:"amount"`       // Tổng tiền hàng sau chiết khấu (VND)
+}
@@ -0,0 +1,7 @@
+package model
+
+type ExportFile struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
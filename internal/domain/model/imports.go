@@ -0,0 +1,21 @@
+package model
+
+type ImportRowError struct {
+	Row     int    `json:"row"`     // Số dòng trong file (tính cả dòng tiêu đề)
+	Column  string `json:"column"`  // Cột bị lỗi
+	Message string `json:"message"` // Mô tả lỗi
+}
+
+type ImportProductsResponse struct {
+	DryRun    bool              `json:"dry_run"`    // Chỉ kiểm tra, không ghi dữ liệu
+	TotalRows int               `json:"total_rows"` // Số dòng dữ liệu trong file
+	Errors    []ImportRowError  `json:"errors"`     // Lỗi theo từng dòng
+	Products  []ProductResponse `json:"products"`   // Sản phẩm sẽ được tạo (dry run) hoặc đã tạo
+}
+
+type ImportCustomersResponse struct {
+	DryRun    bool               `json:"dry_run"`    // Chỉ kiểm tra, không ghi dữ liệu
+	TotalRows int                `json:"total_rows"` // Số dòng dữ liệu trong file
+	Errors    []ImportRowError   `json:"errors"`     // Lỗi theo từng dòng
+	Customers []CustomerResponse `json:"customers"`  // Khách hàng sẽ được tạo (dry run) hoặc đã tạo
+}
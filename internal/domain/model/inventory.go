@@ -1,31 +1,42 @@
 package model
 
 type UpdateInventoryQuantityRequest struct {
-	Quantity int    `json:"quantity" binding:"required"`
-	Note     string `json:"note"`
-	Version  string `json:"version" binding:"required"`
+	Quantity    int    `json:"quantity" binding:"required"`
+	Note        string `json:"note"`
+	Version     string `json:"version" binding:"required"`
+	UnitCost    *int   `json:"unit_cost"`    // Giá nhập mỗi đơn vị (VND), chỉ dùng khi nhập thêm hàng để cập nhật giá vốn bình quân
+	WarehouseID *int   `json:"warehouse_id"` // Kho cần điều chỉnh, mặc định là kho mặc định
 }
 
 type InventoryResponse struct {
-	ID        int    `json:"id"`
-	ProductID int    `json:"product_id"`
-	Quantity  int    `json:"quantity"`
-	Version   string `json:"version"`
+	ID                int    `json:"id"`
+	WarehouseID       int    `json:"warehouse_id"`
+	WarehouseName     string `json:"warehouse_name"`
+	ProductID         int    `json:"product_id"`
+	Quantity          int    `json:"quantity"`           // Số lượng thực có trong kho
+	ReservedQuantity  int    `json:"reserved_quantity"`  // Số lượng đang giữ cho đơn chờ giao
+	AvailableQuantity int    `json:"available_quantity"` // Số lượng còn có thể bán
+	Version           string `json:"version"`
 }
 
 type InventoryWithProductResponse struct {
-	ID        int         `json:"id"`
-	ProductID int         `json:"product_id"`
-	Quantity  int         `json:"quantity"`
-	Version   string      `json:"version"`
-	Product   ProductInfo `json:"product"`
+	ID                int         `json:"id"`
+	WarehouseID       int         `json:"warehouse_id"`
+	WarehouseName     string      `json:"warehouse_name"`
+	ProductID         int         `json:"product_id"`
+	Quantity          int         `json:"quantity"`           // Số lượng thực có trong kho
+	ReservedQuantity  int         `json:"reserved_quantity"`  // Số lượng đang giữ cho đơn chờ giao
+	AvailableQuantity int         `json:"available_quantity"` // Số lượng còn có thể bán
+	Version           string      `json:"version"`
+	Product           ProductInfo `json:"product"`
 }
 
 type ProductInfo struct {
 	ID            int    `json:"id"`
 	Name          string `json:"name"`
 	Spec          int    `json:"spec"`
-	OriginalPrice int    `json:"original_price"`
+	OriginalPrice *int   `json:"original_price,omitempty"`
+	AverageCost   *int   `json:"average_cost,omitempty"`
 }
 
 type GetAllInventoryResponse struct {
@@ -4,6 +4,7 @@ import "time"
 
 type CreateInventoryHistoryRequest struct {
 	ProductID    int    `json:"product_id" binding:"required"`
+	WarehouseID  *int   `json:"warehouse_id"`
 	Quantity     int    `json:"quantity" binding:"required"`
 	ImporterName string `json:"importer_name" binding:"required"`
 	Note         string `json:"note"`
@@ -12,8 +13,11 @@ type CreateInventoryHistoryRequest struct {
 type InventoryHistoryResponse struct {
 	ID            int       `json:"id"`
 	ProductID     int       `json:"product_id"`
+	WarehouseID   int       `json:"warehouse_id"`
+	WarehouseName string    `json:"warehouse_name,omitempty"`
 	Quantity      int       `json:"quantity"`
 	FinalQuantity int       `json:"final_quantity"`
+	MovementType  string    `json:"movement_type"` // SALE, RETURN, PURCHASE, TRANSFER, ADJUSTMENT
 	ImporterName  string    `json:"importer_name"`
 	ImportedAt    time.Time `json:"imported_at"`
 	Note          string    `json:"note"`
@@ -23,3 +27,30 @@ type InventoryHistoryResponse struct {
 type GetAllInventoryHistoriesResponse struct {
 	InventoryHistories []InventoryHistoryResponse `json:"inventory_histories"`
 }
+
+type InventoryMovementTotals struct {
+	In  int `json:"in"`  // Số lượng nhập vào
+	Out int `json:"out"` // Số lượng xuất ra
+}
+
+type InventoryMovementResponse struct {
+	ProductID       int                     `json:"product_id"`
+	ProductName     string                  `json:"product_name"`     // Tên sản phẩm
+	OpeningBalance  int                     `json:"opening_balance"`  // Tồn đầu kỳ
+	TotalIn         int                     `json:"total_in"`         // Tổng nhập trong kỳ
+	TotalOut        int                     `json:"total_out"`        // Tổng xuất trong kỳ
+	Sales           InventoryMovementTotals `json:"sales"`            // Bán hàng, gồm cả hàng hồi về từ đơn huỷ hoặc xoá
+	Returns         InventoryMovementTotals `json:"returns"`          // Khách trả hàng
+	Purchases       InventoryMovementTotals `json:"purchases"`        // Nhập hàng từ phiếu nhập
+	Transfers       InventoryMovementTotals `json:"transfers"`        // Chuyển kho
+	Adjustments     InventoryMovementTotals `json:"adjustments"`      // Điều chỉnh tay, kiểm kê, tồn đầu khi nhập file
+	ClosingBalance  int                     `json:"closing_balance"`  // Tồn cuối kỳ = tồn đầu kỳ + nhập - xuất
+	CurrentQuantity int                     `json:"current_quantity"` // Tồn kho hiện tại
+	IsConsistent    bool                    `json:"is_consistent"`    // false nếu chuỗi final_quantity trong lịch sử không khớp với tồn kho
+}
+
+type GetInventoryMovementsResponse struct {
+	FromDate time.Time                   `json:"from_date"`
+	ToDate   time.Time                   `json:"to_date"`
+	Products []InventoryMovementResponse `json:"products"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+type CreateOrderReturnRequest struct {
+	ReturnedAt time.Time                `json:"returned_at" binding:"required"`      // Ngày khách trả hàng
+	Reason     *string                  `json:"reason"`                              // Lý do trả hàng
+	Items      []OrderReturnItemRequest `json:"items" binding:"required,min=1,dive"` // Danh sách dòng hàng bị trả
+}
+
+type OrderReturnItemRequest struct {
+	OrderItemID int `json:"order_item_id" binding:"required"`  // Mã dòng hàng trong đơn
+	Quantity    int `json:"quantity" binding:"required,min=1"` // Số lượng trả lại
+}
+
+type OrderReturnResponse struct {
+	ID                int                       `json:"id"`
+	OrderID           int                       `json:"order_id"`
+	ReturnedAt        time.Time                 `json:"returned_at"`
+	Reason            *string                   `json:"reason"`
+	TotalRefundAmount int                       `json:"total_refund_amount"`           // Tổng giá trị ghi có cho khách (VND)
+	TotalOriginalCost *int                      `json:"total_original_cost,omitempty"` // Tổng giá gốc của hàng trả (VND)
+	CreatedBy         int                       `json:"created_by"`
+	CreatedByName     string                    `json:"created_by_name"`
+	CreatedAt         time.Time                 `json:"created_at"`
+	Items             []OrderReturnItemResponse `json:"items"`
+}
+
+type OrderReturnItemResponse struct {
+	ID           int    `json:"id"`
+	OrderItemID  int    `json:"order_item_id"`
+	ProductID    int    `json:"product_id"`
+	Quantity     int    `json:"quantity"`
+	RefundAmount int    `json:"refund_amount"`           // Giá trị ghi có cho khách (VND)
+	OriginalCost *int   `json:"original_cost,omitempty"` // Giá gốc của hàng trả (VND)
+	ExportFrom   string `json:"export_from"`
+}
+
+type GetAllOrderReturnsResponse struct {
+	TotalReturnedRevenue int                   `json:"total_returned_revenue"`        // Tổng doanh thu bị trừ do trả hàng (VND)
+	TotalReturnedCost    *int                  `json:"total_returned_cost,omitempty"` // Tổng giá gốc của hàng trả (VND)
+	Returns              []OrderReturnResponse `json:"returns"`
+}
@@ -30,7 +30,6 @@ type CreateOrderRequest struct {
 	CustomerID           int                `json:"customer_id" binding:"required"`      // Mã khách hàng
 	OrderDate            time.Time          `json:"order_date" binding:"required"`       // Ngày đặt hàng
 	DeliveryStatus       string             `json:"delivery_status" binding:"required"`  // Trạng thái giao hàng
-	DebtStatus           *string            `json:"debt_status"`                         // Trạng thái công nợ
 	StatusTransitionedAt *time.Time         `json:"status_transitioned_at"`              // Ngày chuyển trạng thái
 	AdditionalCost       int                `json:"additional_cost"`                     // Chi phí phát sinh thêm (VND)
 	AdditionalCostNote   *string            `json:"additional_cost_note"`                // Ghi chú cho chi phí phát sinh
@@ -42,14 +41,16 @@ type UpdateOrderRequest struct {
 	ID                   int        `json:"id" binding:"required"`  // Mã đơn hàng
 	CustomerID           int        `json:"customer_id"`            // Mã khách hàng
 	OrderDate            time.Time  `json:"order_date"`             // Ngày đặt hàng
-	DeliveryStatus       string     `json:"delivery_status"`        // Trạng thái giao hàng
-	DebtStatus           *string    `json:"debt_status"`            // Trạng thái công nợ
 	StatusTransitionedAt *time.Time `json:"status_transitioned_at"` // Ngày chuyển trạng thái
 	AdditionalCost       *int       `json:"additional_cost"`        // Chi phí phát sinh thêm (VND)
 	AdditionalCostNote   *string    `json:"additional_cost_note"`   // Ghi chú cho chi phí phát sinh
 	TaxPercent           *int       `json:"tax_percent"`            // Phần trăm thuế (%)
 }
 
+type UpdateOrderItemsRequest struct {
+	OrderItems []OrderItemRequest `json:"order_items" binding:"required,dive"` // Danh sách sản phẩm mới của đơn (thay thế toàn bộ)
+}
+
 type OrderItemRequest struct {
 	ProductID     int    `json:"product_id" binding:"required"`    // Mã sản phẩm
 	NumberOfBoxes *int   `json:"number_of_boxes"`                  // Số thùng
@@ -60,6 +61,7 @@ type OrderItemRequest struct {
 	FinalAmount   *int   `json:"final_amount"`                     // Số tiền cuối cùng sau khi trừ chiết khấu (VND)
 	Version       string `json:"version" binding:"required"`       // Version (UUID) của inventory để kiểm tra optimistic lock
 	ExportFrom    string `json:"export_from" binding:"required"`   // Nguồn xuất: INVENTORY hoặc EXTERNAL
+	WarehouseID   *int   `json:"warehouse_id"`                     // Kho xuất hàng khi xuất từ kho, mặc định là kho mặc định
 }
 
 type OrderResponse struct {
@@ -68,6 +70,8 @@ type OrderResponse struct {
 	DeliveryStatus       string              `json:"delivery_status"`
 	DebtStatus           *string             `json:"debt_status"`
 	StatusTransitionedAt *time.Time          `json:"status_transitioned_at"`
+	CancelledAt          *time.Time          `json:"cancelled_at,omitempty"`
+	CancellationReason   *string             `json:"cancellation_reason,omitempty"`
 	AdditionalCost       int                 `json:"additional_cost"`
 	AdditionalCostNote   *string             `json:"additional_cost_note"`
 	Customer             CustomerResponse    `json:"customer"`
@@ -76,10 +80,13 @@ type OrderResponse struct {
 	TotalAmount          *int                `json:"total_amount,omitempty"`
 	ProductCount         *int                `json:"product_count,omitempty"`
 	TaxPercent           *int                `json:"tax_percent,omitempty"`
+	PaidAmount           *int                `json:"paid_amount,omitempty"`        // Số tiền đã thanh toán (VND)
+	OutstandingAmount    *int                `json:"outstanding_amount,omitempty"` // Số tiền còn nợ (VND)
 	// Profit/Loss fields for total order
 	TotalProfitLoss           *int     `json:"total_profit_loss,omitempty"`            // Total profit/loss for the order
 	TotalProfitLossPercentage *float64 `json:"total_profit_loss_percentage,omitempty"` // Total profit/loss percentage for the order
 	TotalSalesRevenue         int      `json:"total_sales_revenue"`                    // Total sales revenue for the order
+	TotalReturnedRevenue      int      `json:"total_returned_revenue"`                 // Revenue credited back to the customer for returned goods
 }
 
 type OrderItemResponse struct {
@@ -94,18 +101,62 @@ type OrderItemResponse struct {
 	Discount      int    `json:"discount"`
 	FinalAmount   *int   `json:"final_amount"`
 	ExportFrom    string `json:"export_from"`
+	WarehouseID   *int   `json:"warehouse_id,omitempty"`
+	PriceSource   string `json:"price_source"` // LIST: bán theo bảng giá, MANUAL: giá nhập tay
+	// Quantity the customer has sent back
+	ReturnedQuantity int `json:"returned_quantity"`
 	// Profit/Loss fields
 	OriginalPrice        *int     `json:"original_price,omitempty"`         // Product's original price
 	ProfitLoss           *int     `json:"profit_loss,omitempty"`            // Profit/Loss amount for this item
 	ProfitLossPercentage *float64 `json:"profit_loss_percentage,omitempty"` // Profit/Loss percentage for this item
 }
 
+type GetAllOrdersRequest struct {
+	CustomerID       int        // Lọc theo mã khách hàng
+	DeliveryStatuses []string   // Lọc theo trạng thái giao hàng
+	DebtStatuses     []string   // Lọc theo trạng thái công nợ
+	Search           string     // Tìm theo tên hoặc số điện thoại khách hàng
+	MinAmount        *int       // Tổng tiền tối thiểu (VND)
+	MaxAmount        *int       // Tổng tiền tối đa (VND)
+	FromDate         *time.Time // Từ ngày
+	ToDate           *time.Time // Đến ngày
+	SortBy           string     // order_date_asc, order_date_desc, mặc định id giảm dần
+	Limit            int        // Số đơn mỗi trang
+	Cursor           string     // Con trỏ trang tiếp theo lấy từ next_cursor
+}
+
 type GetAllOrdersResponse struct {
-	AllOrderTotalAmount     int             `json:"all_order_total_amount"`
-	AllOrderTotalProfitLoss int             `json:"all_order_total_profit_loss"`
+	AllOrderTotalAmount     int             `json:"all_order_total_amount"`                // Tổng tiền của tất cả đơn khớp bộ lọc
+	AllOrderTotalProfitLoss *int            `json:"all_order_total_profit_loss,omitempty"` // Tổng lãi/lỗ của tất cả đơn khớp bộ lọc
+	TotalCount              int             `json:"total_count"`                           // Số đơn khớp bộ lọc
+	NextCursor              *string         `json:"next_cursor"`                           // Con trỏ để lấy trang tiếp theo, null nếu hết
 	Orders                  []OrderResponse `json:"orders"`
 }
 
 type GetOneOrderResponse struct {
 	Order OrderResponse `json:"order"`
 }
+
+type UpdateOrderStatusRequest struct {
+	DeliveryStatus string  `json:"delivery_status" binding:"required"` // Trạng thái giao hàng mới
+	Note           *string `json:"note"`                               // Ghi chú khi chuyển trạng thái
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason" binding:"required"` // Lý do huỷ đơn
+}
+
+type OrderStatusHistoryResponse struct {
+	ID            int       `json:"id"`
+	OrderID       int       `json:"order_id"`
+	FromStatus    *string   `json:"from_status"`
+	ToStatus      string    `json:"to_status"`
+	ChangedBy     int       `json:"changed_by"`
+	ChangedByName string    `json:"changed_by_name"`
+	ChangedAt     time.Time `json:"changed_at"`
+	Note          *string   `json:"note"`
+}
+
+type GetAllOrderStatusHistoriesResponse struct {
+	StatusHistories []OrderStatusHistoryResponse `json:"status_histories"`
+}
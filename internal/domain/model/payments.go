@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+type CreatePaymentRequest struct {
+	Amount int       `json:"amount" binding:"required"`  // Số tiền thanh toán (VND)
+	Method string    `json:"method" binding:"required"`  // Phương thức: CASH, BANK_TRANSFER, OTHER
+	PaidAt time.Time `json:"paid_at" binding:"required"` // Ngày thanh toán
+	Note   *string   `json:"note"`                       // Ghi chú
+}
+
+type PaymentResponse struct {
+	ID             int       `json:"id"`
+	OrderID        int       `json:"order_id"`
+	Amount         int       `json:"amount"`
+	Method         string    `json:"method"`
+	PaidAt         time.Time `json:"paid_at"`
+	Note           *string   `json:"note"`
+	RecordedBy     int       `json:"recorded_by"`
+	RecordedByName string    `json:"recorded_by_name"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type GetAllPaymentsResponse struct {
+	TotalAmount       int               `json:"total_amount"`       // Tổng tiền đơn hàng (VND)
+	PaidAmount        int               `json:"paid_amount"`        // Đã thanh toán (VND)
+	OutstandingAmount int               `json:"outstanding_amount"` // Còn nợ (VND)
+	DebtStatus        string            `json:"debt_status"`        // UNPAID, PARTIAL, PAID
+	Payments          []PaymentResponse `json:"payments"`
+}
+
+type OrderBalanceResponse struct {
+	OrderID           int       `json:"order_id"`
+	OrderDate         time.Time `json:"order_date"`
+	DeliveryStatus    string    `json:"delivery_status"`
+	DebtStatus        string    `json:"debt_status"`
+	TotalAmount       int       `json:"total_amount"`
+	PaidAmount        int       `json:"paid_amount"`
+	OutstandingAmount int       `json:"outstanding_amount"`
+}
+
+type CustomerBalanceResponse struct {
+	Customer          CustomerResponse       `json:"customer"`
+	TotalAmount       int                    `json:"total_amount"`       // Tổng tiền các đơn hàng (VND)
+	PaidAmount        int                    `json:"paid_amount"`        // Tổng đã thanh toán (VND)
+	OutstandingAmount int                    `json:"outstanding_amount"` // Tổng còn nợ (VND)
+	OpenOrders        []OrderBalanceResponse `json:"open_orders"`        // Các đơn hàng còn nợ
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+type CreateProductPriceRequest struct {
+	ProductID     int        `json:"product_id" binding:"required"`          // Mã sản phẩm
+	CustomerID    *int       `json:"customer_id"`                            // Khách hàng được áp giá riêng, bỏ trống là giá bán mặc định
+	SellingPrice  *int       `json:"selling_price" binding:"required,min=0"` // Giá bán (VND)
+	EffectiveFrom *time.Time `json:"effective_from"`                         // Ngày bắt đầu áp dụng, mặc định là hôm nay
+}
+
+type ProductPriceResponse struct {
+	ID            int       `json:"id"`
+	ProductID     int       `json:"product_id"`
+	ProductName   string    `json:"product_name"`   // Tên sản phẩm
+	CustomerID    *int      `json:"customer_id"`    // Khách hàng được áp giá riêng, null là giá bán mặc định
+	CustomerName  *string   `json:"customer_name"`  // Tên khách hàng
+	SellingPrice  int       `json:"selling_price"`  // Giá bán (VND)
+	EffectiveFrom time.Time `json:"effective_from"` // Ngày bắt đầu áp dụng
+	CreatedByName string    `json:"created_by_name"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type GetAllProductPricesResponse struct {
+	ProductPrices []ProductPriceResponse `json:"product_prices"`
+}
+
+type CreateCustomerDiscountRequest struct {
+	CustomerID      int        `json:"customer_id" binding:"required"`                    // Mã khách hàng
+	DiscountPercent *int       `json:"discount_percent" binding:"required,min=0,max=100"` // Chiết khấu mặc định (%)
+	EffectiveFrom   *time.Time `json:"effective_from"`                                    // Ngày bắt đầu áp dụng, mặc định là hôm nay
+}
+
+type CustomerDiscountResponse struct {
+	ID              int       `json:"id"`
+	CustomerID      int       `json:"customer_id"`
+	CustomerName    string    `json:"customer_name"`    // Tên khách hàng
+	DiscountPercent int       `json:"discount_percent"` // Chiết khấu mặc định (%)
+	EffectiveFrom   time.Time `json:"effective_from"`   // Ngày bắt đầu áp dụng
+	CreatedByName   string    `json:"created_by_name"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type GetAllCustomerDiscountsResponse struct {
+	CustomerDiscounts []CustomerDiscountResponse `json:"customer_discounts"`
+}
+
+type SuggestedPriceResponse struct {
+	CustomerID    int        `json:"customer_id"`
+	ProductID     int        `json:"product_id"`
+	Date          time.Time  `json:"date"`           // Ngày áp giá
+	SellingPrice  *int       `json:"selling_price"`  // Giá bán gợi ý (VND), null nếu sản phẩm chưa có giá
+	Discount      int        `json:"discount"`       // Chiết khấu gợi ý (%)
+	PriceType     string     `json:"price_type"`     // CUSTOMER_PRICE: giá riêng của khách, DEFAULT_PRICE: giá bán mặc định, NONE: chưa có giá
+	EffectiveFrom *time.Time `json:"effective_from"` // Ngày bắt đầu áp dụng của giá gợi ý
+}
@@ -1,29 +1,50 @@
 package model
 
+import "time"
+
 type CreateProductRequest struct {
-	Name          string `json:"name" binding:"required"`           // Tên sản phẩm
-	Spec          int    `json:"spec"`                              // Quy cách
-	OriginalPrice int    `json:"original_price" binding:"required"` // Giá gốc của sản phẩm (VND)
+	Name            string `json:"name" binding:"required"`             // Tên sản phẩm
+	Spec            int    `json:"spec"`                                // Quy cách
+	OriginalPrice   int    `json:"original_price" binding:"required"`   // Giá gốc của sản phẩm (VND)
+	MinStock        *int   `json:"min_stock" binding:"omitempty,min=0"` // Tồn kho tối thiểu, mặc định 10
+	ReorderQuantity int    `json:"reorder_quantity" binding:"min=0"`    // Số lượng nhập thêm tối thiểu mỗi lần
 }
 
 type UpdateProductRequest struct {
-	ID            int    `json:"id" binding:"required"`
-	Name          string `json:"name" binding:"required"`           // Tên sản phẩm
-	Spec          int    `json:"spec"`                              // Quy cách
-	OriginalPrice int    `json:"original_price" binding:"required"` // Giá gốc của sản phẩm (VND)
+	ID              int    `json:"id" binding:"required"`
+	Name            string `json:"name" binding:"required"`                    // Tên sản phẩm
+	Spec            int    `json:"spec"`                                       // Quy cách
+	OriginalPrice   int    `json:"original_price" binding:"required"`          // Giá gốc của sản phẩm (VND)
+	MinStock        *int   `json:"min_stock" binding:"omitempty,min=0"`        // Tồn kho tối thiểu, giữ nguyên nếu bỏ trống
+	ReorderQuantity *int   `json:"reorder_quantity" binding:"omitempty,min=0"` // Số lượng nhập thêm tối thiểu, giữ nguyên nếu bỏ trống
 }
 
 type ProductResponse struct {
-	ID            int            `json:"id"`
-	Name          string         `json:"name"`                // Tên sản phẩm
-	Spec          int            `json:"spec"`                // Quy cách
-	OriginalPrice int            `json:"original_price"`      // Giá gốc của sản phẩm (VND)
-	Inventory     *InventoryInfo `json:"inventory,omitempty"` // Thông tin tồn kho
+	ID              int                      `json:"id"`
+	Name            string                   `json:"name"`                     // Tên sản phẩm
+	Spec            int                      `json:"spec"`                     // Quy cách
+	OriginalPrice   *int                     `json:"original_price,omitempty"` // Giá gốc của sản phẩm (VND)
+	AverageCost     *int                     `json:"average_cost,omitempty"`   // Giá vốn bình quân gia quyền (VND)
+	MinStock        int                      `json:"min_stock"`                // Tồn kho tối thiểu
+	ReorderQuantity int                      `json:"reorder_quantity"`         // Số lượng nhập thêm tối thiểu mỗi lần
+	Inventory       *InventoryInfo           `json:"inventory,omitempty"`      // Thông tin tồn kho ở kho mặc định
+	Inventories     []WarehouseInventoryInfo `json:"inventories,omitempty"`    // Tồn kho ở từng kho
 }
 
 type InventoryInfo struct {
-	Quantity int    `json:"quantity"` // Số lượng tồn kho
-	Version  string `json:"version"`  // Version để optimistic lock
+	Quantity          int    `json:"quantity"`           // Số lượng tồn kho
+	ReservedQuantity  int    `json:"reserved_quantity"`  // Số lượng đang giữ cho đơn chờ giao
+	AvailableQuantity int    `json:"available_quantity"` // Số lượng còn có thể bán
+	Version           string `json:"version"`            // Version để optimistic lock
+}
+
+type WarehouseInventoryInfo struct {
+	WarehouseID       int    `json:"warehouse_id"`
+	WarehouseName     string `json:"warehouse_name"`     // Tên kho
+	Quantity          int    `json:"quantity"`           // Số lượng tồn kho
+	ReservedQuantity  int    `json:"reserved_quantity"`  // Số lượng đang giữ cho đơn chờ giao
+	AvailableQuantity int    `json:"available_quantity"` // Số lượng còn có thể bán
+	Version           string `json:"version"`            // Version để optimistic lock
 }
 
 type GetAllProductsResponse struct {
@@ -33,3 +54,42 @@ type GetAllProductsResponse struct {
 type GetOneProductResponse struct {
 	Product ProductResponse `json:"product"`
 }
+
+type ProductCostHistoryResponse struct {
+	ID                  int       `json:"id"`
+	Source              string    `json:"source"`                 // Nguồn nhập: PURCHASE_ORDER, INVENTORY_ADJUSTMENT, OPENING_STOCK
+	ReferenceID         *int      `json:"reference_id,omitempty"` // Mã phiếu nhập nếu nhập từ phiếu nhập
+	QuantityBefore      int       `json:"quantity_before"`        // Số lượng tồn trước khi nhập
+	ReceivedQuantity    int       `json:"received_quantity"`      // Số lượng nhập
+	UnitCost            int       `json:"unit_cost"`              // Giá nhập mỗi đơn vị (VND)
+	PreviousAverageCost int       `json:"previous_average_cost"`  // Giá vốn bình quân trước khi nhập (VND)
+	AverageCost         int       `json:"average_cost"`           // Giá vốn bình quân sau khi nhập (VND)
+	CreatedBy           *int      `json:"created_by,omitempty"`
+	CreatedByName       *string   `json:"created_by_name,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+type GetProductCostHistoriesResponse struct {
+	ProductID   int                          `json:"product_id"`
+	AverageCost int                          `json:"average_cost"` // Giá vốn bình quân hiện tại (VND)
+	Histories   []ProductCostHistoryResponse `json:"histories"`
+}
+
+type LowStockProductResponse struct {
+	ProductID                int     `json:"product_id"`
+	ProductName              string  `json:"product_name"`               // Tên sản phẩm
+	Spec                     int     `json:"spec"`                       // Quy cách
+	Quantity                 int     `json:"quantity"`                   // Số lượng thực có ở tất cả các kho
+	ReservedQuantity         int     `json:"reserved_quantity"`          // Số lượng đang giữ cho đơn chờ giao
+	AvailableQuantity        int     `json:"available_quantity"`         // Số lượng còn có thể bán
+	MinStock                 int     `json:"min_stock"`                  // Tồn kho tối thiểu
+	ReorderQuantity          int     `json:"reorder_quantity"`           // Số lượng nhập thêm tối thiểu mỗi lần
+	SoldQuantity             int     `json:"sold_quantity"`              // Số lượng bán ra trong khoảng thời gian xét
+	DailySalesVelocity       float64 `json:"daily_sales_velocity"`       // Số lượng bán trung bình mỗi ngày
+	SuggestedReorderQuantity int     `json:"suggested_reorder_quantity"` // Số lượng đề xuất nhập thêm
+}
+
+type GetLowStockProductsResponse struct {
+	Days     int                       `json:"days"` // Số ngày bán hàng dùng để tính tốc độ bán
+	Products []LowStockProductResponse `json:"products"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+type CreatePurchaseOrderRequest struct {
+	SupplierID   int                        `json:"supplier_id" binding:"required"`      // Mã nhà cung cấp
+	WarehouseID  *int                       `json:"warehouse_id"`                        // Kho nhận hàng, mặc định là kho mặc định
+	ExpectedDate *time.Time                 `json:"expected_date"`                       // Ngày dự kiến nhận hàng
+	Note         *string                    `json:"note"`                                // Ghi chú
+	Items        []PurchaseOrderItemRequest `json:"items" binding:"required,min=1,dive"` // Danh sách hàng đặt mua
+}
+
+type PurchaseOrderItemRequest struct {
+	ProductID     int `json:"product_id" binding:"required"`     // Mã sản phẩm
+	Quantity      int `json:"quantity" binding:"required,min=1"` // Số lượng đặt mua
+	PurchasePrice int `json:"purchase_price" binding:"min=0"`    // Giá nhập mỗi đơn vị (VND)
+}
+
+type ReceivePurchaseOrderRequest struct {
+	Items               []ReceivePurchaseOrderItemRequest `json:"items" binding:"required,min=1,dive"` // Các dòng hàng nhận lần này
+	UpdateOriginalPrice bool                              `json:"update_original_price"`               // Cập nhật giá gốc sản phẩm theo giá nhập
+	Note                *string                           `json:"note"`                                // Ghi chú thêm vào lịch sử kho
+}
+
+type ReceivePurchaseOrderItemRequest struct {
+	PurchaseOrderItemID int `json:"purchase_order_item_id" binding:"required"` // Mã dòng hàng trong phiếu nhập
+	Quantity            int `json:"quantity" binding:"required,min=1"`         // Số lượng nhận vào kho
+}
+
+type PurchaseOrderResponse struct {
+	ID            int                         `json:"id"`
+	SupplierID    int                         `json:"supplier_id"`
+	SupplierName  string                      `json:"supplier_name"`          // Tên nhà cung cấp
+	WarehouseID   int                         `json:"warehouse_id"`           // Kho nhận hàng
+	WarehouseName string                      `json:"warehouse_name"`         // Tên kho nhận hàng
+	Status        string                      `json:"status"`                 // PENDING, PARTIALLY_RECEIVED, RECEIVED, CANCELLED
+	ExpectedDate  *time.Time                  `json:"expected_date"`          // Ngày dự kiến nhận hàng
+	Note          *string                     `json:"note"`                   // Ghi chú
+	TotalAmount   *int                        `json:"total_amount,omitempty"` // Tổng tiền hàng theo giá nhập (VND)
+	CreatedBy     int                         `json:"created_by"`
+	CreatedByName string                      `json:"created_by_name"`
+	CreatedAt     time.Time                   `json:"created_at"`
+	ReceivedAt    *time.Time                  `json:"received_at"`  // Thời điểm nhận đủ hàng
+	CancelledAt   *time.Time                  `json:"cancelled_at"` // Thời điểm huỷ phiếu nhập
+	Items         []PurchaseOrderItemResponse `json:"items,omitempty"`
+}
+
+type PurchaseOrderItemResponse struct {
+	ID                int    `json:"id"`
+	ProductID         int    `json:"product_id"`
+	ProductName       string `json:"product_name"`             // Tên sản phẩm
+	Quantity          int    `json:"quantity"`                 // Số lượng đặt mua
+	ReceivedQuantity  int    `json:"received_quantity"`        // Số lượng đã nhận vào kho
+	RemainingQuantity int    `json:"remaining_quantity"`       // Số lượng còn chờ nhận
+	PurchasePrice     *int   `json:"purchase_price,omitempty"` // Giá nhập mỗi đơn vị (VND)
+}
+
+type GetAllPurchaseOrdersResponse struct {
+	PurchaseOrders []PurchaseOrderResponse `json:"purchase_orders"`
+}
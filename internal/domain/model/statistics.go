@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type DashboardStatsResponse struct {
 	TotalProducts       int `json:"total_products"`
 	TotalCustomers      int `json:"total_customers"`
@@ -7,4 +9,59 @@ type DashboardStatsResponse struct {
 	LowStockProducts    int `json:"low_stock_products"`
 	TotalOrders         int `json:"total_orders"`
 	PendingOrders       int `json:"pending_orders"`
+	CancelledOrders     int `json:"cancelled_orders"`
+}
+
+type ReportFigures struct {
+	OrderCount           int     `json:"order_count"`            // Số đơn hàng
+	Revenue              int     `json:"revenue"`                // Doanh thu sau chiết khấu, đã trừ hàng trả (VND)
+	OriginalCost         int     `json:"original_cost"`          // Giá vốn theo giá gốc lúc bán (VND)
+	AdditionalCost       int     `json:"additional_cost"`        // Chi phí phát sinh (VND)
+	Tax                  int     `json:"tax"`                    // Thuế (VND)
+	ProfitLoss           int     `json:"profit_loss"`            // Lãi/lỗ (VND)
+	ProfitLossPercentage float64 `json:"profit_loss_percentage"` // Lãi/lỗ so với giá vốn (%)
+}
+
+type PeriodReportRow struct {
+	Period string `json:"period"` // Ngày (YYYY-MM-DD), tuần (YYYY-Www) hoặc tháng (YYYY-MM)
+	ReportFigures
+}
+
+type CustomerReportRow struct {
+	CustomerID   int    `json:"customer_id"`
+	CustomerName string `json:"customer_name"`
+	ReportFigures
+}
+
+type ProductReportRow struct {
+	ProductID            int     `json:"product_id"`
+	ProductName          string  `json:"product_name"`
+	OrderCount           int     `json:"order_count"`            // Số đơn có sản phẩm
+	QuantitySold         int     `json:"quantity_sold"`          // Số lượng đã bán
+	QuantityReturned     int     `json:"quantity_returned"`      // Số lượng khách trả
+	Revenue              int     `json:"revenue"`                // Doanh thu sau chiết khấu, đã trừ hàng trả (VND)
+	OriginalCost         int     `json:"original_cost"`          // Giá vốn theo giá gốc lúc bán (VND)
+	ProfitLoss           int     `json:"profit_loss"`            // Lãi/lỗ (VND)
+	ProfitLossPercentage float64 `json:"profit_loss_percentage"` // Lãi/lỗ so với giá vốn (%)
+}
+
+type GetPeriodReportResponse struct {
+	FromDate time.Time         `json:"from_date"`
+	ToDate   time.Time         `json:"to_date"`
+	GroupBy  string            `json:"group_by"` // day, week, month
+	Total    ReportFigures     `json:"total"`
+	Rows     []PeriodReportRow `json:"rows"`
+}
+
+type GetCustomerReportResponse struct {
+	FromDate time.Time           `json:"from_date"`
+	ToDate   time.Time           `json:"to_date"`
+	Total    ReportFigures       `json:"total"`
+	Rows     []CustomerReportRow `json:"rows"`
+}
+
+type GetProductReportResponse struct {
+	FromDate time.Time          `json:"from_date"`
+	ToDate   time.Time          `json:"to_date"`
+	Rows     []ProductReportRow `json:"rows"`
 }
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+type OpenStocktakeRequest struct {
+	WarehouseID *int    `json:"warehouse_id"` // Kho được kiểm kê, mặc định là kho mặc định
+	Note        *string `json:"note"`         // Ghi chú
+}
+
+type UpdateStocktakeCountsRequest struct {
+	Items []StocktakeCountRequest `json:"items" binding:"required,min=1,dive"` // Các sản phẩm đếm lần này
+}
+
+type StocktakeCountRequest struct {
+	ProductID       int  `json:"product_id" binding:"required"`             // Mã sản phẩm
+	CountedQuantity *int `json:"counted_quantity" binding:"required,min=0"` // Số lượng đếm thực tế
+}
+
+type StocktakeResponse struct {
+	ID            int                     `json:"id"`
+	WarehouseID   int                     `json:"warehouse_id"`   // Kho được kiểm kê
+	WarehouseName string                  `json:"warehouse_name"` // Tên kho được kiểm kê
+	Status        string                  `json:"status"`         // OPEN, POSTED, CANCELLED
+	Note          *string                 `json:"note"`           // Ghi chú
+	CreatedBy     int                     `json:"created_by"`
+	CreatedByName string                  `json:"created_by_name"`
+	CreatedAt     time.Time               `json:"created_at"`
+	PostedBy      *int                    `json:"posted_by"`    // Người chốt phiếu kiểm kê
+	PostedAt      *time.Time              `json:"posted_at"`    // Thời điểm chốt và điều chỉnh tồn kho
+	CancelledAt   *time.Time              `json:"cancelled_at"` // Thời điểm huỷ phiếu kiểm kê
+	Items         []StocktakeItemResponse `json:"items,omitempty"`
+}
+
+type StocktakeItemResponse struct {
+	ProductID        int        `json:"product_id"`
+	ProductName      string     `json:"product_name"`                // Tên sản phẩm
+	SnapshotQuantity int        `json:"snapshot_quantity"`           // Số lượng trên sổ sách lúc chụp
+	CountedQuantity  *int       `json:"counted_quantity"`            // Số lượng đếm thực tế, null nếu chưa đếm
+	Variance         *int       `json:"variance"`                    // Chênh lệch = đếm thực tế - sổ sách, null nếu chưa đếm
+	NeedsRecount     bool       `json:"needs_recount"`               // Tồn kho đã thay đổi từ lúc chụp, cần đếm lại trước khi chốt
+	BelowReserved    bool       `json:"below_reserved"`              // Số lượng đếm ít hơn số lượng đang giữ cho đơn chờ giao, cần xử lý các đơn đó trước khi chốt
+	ReservedQuantity *int       `json:"reserved_quantity,omitempty"` // Số lượng đang giữ cho đơn chờ giao
+	CountedByName    *string    `json:"counted_by_name"`             // Người đếm
+	CountedAt        *time.Time `json:"counted_at"`                  // Thời điểm đếm
+}
+
+type GetAllStocktakesResponse struct {
+	Stocktakes []StocktakeResponse `json:"stocktakes"`
+}
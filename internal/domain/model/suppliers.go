@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+type CreateSupplierRequest struct {
+	Name    string  `json:"name" binding:"required"` // Tên nhà cung cấp
+	Phone   string  `json:"phone"`                   // Số điện thoại
+	Address string  `json:"address"`                 // Địa chỉ
+	Note    *string `json:"note"`                    // Ghi chú
+}
+
+type UpdateSupplierRequest struct {
+	Name    string  `json:"name"`    // Tên nhà cung cấp
+	Phone   string  `json:"phone"`   // Số điện thoại
+	Address string  `json:"address"` // Địa chỉ
+	Note    *string `json:"note"`    // Ghi chú
+}
+
+type SupplierResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`    // Tên nhà cung cấp
+	Phone     string    `json:"phone"`   // Số điện thoại
+	Address   string    `json:"address"` // Địa chỉ
+	Note      *string   `json:"note"`    // Ghi chú
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetAllSuppliersResponse struct {
+	Suppliers []SupplierResponse `json:"suppliers"`
+}
+
+type GetOneSupplierResponse struct {
+	Supplier SupplierResponse `json:"supplier"`
+}
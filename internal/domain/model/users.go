@@ -1,11 +1,81 @@
 package model
 
+import "time"
+
 type LoginRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 }
 
 type LoginResponse struct {
-	Token    string `json:"token"`
-	Username string `json:"username"`
+	Token                 string    `json:"token"`                    // Access token, hết hạn sau ít phút
+	TokenExpiresAt        time.Time `json:"token_expires_at"`         // Thời điểm access token hết hạn
+	RefreshToken          string    `json:"refresh_token"`            // Dùng một lần để lấy cặp token mới
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"` // Thời điểm refresh token hết hạn
+	Username              string    `json:"username"`
+	Role                  string    `json:"role"` // Vai trò: OWNER, SALES, WAREHOUSE, ACCOUNTANT
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+	AllDevices   bool   `json:"all_devices"` // Đăng xuất khỏi mọi thiết bị, vô hiệu mọi token đã cấp
+}
+
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required,max=50"`                             // Tên đăng nhập
+	Password string `json:"password" binding:"required,min=6"`                              // Mật khẩu
+	Role     string `json:"role" binding:"required,oneof=OWNER SALES WAREHOUSE ACCOUNTANT"` // Vai trò
+}
+
+type UpdateUserStatusRequest struct {
+	IsActive *bool `json:"is_active" binding:"required"` // false để khoá tài khoản, true để mở khoá
+}
+
+type ResetPasswordRequest struct {
+	NewPassword string `json:"new_password" binding:"required,min=6"` // Mật khẩu mới
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`       // Mật khẩu hiện tại
+	NewPassword string `json:"new_password" binding:"required,min=6"` // Mật khẩu mới
+}
+
+type UserResponse struct {
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`   // Tên đăng nhập
+	Role      string    `json:"role"`       // Vai trò
+	IsActive  bool      `json:"is_active"`  // Tài khoản đang hoạt động
+	CreatedAt time.Time `json:"created_at"` // Thời điểm tạo tài khoản
+}
+
+type GetAllUsersResponse struct {
+	Users []UserResponse `json:"users"`
+}
+
+type GetLoginAttemptsRequest struct {
+	Username  string     // Lọc theo tên đăng nhập đã nhập
+	IPAddress string     // Lọc theo địa chỉ IP
+	Success   *bool      // Lọc theo kết quả đăng nhập
+	FromDate  *time.Time // Từ ngày
+	ToDate    *time.Time // Đến ngày
+	Limit     int        // Số dòng tối đa
+}
+
+type LoginAttemptResponse struct {
+	ID            int       `json:"id"`
+	Username      string    `json:"username"`       // Tên đăng nhập đã nhập, có thể không tồn tại
+	IPAddress     string    `json:"ip_address"`     // Địa chỉ IP của client
+	UserAgent     string    `json:"user_agent"`     // Trình duyệt / thiết bị
+	UserID        *int      `json:"user_id"`        // Mã tài khoản nếu tên đăng nhập tồn tại
+	Success       bool      `json:"success"`        // Đăng nhập thành công hay không
+	FailureReason *string   `json:"failure_reason"` // UNKNOWN_USER, WRONG_PASSWORD, DISABLED, LOCKED
+	AttemptedAt   time.Time `json:"attempted_at"`   // Thời điểm đăng nhập
+}
+
+type GetLoginAttemptsResponse struct {
+	LoginAttempts []LoginAttemptResponse `json:"login_attempts"`
 }
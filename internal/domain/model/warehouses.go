@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+type CreateWarehouseRequest struct {
+	Name    string `json:"name" binding:"required"` // Tên kho
+	Address string `json:"address"`                 // Địa chỉ kho
+}
+
+type UpdateWarehouseRequest struct {
+	Name    string `json:"name"`    // Tên kho
+	Address string `json:"address"` // Địa chỉ kho
+}
+
+type WarehouseResponse struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`       // Tên kho
+	Address   string    `json:"address"`    // Địa chỉ kho
+	IsDefault bool      `json:"is_default"` // Kho mặc định khi không chọn kho
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetAllWarehousesResponse struct {
+	Warehouses []WarehouseResponse `json:"warehouses"`
+}
+
+type CreateStockTransferRequest struct {
+	ProductID       int     `json:"product_id" binding:"required"`        // Mã sản phẩm
+	FromWarehouseID int     `json:"from_warehouse_id" binding:"required"` // Kho chuyển đi
+	ToWarehouseID   int     `json:"to_warehouse_id" binding:"required"`   // Kho nhận
+	Quantity        int     `json:"quantity" binding:"required,min=1"`    // Số lượng chuyển
+	Note            *string `json:"note"`                                 // Ghi chú
+}
+
+type StockTransferResponse struct {
+	ID                int       `json:"id"`
+	ProductID         int       `json:"product_id"`
+	ProductName       string    `json:"product_name"`
+	FromWarehouseID   int       `json:"from_warehouse_id"` // Kho chuyển đi
+	FromWarehouseName string    `json:"from_warehouse_name"`
+	ToWarehouseID     int       `json:"to_warehouse_id"` // Kho nhận
+	ToWarehouseName   string    `json:"to_warehouse_name"`
+	Quantity          int       `json:"quantity"` // Số lượng chuyển
+	Note              *string   `json:"note"`
+	CreatedBy         int       `json:"created_by"`
+	CreatedByName     string    `json:"created_by_name"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type GetAllStockTransfersResponse struct {
+	StockTransfers []StockTransferResponse `json:"stock_transfers"`
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type AuditLogFilter struct {
+	UserID     int
+	Action     string
+	EntityType string
+	EntityID   int
+	RequestID  string
+	FromDate   *time.Time
+	ToDate     *time.Time
+	Limit      int
+}
+
+type AuditLogRepository interface {
+	GetAllWithFiltersQuery(ctx context.Context, filter AuditLogFilter, tx *sqlx.Tx) ([]entity.AuditLog, error)
+	CreateCommand(ctx context.Context, auditLog *entity.AuditLog, tx *sqlx.Tx) error
+}
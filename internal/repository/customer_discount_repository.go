@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type CustomerDiscountRepository interface {
+	GetAllByCustomerIDQuery(ctx context.Context, customerID int, tx *sqlx.Tx) ([]entity.CustomerDiscount, error)
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.CustomerDiscount, error)
+	GetOneByEffectiveFromQuery(ctx context.Context, customerID int, effectiveFrom time.Time, tx *sqlx.Tx) (*entity.CustomerDiscount, error)
+	GetEffectiveQuery(ctx context.Context, customerID int, date time.Time, tx *sqlx.Tx) (*entity.CustomerDiscount, error)
+	CreateCommand(ctx context.Context, customerDiscount *entity.CustomerDiscount, tx *sqlx.Tx) error
+	DeleteByIDCommand(ctx context.Context, id int, tx *sqlx.Tx) error
+	ReassignCustomerCommand(ctx context.Context, fromCustomerID int, toCustomerID int, tx *sqlx.Tx) error
+}
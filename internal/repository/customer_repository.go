@@ -9,7 +9,12 @@ import (
 
 type CustomerRepository interface {
 	GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Customer, error)
+	GetAllWithSearchQuery(ctx context.Context, search string, tx *sqlx.Tx) ([]entity.Customer, error)
+	GetDuplicatesByPhoneQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.CustomerDuplicate, error)
+	GetDuplicatesByNameQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.CustomerDuplicate, error)
 	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Customer, error)
+	GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Customer, error)
 	CreateCommand(ctx context.Context, customer *entity.Customer, tx *sqlx.Tx) error
 	UpdateCommand(ctx context.Context, customer *entity.Customer, tx *sqlx.Tx) error
+	SoftDeleteCommand(ctx context.Context, id int, mergedIntoID *int, tx *sqlx.Tx) error
 }
@@ -0,0 +1,96 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+)
+
+type AuditLogRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditLogRepository(db database.Db) repository.AuditLogRepository {
+	return &AuditLogRepository{db: db}
+}
+
+func (repo *AuditLogRepository) GetAllWithFiltersQuery(ctx context.Context, filter repository.AuditLogFilter, tx *sqlx.Tx) ([]entity.AuditLog, error) {
+	query := `SELECT a.*, u.username FROM audit_logs a
+		LEFT JOIN users u ON u.id = a.user_id
+		WHERE 1=1`
+	var args []interface{}
+
+	if filter.UserID != 0 {
+		query += " AND a.user_id = ?"
+		args = append(args, filter.UserID)
+	}
+	if filter.Action != "" {
+		query += " AND a.action = ?"
+		args = append(args, filter.Action)
+	}
+	if filter.EntityType != "" {
+		query += " AND a.entity_type = ?"
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		query += " AND a.entity_id = ?"
+		args = append(args, filter.EntityID)
+	}
+	if filter.RequestID != "" {
+		query += " AND a.request_id = ?"
+		args = append(args, filter.RequestID)
+	}
+	if filter.FromDate != nil {
+		startOfDay := time.Date(filter.FromDate.Year(), filter.FromDate.Month(), filter.FromDate.Day(), 0, 0, 0, 0, filter.FromDate.Location())
+		query += " AND a.created_at >= ?"
+		args = append(args, startOfDay)
+	}
+	if filter.ToDate != nil {
+		endOfDay := time.Date(filter.ToDate.Year(), filter.ToDate.Month(), filter.ToDate.Day(), 23, 59, 59, 999999999, filter.ToDate.Location())
+		query += " AND a.created_at <= ?"
+		args = append(args, endOfDay)
+	}
+
+	query += " ORDER BY a.created_at DESC, a.id DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	auditLogs := make([]entity.AuditLog, 0)
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &auditLogs, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &auditLogs, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return auditLogs, nil
+}
+
+func (repo *AuditLogRepository) CreateCommand(ctx context.Context, auditLog *entity.AuditLog, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO audit_logs(user_id, action, entity_type, entity_id, before_data, after_data, request_id, created_at)
+		VALUES (:user_id, :action, :entity_type, :entity_id, :before_data, :after_data, :request_id, :created_at)`
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, auditLog)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, auditLog)
+	}
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	auditLog.ID = int(lastID)
+	return nil
+}
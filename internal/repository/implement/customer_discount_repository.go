@@ -0,0 +1,136 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+)
+
+type CustomerDiscountRepository struct {
+	db *sqlx.DB
+}
+
+func NewCustomerDiscountRepository(db database.Db) repository.CustomerDiscountRepository {
+	return &CustomerDiscountRepository{db: db}
+}
+
+// customerDiscountSelect joins the customer and creator names
+const customerDiscountSelect = `SELECT cd.*, c.name AS customer_name, u.username AS created_by_name
+	FROM customer_discounts cd
+	JOIN customers c ON c.id = cd.customer_id
+	JOIN users u ON u.id = cd.created_by`
+
+// GetAllByCustomerIDQuery lists the discounts of one customer, or of every customer when customerID is 0,
+// with the latest effective date first
+func (repo *CustomerDiscountRepository) GetAllByCustomerIDQuery(ctx context.Context, customerID int, tx *sqlx.Tx) ([]entity.CustomerDiscount, error) {
+	query := customerDiscountSelect
+	var args []interface{}
+	if customerID != 0 {
+		query += " WHERE cd.customer_id = ?"
+		args = append(args, customerID)
+	}
+	query += " ORDER BY cd.customer_id, cd.effective_from DESC"
+
+	var customerDiscounts []entity.CustomerDiscount
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &customerDiscounts, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &customerDiscounts, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if customerDiscounts == nil {
+		return []entity.CustomerDiscount{}, nil
+	}
+	return customerDiscounts, nil
+}
+
+func (repo *CustomerDiscountRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.CustomerDiscount, error) {
+	return repo.getOne(ctx, customerDiscountSelect+" WHERE cd.id = ?", []interface{}{id}, tx)
+}
+
+func (repo *CustomerDiscountRepository) GetOneByEffectiveFromQuery(ctx context.Context, customerID int, effectiveFrom time.Time, tx *sqlx.Tx) (*entity.CustomerDiscount, error) {
+	return repo.getOne(ctx, customerDiscountSelect+" WHERE cd.customer_id = ? AND cd.effective_from = ?", []interface{}{customerID, effectiveFrom}, tx)
+}
+
+// GetEffectiveQuery returns the discount of a customer in force on the given date
+func (repo *CustomerDiscountRepository) GetEffectiveQuery(ctx context.Context, customerID int, date time.Time, tx *sqlx.Tx) (*entity.CustomerDiscount, error) {
+	query := customerDiscountSelect + " WHERE cd.customer_id = ? AND cd.effective_from <= ? ORDER BY cd.effective_from DESC LIMIT 1"
+	return repo.getOne(ctx, query, []interface{}{customerID, date}, tx)
+}
+
+func (repo *CustomerDiscountRepository) getOne(ctx context.Context, query string, args []interface{}, tx *sqlx.Tx) (*entity.CustomerDiscount, error) {
+	var customerDiscount entity.CustomerDiscount
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &customerDiscount, query, args...)
+	} else {
+		err = repo.db.GetContext(ctx, &customerDiscount, query, args...)
+	}
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &customerDiscount, nil
+}
+
+func (repo *CustomerDiscountRepository) CreateCommand(ctx context.Context, customerDiscount *entity.CustomerDiscount, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO customer_discounts(customer_id, discount_percent, effective_from, created_by) VALUES (:customer_id, :discount_percent, :effective_from, :created_by)`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, customerDiscount)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, customerDiscount)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "unique_customer_discount_effective_from") {
+			return &error_utils.ConstraintViolationError{Message: "Khách hàng đã có chiết khấu bắt đầu từ ngày này"}
+		}
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	customerDiscount.ID = int(lastID)
+	return nil
+}
+
+func (repo *CustomerDiscountRepository) DeleteByIDCommand(ctx context.Context, id int, tx *sqlx.Tx) error {
+	deleteQuery := `DELETE FROM customer_discounts WHERE id = ?`
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, deleteQuery, id)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, deleteQuery, id)
+	return err
+}
+
+// ReassignCustomerCommand moves the default discounts of a customer to another one, except on the dates
+// the other customer already has a discount starting
+func (repo *CustomerDiscountRepository) ReassignCustomerCommand(ctx context.Context, fromCustomerID int, toCustomerID int, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE customer_discounts SET customer_id = ?
+		WHERE customer_id = ? AND effective_from NOT IN (
+			SELECT effective_from FROM (
+				SELECT effective_from FROM customer_discounts WHERE customer_id = ?
+			) existing
+		)`
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, updateQuery, toCustomerID, fromCustomerID, toCustomerID)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, updateQuery, toCustomerID, fromCustomerID, toCustomerID)
+	return err
+}
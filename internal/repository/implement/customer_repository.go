@@ -3,6 +3,9 @@ package repositoryimplement
 import (
 	"context"
 	"database/sql"
+	"strings"
+
+	stringutils "github.com/pna/order-app-backend/internal/utils/string_utils"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pna/order-app-backend/internal/database"
@@ -19,15 +22,40 @@ func NewCustomerRepository(db database.Db) repository.CustomerRepository {
 	return &CustomerRepository{db: db}
 }
 
+// Vietnamese text is compared accent and case insensitively. The collation already folds the tone marks
+// but treats đ as its own letter, so it is spelled out as d first
+const (
+	customerFoldedName    = "REPLACE(REPLACE(c.name, 'đ', 'd'), 'Đ', 'D') COLLATE utf8mb4_0900_ai_ci"
+	customerFoldedAddress = "REPLACE(REPLACE(c.address, 'đ', 'd'), 'Đ', 'D') COLLATE utf8mb4_0900_ai_ci"
+)
+
 func (repo *CustomerRepository) GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Customer, error) {
+	return repo.GetAllWithSearchQuery(ctx, "", tx)
+}
+
+func (repo *CustomerRepository) GetAllWithSearchQuery(ctx context.Context, search string, tx *sqlx.Tx) ([]entity.Customer, error) {
 	var customers []entity.Customer
-	query := "SELECT * FROM customers ORDER BY id"
-	var err error
+	query := "SELECT c.* FROM customers c WHERE c.deleted_at IS NULL"
+	var args []interface{}
+
+	// Every word has to match the name, the address or the phone number
+	for _, word := range strings.Fields(search) {
+		pattern := stringutils.ContainsPattern(strings.NewReplacer("đ", "d", "Đ", "D").Replace(word))
+		condition := customerFoldedName + " LIKE ? OR " + customerFoldedAddress + " LIKE ?"
+		args = append(args, pattern, pattern)
+		if phone := stringutils.NormalizePhone(word); phone != "" {
+			condition += " OR c.normalized_phone LIKE ?"
+			args = append(args, stringutils.ContainsPattern(phone))
+		}
+		query += " AND (" + condition + ")"
+	}
+	query += " ORDER BY c.id"
 
+	var err error
 	if tx != nil {
-		err = tx.SelectContext(ctx, &customers, query)
+		err = tx.SelectContext(ctx, &customers, query, args...)
 	} else {
-		err = repo.db.SelectContext(ctx, &customers, query)
+		err = repo.db.SelectContext(ctx, &customers, query, args...)
 	}
 
 	if err != nil {
@@ -41,9 +69,54 @@ func (repo *CustomerRepository) GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([
 	return customers, nil
 }
 
+func (repo *CustomerRepository) GetDuplicatesByPhoneQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.CustomerDuplicate, error) {
+	return repo.getDuplicates(ctx, "c.normalized_phone", tx)
+}
+
+func (repo *CustomerRepository) GetDuplicatesByNameQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.CustomerDuplicate, error) {
+	return repo.getDuplicates(ctx, "TRIM("+customerFoldedName+")", tx)
+}
+
+// getDuplicates lists the active customers sharing the value of key with another active customer,
+// grouped by the oldest customer of each group
+func (repo *CustomerRepository) getDuplicates(ctx context.Context, key string, tx *sqlx.Tx) ([]entity.CustomerDuplicate, error) {
+	var duplicates []entity.CustomerDuplicate
+	query := `SELECT * FROM (
+		SELECT c.*, MIN(c.id) OVER w AS duplicate_of_id, COUNT(*) OVER w AS duplicate_count
+		FROM customers c
+		WHERE c.deleted_at IS NULL AND ` + key + ` <> ''
+		WINDOW w AS (PARTITION BY ` + key + `)
+	) d WHERE d.duplicate_count > 1 ORDER BY d.duplicate_of_id, d.id`
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &duplicates, query)
+	} else {
+		err = repo.db.SelectContext(ctx, &duplicates, query)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if duplicates == nil {
+		return []entity.CustomerDuplicate{}, nil
+	}
+
+	return duplicates, nil
+}
+
 func (repo *CustomerRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Customer, error) {
+	// Deleted customers are still returned, their orders keep pointing to them
+	return repo.getOne(ctx, "SELECT * FROM customers WHERE id = ?", id, tx)
+}
+
+func (repo *CustomerRepository) GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Customer, error) {
+	return repo.getOne(ctx, "SELECT * FROM customers WHERE id = ? FOR UPDATE", id, tx)
+}
+
+func (repo *CustomerRepository) getOne(ctx context.Context, query string, id int, tx *sqlx.Tx) (*entity.Customer, error) {
 	var customer entity.Customer
-	query := "SELECT * FROM customers WHERE id = ?"
 	var err error
 
 	if tx != nil {
@@ -63,7 +136,7 @@ func (repo *CustomerRepository) GetOneByIDQuery(ctx context.Context, id int, tx
 }
 
 func (repo *CustomerRepository) CreateCommand(ctx context.Context, customer *entity.Customer, tx *sqlx.Tx) error {
-	insertQuery := `INSERT INTO customers(name, phone, address) VALUES (:name, :phone, :address)`
+	insertQuery := `INSERT INTO customers(name, phone, normalized_phone, address) VALUES (:name, :phone, :normalized_phone, :address)`
 
 	var result sql.Result
 	var err error
@@ -90,7 +163,7 @@ func (repo *CustomerRepository) CreateCommand(ctx context.Context, customer *ent
 }
 
 func (repo *CustomerRepository) UpdateCommand(ctx context.Context, customer *entity.Customer, tx *sqlx.Tx) error {
-	updateQuery := `UPDATE customers SET name = :name, phone = :phone, address = :address WHERE id = :id`
+	updateQuery := `UPDATE customers SET name = :name, phone = :phone, normalized_phone = :normalized_phone, address = :address WHERE id = :id`
 
 	if tx != nil {
 		_, err := tx.NamedExecContext(ctx, updateQuery, customer)
@@ -99,3 +172,14 @@ func (repo *CustomerRepository) UpdateCommand(ctx context.Context, customer *ent
 	_, err := repo.db.NamedExecContext(ctx, updateQuery, customer)
 	return err
 }
+
+func (repo *CustomerRepository) SoftDeleteCommand(ctx context.Context, id int, mergedIntoID *int, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE customers SET deleted_at = NOW(), merged_into_id = ? WHERE id = ?`
+
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, updateQuery, mergedIntoID, id)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, updateQuery, mergedIntoID, id)
+	return err
+}
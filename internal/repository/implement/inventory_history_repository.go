@@ -3,6 +3,7 @@ package repositoryimplement
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pna/order-app-backend/internal/database"
@@ -18,15 +19,26 @@ func NewInventoryHistoryRepository(db database.Db) repository.InventoryHistoryRe
 	return &InventoryHistoryRepository{db: db}
 }
 
-func (repo *InventoryHistoryRepository) GetAllByProductIDQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.InventoryHistory, error) {
+// GetAllByProductIDQuery returns the history of a product in one warehouse, or in every warehouse when warehouseID is 0
+func (repo *InventoryHistoryRepository) GetAllByProductIDQuery(ctx context.Context, productID int, warehouseID int, tx *sqlx.Tx) ([]entity.InventoryHistory, error) {
 	var inventoryHistories []entity.InventoryHistory
-	query := "SELECT * FROM inventory_histories WHERE product_id = ? ORDER BY imported_at DESC"
+	query := `
+		SELECT ih.*, w.name AS warehouse_name
+		FROM inventory_histories ih
+		JOIN warehouses w ON w.id = ih.warehouse_id
+		WHERE ih.product_id = ?`
+	args := []interface{}{productID}
+	if warehouseID != 0 {
+		query += " AND ih.warehouse_id = ?"
+		args = append(args, warehouseID)
+	}
+	query += " ORDER BY ih.imported_at DESC, ih.id DESC"
 	var err error
 
 	if tx != nil {
-		err = tx.SelectContext(ctx, &inventoryHistories, query, productID)
+		err = tx.SelectContext(ctx, &inventoryHistories, query, args...)
 	} else {
-		err = repo.db.SelectContext(ctx, &inventoryHistories, query, productID)
+		err = repo.db.SelectContext(ctx, &inventoryHistories, query, args...)
 	}
 
 	if err != nil {
@@ -40,8 +52,124 @@ func (repo *InventoryHistoryRepository) GetAllByProductIDQuery(ctx context.Conte
 	return inventoryHistories, nil
 }
 
+// inventoryHistoryFilter narrows rows of the given table alias to one product and one warehouse, 0 meaning all
+func inventoryHistoryFilter(alias string, productID int, warehouseID int) (string, []interface{}) {
+	condition := ""
+	var args []interface{}
+	if productID != 0 {
+		condition += " AND " + alias + ".product_id = ?"
+		args = append(args, productID)
+	}
+	if warehouseID != 0 {
+		condition += " AND " + alias + ".warehouse_id = ?"
+		args = append(args, warehouseID)
+	}
+	return condition, args
+}
+
+// GetMovementSummariesQuery adds up the stock that came in and went out per product and movement type,
+// from fromDate inclusive to toDate exclusive
+func (repo *InventoryHistoryRepository) GetMovementSummariesQuery(ctx context.Context, fromDate time.Time, toDate time.Time, productID int, warehouseID int, tx *sqlx.Tx) ([]entity.InventoryMovementSummary, error) {
+	condition, filterArgs := inventoryHistoryFilter("ih", productID, warehouseID)
+	query := `SELECT ih.product_id, ih.movement_type,
+			COALESCE(SUM(CASE WHEN ih.quantity > 0 THEN ih.quantity ELSE 0 END), 0) AS quantity_in,
+			COALESCE(SUM(CASE WHEN ih.quantity < 0 THEN -ih.quantity ELSE 0 END), 0) AS quantity_out
+		FROM inventory_histories ih
+		WHERE ih.imported_at >= ? AND ih.imported_at < ?` + condition + `
+		GROUP BY ih.product_id, ih.movement_type`
+	args := append([]interface{}{fromDate, toDate}, filterArgs...)
+
+	var summaries []entity.InventoryMovementSummary
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &summaries, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &summaries, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if summaries == nil {
+		return []entity.InventoryMovementSummary{}, nil
+	}
+	return summaries, nil
+}
+
+// GetBalancesBeforeQuery returns the stock of each product just before the given time, taken from the final quantity
+// of the last history row of each warehouse. Products without history before that time are left out
+func (repo *InventoryHistoryRepository) GetBalancesBeforeQuery(ctx context.Context, before time.Time, productID int, warehouseID int, tx *sqlx.Tx) (map[int]int, error) {
+	condition, filterArgs := inventoryHistoryFilter("ih", productID, warehouseID)
+	query := `SELECT ih.product_id, COALESCE(SUM(ih.final_quantity), 0) AS balance
+		FROM inventory_histories ih
+		JOIN (
+			SELECT MAX(ih.id) AS last_id FROM inventory_histories ih
+			WHERE ih.imported_at < ?` + condition + `
+			GROUP BY ih.product_id, ih.warehouse_id
+		) l ON l.last_id = ih.id
+		GROUP BY ih.product_id`
+	args := append([]interface{}{before}, filterArgs...)
+
+	var rows []struct {
+		ProductID int `db:"product_id"`
+		Balance   int `db:"balance"`
+	}
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &rows, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &rows, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[int]int)
+	for _, row := range rows {
+		balances[row.ProductID] = row.Balance
+	}
+	return balances, nil
+}
+
+// GetInconsistentProductIDsQuery returns the products whose history does not add up: a row whose final quantity is not
+// the previous row's final quantity plus its own quantity, or a last final quantity that differs from the inventory
+func (repo *InventoryHistoryRepository) GetInconsistentProductIDsQuery(ctx context.Context, productID int, warehouseID int, tx *sqlx.Tx) ([]int, error) {
+	historyCondition, historyArgs := inventoryHistoryFilter("ih", productID, warehouseID)
+	inventoryCondition, inventoryArgs := inventoryHistoryFilter("i", productID, warehouseID)
+	query := `SELECT c.product_id FROM (
+			SELECT ih.product_id, ih.quantity, ih.final_quantity,
+				LAG(ih.final_quantity) OVER (PARTITION BY ih.product_id, ih.warehouse_id ORDER BY ih.id) AS previous_final_quantity
+			FROM inventory_histories ih
+			WHERE 1=1` + historyCondition + `
+		) c
+		WHERE c.previous_final_quantity IS NOT NULL AND c.final_quantity <> c.previous_final_quantity + c.quantity
+		UNION
+		SELECT i.product_id FROM inventory i
+		LEFT JOIN (
+			SELECT product_id, warehouse_id, MAX(id) AS last_id FROM inventory_histories GROUP BY product_id, warehouse_id
+		) l ON l.product_id = i.product_id AND l.warehouse_id = i.warehouse_id
+		LEFT JOIN inventory_histories ih ON ih.id = l.last_id
+		WHERE i.quantity <> COALESCE(ih.final_quantity, 0)` + inventoryCondition + `
+		ORDER BY product_id`
+	args := append(historyArgs, inventoryArgs...)
+
+	var productIDs []int
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &productIDs, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &productIDs, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if productIDs == nil {
+		return []int{}, nil
+	}
+	return productIDs, nil
+}
+
 func (repo *InventoryHistoryRepository) CreateCommand(ctx context.Context, inventoryHistory *entity.InventoryHistory, tx *sqlx.Tx) error {
-	insertQuery := `INSERT INTO inventory_histories(product_id, quantity, final_quantity, importer_name, imported_at, note, reference_id) VALUES (:product_id, :quantity, :final_quantity, :importer_name, :imported_at, :note, :reference_id)`
+	insertQuery := `INSERT INTO inventory_histories(product_id, warehouse_id, quantity, final_quantity, movement_type, importer_name, imported_at, note, reference_id) VALUES (:product_id, :warehouse_id, :quantity, :final_quantity, :movement_type, :importer_name, :imported_at, :note, :reference_id)`
 
 	var result sql.Result
 	var err error
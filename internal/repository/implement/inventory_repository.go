@@ -21,7 +21,7 @@ func NewInventoryRepository(db database.Db) repository.InventoryRepository {
 }
 
 func (repo *InventoryRepository) CreateCommand(ctx context.Context, inventory *entity.Inventory, tx *sqlx.Tx) error {
-	insertQuery := `INSERT INTO inventory(product_id, quantity, version) VALUES (:product_id, :quantity, :version)`
+	insertQuery := `INSERT INTO inventory(warehouse_id, product_id, quantity, version) VALUES (:warehouse_id, :product_id, :quantity, :version)`
 
 	if tx != nil {
 		_, err := tx.NamedExecContext(ctx, insertQuery, inventory)
@@ -31,33 +31,81 @@ func (repo *InventoryRepository) CreateCommand(ctx context.Context, inventory *e
 	return err
 }
 
-func (repo *InventoryRepository) GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Inventory, error) {
+// CreateForAllProductsCommand gives a new warehouse an empty inventory row for every product
+func (repo *InventoryRepository) CreateForAllProductsCommand(ctx context.Context, warehouseID int, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO inventory(warehouse_id, product_id, quantity, version) SELECT ?, id, 0, UUID() FROM products`
+
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, insertQuery, warehouseID)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, insertQuery, warehouseID)
+	return err
+}
+
+// inventorySelect joins the warehouse name, it must not be used with FOR UPDATE so warehouses are not locked
+const inventorySelect = `SELECT i.*, w.name AS warehouse_name FROM inventory i JOIN warehouses w ON w.id = i.warehouse_id`
+
+// GetAllQuery returns the inventory of one warehouse, or of every warehouse when warehouseID is 0
+func (repo *InventoryRepository) GetAllQuery(ctx context.Context, warehouseID int, tx *sqlx.Tx) ([]entity.Inventory, error) {
+	var inventories []entity.Inventory
+	query := inventorySelect
+	var args []interface{}
+	if warehouseID != 0 {
+		query += " WHERE i.warehouse_id = ?"
+		args = append(args, warehouseID)
+	}
+	query += " ORDER BY i.product_id, i.warehouse_id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &inventories, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &inventories, query, args...)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if inventories == nil {
+		return []entity.Inventory{}, nil
+	}
+
+	return inventories, nil
+}
+
+func (repo *InventoryRepository) GetAllByProductIDQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.Inventory, error) {
 	var inventories []entity.Inventory
-	query := "SELECT * FROM inventory ORDER BY id"
+	query := inventorySelect + " WHERE i.product_id = ? ORDER BY i.warehouse_id"
 	var err error
 
 	if tx != nil {
-		err = tx.SelectContext(ctx, &inventories, query)
+		err = tx.SelectContext(ctx, &inventories, query, productID)
 	} else {
-		err = repo.db.SelectContext(ctx, &inventories, query)
+		err = repo.db.SelectContext(ctx, &inventories, query, productID)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	if inventories == nil {
+		return []entity.Inventory{}, nil
+	}
+
 	return inventories, nil
 }
 
-func (repo *InventoryRepository) GetOneByProductIDQuery(ctx context.Context, productID int, tx *sqlx.Tx) (*entity.Inventory, error) {
+func (repo *InventoryRepository) GetOneByWarehouseAndProductIDQuery(ctx context.Context, warehouseID int, productID int, tx *sqlx.Tx) (*entity.Inventory, error) {
 	var inventory entity.Inventory
-	query := "SELECT * FROM inventory WHERE product_id = ?"
+	query := inventorySelect + " WHERE i.warehouse_id = ? AND i.product_id = ?"
 	var err error
 
 	if tx != nil {
-		err = tx.GetContext(ctx, &inventory, query, productID)
+		err = tx.GetContext(ctx, &inventory, query, warehouseID, productID)
 	} else {
-		err = repo.db.GetContext(ctx, &inventory, query, productID)
+		err = repo.db.GetContext(ctx, &inventory, query, warehouseID, productID)
 	}
 
 	if err != nil {
@@ -70,14 +118,14 @@ func (repo *InventoryRepository) GetOneByProductIDQuery(ctx context.Context, pro
 	return &inventory, nil
 }
 
-func (repo *InventoryRepository) UpdateQuantityCommand(ctx context.Context, productID int, quantity int, version string, tx *sqlx.Tx) error {
-	updateQuery := `UPDATE inventory SET quantity = quantity + ?, version = ? WHERE product_id = ?`
+func (repo *InventoryRepository) UpdateQuantityCommand(ctx context.Context, id int, quantity int, version string, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE inventory SET quantity = quantity + ?, version = ? WHERE id = ?`
 
 	var err error
 	if tx != nil {
-		_, err = tx.ExecContext(ctx, updateQuery, quantity, version, productID)
+		_, err = tx.ExecContext(ctx, updateQuery, quantity, version, id)
 	} else {
-		_, err = repo.db.ExecContext(ctx, updateQuery, quantity, version, productID)
+		_, err = repo.db.ExecContext(ctx, updateQuery, quantity, version, id)
 	}
 
 	if err != nil {
@@ -85,21 +133,24 @@ func (repo *InventoryRepository) UpdateQuantityCommand(ctx context.Context, prod
 		if strings.Contains(err.Error(), "check_quantity_non_negative") {
 			return &error_utils.ConstraintViolationError{Message: "Số lượng kho không thể âm"}
 		}
+		if strings.Contains(err.Error(), "check_quantity_covers_reserved") {
+			return &error_utils.ReservedQuantityViolationError{Message: "Số lượng kho không thể ít hơn số lượng đang giữ cho đơn hàng"}
+		}
 		return err
 	}
 
 	return nil
 }
 
-func (repo *InventoryRepository) GetOneByIDForUpdateQuery(ctx context.Context, productID int, tx *sqlx.Tx) (*entity.Inventory, error) {
+func (repo *InventoryRepository) GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Inventory, error) {
 	var inventory entity.Inventory
 	query := "SELECT * FROM inventory WHERE id = ? FOR UPDATE"
 	var err error
 
 	if tx != nil {
-		err = tx.GetContext(ctx, &inventory, query, productID)
+		err = tx.GetContext(ctx, &inventory, query, id)
 	} else {
-		err = repo.db.GetContext(ctx, &inventory, query, productID)
+		err = repo.db.GetContext(ctx, &inventory, query, id)
 	}
 
 	if err != nil {
@@ -112,15 +163,15 @@ func (repo *InventoryRepository) GetOneByIDForUpdateQuery(ctx context.Context, p
 	return &inventory, nil
 }
 
-func (repo *InventoryRepository) UpdateQuantityWithVersionCommand(ctx context.Context, productID int, quantity int, expectedVersion string, newVersion string, tx *sqlx.Tx) error {
-	updateQuery := `UPDATE inventory SET quantity = quantity + ?, version = ? WHERE product_id = ? AND version = ?`
+func (repo *InventoryRepository) UpdateQuantityWithVersionCommand(ctx context.Context, id int, quantity int, expectedVersion string, newVersion string, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE inventory SET quantity = quantity + ?, version = ? WHERE id = ? AND version = ?`
 
 	var result sql.Result
 	var err error
 	if tx != nil {
-		result, err = tx.ExecContext(ctx, updateQuery, quantity, newVersion, productID, expectedVersion)
+		result, err = tx.ExecContext(ctx, updateQuery, quantity, newVersion, id, expectedVersion)
 	} else {
-		result, err = repo.db.ExecContext(ctx, updateQuery, quantity, newVersion, productID, expectedVersion)
+		result, err = repo.db.ExecContext(ctx, updateQuery, quantity, newVersion, id, expectedVersion)
 	}
 
 	if err != nil {
@@ -128,6 +179,45 @@ func (repo *InventoryRepository) UpdateQuantityWithVersionCommand(ctx context.Co
 		if strings.Contains(err.Error(), "check_quantity_non_negative") {
 			return &error_utils.ConstraintViolationError{Message: "Quantity cannot be negative"}
 		}
+		if strings.Contains(err.Error(), "check_quantity_covers_reserved") {
+			return &error_utils.ReservedQuantityViolationError{Message: "Quantity cannot drop below the reserved quantity"}
+		}
+		return err
+	}
+
+	// Check if any rows were affected (version mismatch)
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return &error_utils.VersionMismatchError{Message: "Version mismatch. Try again"}
+	}
+
+	return nil
+}
+
+// UpdateReservedQuantityWithVersionCommand moves the quantity reserved for pending orders, a negative value releases it
+func (repo *InventoryRepository) UpdateReservedQuantityWithVersionCommand(ctx context.Context, id int, reservedQuantity int, expectedVersion string, newVersion string, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE inventory SET reserved_quantity = reserved_quantity + ?, version = ? WHERE id = ? AND version = ?`
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, updateQuery, reservedQuantity, newVersion, id, expectedVersion)
+	} else {
+		result, err = repo.db.ExecContext(ctx, updateQuery, reservedQuantity, newVersion, id, expectedVersion)
+	}
+
+	if err != nil {
+		// Check if it's a constraint violation error
+		if strings.Contains(err.Error(), "check_reserved_quantity_non_negative") {
+			return &error_utils.ConstraintViolationError{Message: "Reserved quantity cannot be negative"}
+		}
+		if strings.Contains(err.Error(), "check_quantity_covers_reserved") {
+			return &error_utils.ReservedQuantityViolationError{Message: "Reserved quantity cannot exceed the quantity on hand"}
+		}
 		return err
 	}
 
@@ -169,7 +259,7 @@ func (repo *InventoryRepository) GetInventoryIDsByProductIDsQuery(ctx context.Co
 	if len(productIDs) == 0 {
 		return []int{}, nil
 	}
-	query, args, err := sqlx.In("SELECT id FROM inventory WHERE product_id IN (?)", productIDs)
+	query, args, err := sqlx.In("SELECT id FROM inventory WHERE product_id IN (?) ORDER BY id", productIDs)
 	if err != nil {
 		return nil, err
 	}
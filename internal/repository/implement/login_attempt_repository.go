@@ -0,0 +1,120 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+)
+
+type LoginAttemptRepository struct {
+	db *sqlx.DB
+}
+
+func NewLoginAttemptRepository(db database.Db) repository.LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func (repo *LoginAttemptRepository) GetAllWithFiltersQuery(ctx context.Context, filter repository.LoginAttemptFilter, tx *sqlx.Tx) ([]entity.LoginAttempt, error) {
+	query := "SELECT * FROM login_attempts WHERE 1=1"
+	var args []interface{}
+
+	if filter.Username != "" {
+		query += " AND username = ?"
+		args = append(args, filter.Username)
+	}
+	if filter.IPAddress != "" {
+		query += " AND ip_address = ?"
+		args = append(args, filter.IPAddress)
+	}
+	if filter.Success != nil {
+		query += " AND success = ?"
+		args = append(args, *filter.Success)
+	}
+	if filter.FromDate != nil {
+		startOfDay := time.Date(filter.FromDate.Year(), filter.FromDate.Month(), filter.FromDate.Day(), 0, 0, 0, 0, filter.FromDate.Location())
+		query += " AND attempted_at >= ?"
+		args = append(args, startOfDay)
+	}
+	if filter.ToDate != nil {
+		endOfDay := time.Date(filter.ToDate.Year(), filter.ToDate.Month(), filter.ToDate.Day(), 23, 59, 59, 999999999, filter.ToDate.Location())
+		query += " AND attempted_at <= ?"
+		args = append(args, endOfDay)
+	}
+
+	query += " ORDER BY attempted_at DESC, id DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	loginAttempts := make([]entity.LoginAttempt, 0)
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &loginAttempts, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &loginAttempts, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return loginAttempts, nil
+}
+
+func (repo *LoginAttemptRepository) GetFailureStatsByUsernameQuery(ctx context.Context, username string, since time.Time, tx *sqlx.Tx) (*entity.LoginFailureStats, error) {
+	return repo.getFailureStats(ctx, "username", username, since, true, tx)
+}
+
+// GetFailureStatsByIPAddressQuery is not reset by successful logins: an attacker could otherwise log into their own
+// account now and then to keep guessing other users' passwords from the same address
+func (repo *LoginAttemptRepository) GetFailureStatsByIPAddressQuery(ctx context.Context, ipAddress string, since time.Time, tx *sqlx.Tx) (*entity.LoginFailureStats, error) {
+	return repo.getFailureStats(ctx, "ip_address", ipAddress, since, false, tx)
+}
+
+// getFailureStats counts failures on the given column since the given time, or since the last successful login when
+// resetOnSuccess is set; column is always one of the constants above, never user input
+func (repo *LoginAttemptRepository) getFailureStats(ctx context.Context, column string, value string, since time.Time, resetOnSuccess bool, tx *sqlx.Tx) (*entity.LoginFailureStats, error) {
+	query := `SELECT COUNT(*) AS failure_count, MAX(attempted_at) AS last_failure_at FROM login_attempts
+		WHERE ` + column + ` = ? AND success = FALSE AND failure_reason <> 'LOCKED' AND attempted_at > ?`
+	args := []interface{}{value, since}
+	if resetOnSuccess {
+		query += ` AND attempted_at > COALESCE((SELECT MAX(attempted_at) FROM login_attempts WHERE ` + column + ` = ? AND success = TRUE), ?)`
+		args = append(args, value, since)
+	}
+
+	var stats entity.LoginFailureStats
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &stats, query, args...)
+	} else {
+		err = repo.db.GetContext(ctx, &stats, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func (repo *LoginAttemptRepository) CreateCommand(ctx context.Context, loginAttempt *entity.LoginAttempt, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO login_attempts(username, ip_address, user_agent, user_id, success, failure_reason, attempted_at)
+		VALUES (:username, :ip_address, :user_agent, :user_id, :success, :failure_reason, :attempted_at)`
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, loginAttempt)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, loginAttempt)
+	}
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	loginAttempt.ID = int(lastID)
+	return nil
+}
@@ -3,6 +3,7 @@ package repositoryimplement
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pna/order-app-backend/internal/database"
@@ -36,8 +37,91 @@ func (repo *OrderItemRepository) GetAllByOrderIDQuery(ctx context.Context, order
 	return orderItems, nil
 }
 
+func (repo *OrderItemRepository) GetAllByOrderIDsQuery(ctx context.Context, orderIDs []int, tx *sqlx.Tx) ([]entity.OrderItem, error) {
+	if len(orderIDs) == 0 {
+		return []entity.OrderItem{}, nil
+	}
+	query, args, err := sqlx.In("SELECT * FROM order_items WHERE order_id IN (?) ORDER BY order_id, id", orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	query = repo.db.Rebind(query)
+
+	var orderItems []entity.OrderItem
+	if tx != nil {
+		err = tx.SelectContext(ctx, &orderItems, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &orderItems, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if orderItems == nil {
+		return []entity.OrderItem{}, nil
+	}
+	return orderItems, nil
+}
+
+// GetSoldQuantitiesSinceQuery returns the quantity sold of each product by orders placed since the given time, keyed by product ID.
+// Cancelled orders are left out, items are counted whichever source they were exported from.
+func (repo *OrderItemRepository) GetSoldQuantitiesSinceQuery(ctx context.Context, since time.Time, tx *sqlx.Tx) (map[int]int, error) {
+	query := `SELECT oi.product_id, COALESCE(SUM(oi.quantity), 0) AS sold_quantity FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		WHERE o.order_date >= ? AND o.delivery_status <> ? GROUP BY oi.product_id`
+
+	var rows []struct {
+		ProductID    int `db:"product_id"`
+		SoldQuantity int `db:"sold_quantity"`
+	}
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &rows, query, since, entity.OrderDeliveryStatus.CANCELLED)
+	} else {
+		err = repo.db.SelectContext(ctx, &rows, query, since, entity.OrderDeliveryStatus.CANCELLED)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	soldQuantities := make(map[int]int)
+	for _, row := range rows {
+		soldQuantities[row.ProductID] = row.SoldQuantity
+	}
+	return soldQuantities, nil
+}
+
+// GetTopProductsByCustomerIDQuery returns the products a customer bought the most of, ignoring cancelled orders
+func (repo *OrderItemRepository) GetTopProductsByCustomerIDQuery(ctx context.Context, customerID int, limit int, tx *sqlx.Tx) ([]entity.CustomerProductPurchase, error) {
+	query := `SELECT oi.product_id, p.name AS product_name,
+			COALESCE(SUM(oi.quantity), 0) AS quantity,
+			COUNT(DISTINCT oi.order_id) AS order_count,
+			COALESCE(SUM(COALESCE(oi.final_amount, oi.quantity * oi.selling_price - (oi.quantity * oi.selling_price * oi.discount) DIV 100)), 0) AS amount
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		JOIN products p ON p.id = oi.product_id
+		WHERE o.customer_id = ? AND o.delivery_status <> ?
+		GROUP BY oi.product_id, p.name
+		ORDER BY quantity DESC, oi.product_id
+		LIMIT ?`
+
+	var purchases []entity.CustomerProductPurchase
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &purchases, query, customerID, entity.OrderDeliveryStatus.CANCELLED, limit)
+	} else {
+		err = repo.db.SelectContext(ctx, &purchases, query, customerID, entity.OrderDeliveryStatus.CANCELLED, limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if purchases == nil {
+		return []entity.CustomerProductPurchase{}, nil
+	}
+	return purchases, nil
+}
+
 func (repo *OrderItemRepository) CreateCommand(ctx context.Context, orderItem *entity.OrderItem, tx *sqlx.Tx) error {
-	insertQuery := `INSERT INTO order_items(order_id, product_id, number_of_boxes, spec, quantity, selling_price, original_price, discount, final_amount, export_from) VALUES (:order_id, :product_id, :number_of_boxes, :spec, :quantity, :selling_price, :original_price, :discount, :final_amount, :export_from)`
+	insertQuery := `INSERT INTO order_items(order_id, product_id, number_of_boxes, spec, quantity, selling_price, original_price, discount, final_amount, price_source, export_from, warehouse_id) VALUES (:order_id, :product_id, :number_of_boxes, :spec, :quantity, :selling_price, :original_price, :discount, :final_amount, :price_source, :export_from, :warehouse_id)`
 	var result sql.Result
 	var err error
 	if tx != nil {
@@ -56,6 +140,16 @@ func (repo *OrderItemRepository) CreateCommand(ctx context.Context, orderItem *e
 	return nil
 }
 
+func (repo *OrderItemRepository) UpdateCommand(ctx context.Context, orderItem *entity.OrderItem, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE order_items SET number_of_boxes = :number_of_boxes, spec = :spec, quantity = :quantity, selling_price = :selling_price, original_price = :original_price, discount = :discount, final_amount = :final_amount, price_source = :price_source, export_from = :export_from, warehouse_id = :warehouse_id WHERE id = :id`
+	if tx != nil {
+		_, err := tx.NamedExecContext(ctx, updateQuery, orderItem)
+		return err
+	}
+	_, err := repo.db.NamedExecContext(ctx, updateQuery, orderItem)
+	return err
+}
+
 func (repo *OrderItemRepository) DeleteByIDCommand(ctx context.Context, id int, tx *sqlx.Tx) error {
 	deleteQuery := `DELETE FROM order_items WHERE id = ?`
 	if tx != nil {
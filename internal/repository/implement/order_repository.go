@@ -11,6 +11,7 @@ import (
 	"github.com/pna/order-app-backend/internal/domain/entity"
 	"github.com/pna/order-app-backend/internal/repository"
 	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	stringutils "github.com/pna/order-app-backend/internal/utils/string_utils"
 )
 
 type OrderRepository struct {
@@ -39,55 +40,135 @@ func (repo *OrderRepository) GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]en
 	return orders, nil
 }
 
-func (repo *OrderRepository) GetAllWithFiltersQuery(ctx context.Context, customerID int, deliveryStatuses string, sortBy string, fromDate *time.Time, toDate *time.Time, tx *sqlx.Tx) ([]entity.Order, error) {
-	var orders []entity.Order
-	query := "SELECT * FROM orders WHERE 1=1"
+// orderNetAmountExpr is the amount of the items minus returned goods plus additional cost, before tax
+const orderNetAmountExpr = `(COALESCE(oi.items_amount, 0) - o.total_returned_revenue + o.additional_cost)`
+
+// orderTotalAmountExpr mirrors calculateOrderTotalAmount: the net amount plus tax truncated to VND
+const orderTotalAmountExpr = `(` + orderNetAmountExpr + ` + TRUNCATE(` + orderNetAmountExpr + ` * o.tax_percent / 100, 0))`
+
+// orderItemsSummaryJoin adds, as oi, the amount of the items of each order after discount.
+// It is a lateral lookup by order_id so only the orders left after filtering are aggregated, not the whole order_items table.
+const orderItemsSummaryJoin = `
+		JOIN LATERAL (
+			SELECT SUM(COALESCE(final_amount, quantity * selling_price - (quantity * selling_price * discount) DIV 100)) AS items_amount,
+				COUNT(DISTINCT product_id) AS product_count
+			FROM order_items WHERE order_items.order_id = o.id
+		) oi ON TRUE`
+
+// orderPaymentsSummaryJoin adds, as p, what has been paid for each order, looked up the same way
+const orderPaymentsSummaryJoin = `
+		JOIN LATERAL (
+			SELECT SUM(amount) AS paid_amount FROM payments WHERE payments.order_id = o.id
+		) p ON TRUE`
+
+// orderWithItemsFrom joins each order (o) with its customer (c) and its items amount.
+// Conditions go straight on o and c so MySQL filters orders before running the lateral lookups.
+const orderWithItemsFrom = `
+		FROM orders o
+		JOIN customers c ON c.id = o.customer_id` + orderItemsSummaryJoin
+
+// orderWithSummaryFrom adds the payments of each order to orderWithItemsFrom
+const orderWithSummaryFrom = orderWithItemsFrom + orderPaymentsSummaryJoin
+
+// orderWithSummarySelect lists each order with its customer, the amount of its items after discount and what has been paid
+const orderWithSummarySelect = `SELECT o.*,
+		c.name AS customer_name, c.phone AS customer_phone, c.address AS customer_address,
+		COALESCE(oi.items_amount, 0) AS items_amount,
+		oi.product_count,
+		COALESCE(p.paid_amount, 0) AS paid_amount,
+		` + orderNetAmountExpr + ` AS net_amount,
+		` + orderTotalAmountExpr + ` AS total_amount` + orderWithSummaryFrom
+
+// buildOrderFilterConditions turns the filter (without the pagination cursor) into a WHERE clause over orderWithItemsFrom
+func buildOrderFilterConditions(filter repository.OrderFilter) (string, []interface{}) {
+	conditions := " WHERE 1=1"
 	var args []interface{}
 
 	// Add customer filter
-	if customerID > 0 {
-		query += " AND customer_id = ?"
-		args = append(args, customerID)
-	}
-
-	// Add delivery statuses filter using IN query
-	if deliveryStatuses != "" {
-		// Split the comma-separated statuses
-		statusList := strings.Split(deliveryStatuses, ",")
-		if len(statusList) > 0 {
-			// Create placeholders for IN query
-			placeholders := make([]string, len(statusList))
-			for i := range statusList {
-				placeholders[i] = "?"
-				args = append(args, strings.TrimSpace(statusList[i]))
-			}
-			query += " AND delivery_status IN (" + strings.Join(placeholders, ",") + ")"
+	if filter.CustomerID > 0 {
+		conditions += " AND o.customer_id = ?"
+		args = append(args, filter.CustomerID)
+	}
+
+	// Add delivery and debt statuses filter using IN query
+	if len(filter.DeliveryStatuses) > 0 {
+		conditions += " AND o.delivery_status IN (?" + strings.Repeat(",?", len(filter.DeliveryStatuses)-1) + ")"
+		for _, status := range filter.DeliveryStatuses {
+			args = append(args, status)
+		}
+	}
+	if len(filter.DebtStatuses) > 0 {
+		conditions += " AND o.debt_status IN (?" + strings.Repeat(",?", len(filter.DebtStatuses)-1) + ")"
+		for _, status := range filter.DebtStatuses {
+			args = append(args, status)
 		}
 	}
 
+	// Add free-text search over customer name and phone
+	if filter.Search != "" {
+		pattern := stringutils.ContainsPattern(filter.Search)
+		conditions += " AND (c.name LIKE ? OR c.phone LIKE ?)"
+		args = append(args, pattern, pattern)
+	}
+
+	// Add amount range filter
+	if filter.MinAmount != nil {
+		conditions += " AND " + orderTotalAmountExpr + " >= ?"
+		args = append(args, *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		conditions += " AND " + orderTotalAmountExpr + " <= ?"
+		args = append(args, *filter.MaxAmount)
+	}
+
 	// Add date range filter
-	if fromDate != nil {
+	if filter.FromDate != nil {
 		// Set fromDate to start of day (00:00:00)
-		startOfDay := time.Date(fromDate.Year(), fromDate.Month(), fromDate.Day(), 0, 0, 0, 0, fromDate.Location())
-		query += " AND order_date >= ?"
+		startOfDay := time.Date(filter.FromDate.Year(), filter.FromDate.Month(), filter.FromDate.Day(), 0, 0, 0, 0, filter.FromDate.Location())
+		conditions += " AND o.order_date >= ?"
 		args = append(args, startOfDay)
 	}
 
-	if toDate != nil {
+	if filter.ToDate != nil {
 		// Set toDate to end of day (23:59:59.999999999)
-		endOfDay := time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 23, 59, 59, 999999999, toDate.Location())
-		query += " AND order_date <= ?"
+		endOfDay := time.Date(filter.ToDate.Year(), filter.ToDate.Month(), filter.ToDate.Day(), 23, 59, 59, 999999999, filter.ToDate.Location())
+		conditions += " AND o.order_date <= ?"
 		args = append(args, endOfDay)
 	}
 
-	// Add sorting
-	switch sortBy {
+	return conditions, args
+}
+
+func (repo *OrderRepository) GetAllWithFiltersQuery(ctx context.Context, filter repository.OrderFilter, tx *sqlx.Tx) ([]entity.OrderWithSummary, error) {
+	var orders []entity.OrderWithSummary
+	conditions, args := buildOrderFilterConditions(filter)
+	query := orderWithSummarySelect + conditions
+
+	// Add keyset cursor and sorting, id breaks ties between orders of the same date
+	switch filter.SortBy {
 	case "order_date_asc":
-		query += " ORDER BY order_date ASC"
+		if filter.CursorOrderDate != nil {
+			query += " AND (o.order_date > ? OR (o.order_date = ? AND o.id > ?))"
+			args = append(args, *filter.CursorOrderDate, *filter.CursorOrderDate, filter.CursorID)
+		}
+		query += " ORDER BY o.order_date ASC, o.id ASC"
 	case "order_date_desc":
-		query += " ORDER BY order_date DESC"
+		if filter.CursorOrderDate != nil {
+			query += " AND (o.order_date < ? OR (o.order_date = ? AND o.id < ?))"
+			args = append(args, *filter.CursorOrderDate, *filter.CursorOrderDate, filter.CursorID)
+		}
+		query += " ORDER BY o.order_date DESC, o.id DESC"
 	default:
-		query += " ORDER BY id DESC"
+		if filter.CursorID > 0 {
+			query += " AND o.id < ?"
+			args = append(args, filter.CursorID)
+		}
+		query += " ORDER BY o.id DESC"
+	}
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
 	}
 
 	var err error
@@ -100,11 +181,35 @@ func (repo *OrderRepository) GetAllWithFiltersQuery(ctx context.Context, custome
 		return nil, err
 	}
 	if orders == nil {
-		return []entity.Order{}, nil
+		return []entity.OrderWithSummary{}, nil
 	}
 	return orders, nil
 }
 
+// GetSummaryWithFiltersQuery aggregates every order matching the filter, ignoring the pagination cursor and limit.
+// Cancelled orders are counted but do not add to the amount and profit totals.
+func (repo *OrderRepository) GetSummaryWithFiltersQuery(ctx context.Context, filter repository.OrderFilter, tx *sqlx.Tx) (*entity.OrderListSummary, error) {
+	var summary entity.OrderListSummary
+	conditions, args := buildOrderFilterConditions(filter)
+	query := `SELECT COUNT(*) AS total_count,
+		COALESCE(SUM(CASE WHEN o.delivery_status <> ? THEN ` + orderTotalAmountExpr + ` ELSE 0 END), 0) AS total_amount,
+		COALESCE(SUM(CASE WHEN o.delivery_status <> ?
+			THEN o.total_sales_revenue - o.total_returned_revenue - (o.total_original_cost - o.total_returned_cost) + o.additional_cost
+			ELSE 0 END), 0) AS total_profit_loss` + orderWithItemsFrom + conditions
+	args = append([]interface{}{entity.OrderDeliveryStatus.CANCELLED, entity.OrderDeliveryStatus.CANCELLED}, args...)
+
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &summary, query, args...)
+	} else {
+		err = repo.db.GetContext(ctx, &summary, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
 func (repo *OrderRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Order, error) {
 	var order entity.Order
 	query := "SELECT * FROM orders WHERE id = ?"
@@ -123,6 +228,24 @@ func (repo *OrderRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sq
 	return &order, nil
 }
 
+func (repo *OrderRepository) GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Order, error) {
+	var order entity.Order
+	query := "SELECT * FROM orders WHERE id = ? FOR UPDATE"
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &order, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &order, query, id)
+	}
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
 func (repo *OrderRepository) CreateCommand(ctx context.Context, order *entity.Order, tx *sqlx.Tx) error {
 	insertQuery := `INSERT INTO orders(customer_id, order_date, delivery_status, debt_status, status_transitioned_at, total_original_cost, total_sales_revenue, additional_cost, additonal_cost_note, tax_percent) VALUES (:customer_id, :order_date, :delivery_status, :debt_status, :status_transitioned_at, :total_original_cost, :total_sales_revenue, :additional_cost, :additonal_cost_note, :tax_percent)`
 	var result sql.Result
@@ -144,7 +267,7 @@ func (repo *OrderRepository) CreateCommand(ctx context.Context, order *entity.Or
 }
 
 func (repo *OrderRepository) UpdateCommand(ctx context.Context, order *entity.Order, tx *sqlx.Tx) error {
-	updateQuery := `UPDATE orders SET customer_id = :customer_id, order_date = :order_date, delivery_status = :delivery_status, debt_status = :debt_status, status_transitioned_at = :status_transitioned_at, total_original_cost = :total_original_cost, total_sales_revenue = :total_sales_revenue, additional_cost = :additional_cost, additonal_cost_note = :additonal_cost_note, tax_percent = :tax_percent WHERE id = :id`
+	updateQuery := `UPDATE orders SET customer_id = :customer_id, order_date = :order_date, delivery_status = :delivery_status, debt_status = :debt_status, status_transitioned_at = :status_transitioned_at, total_original_cost = :total_original_cost, total_sales_revenue = :total_sales_revenue, additional_cost = :additional_cost, additonal_cost_note = :additonal_cost_note, tax_percent = :tax_percent, cancelled_at = :cancelled_at, cancellation_reason = :cancellation_reason, total_returned_revenue = :total_returned_revenue, total_returned_cost = :total_returned_cost WHERE id = :id`
 	if tx != nil {
 		_, err := tx.NamedExecContext(ctx, updateQuery, order)
 		return err
@@ -153,6 +276,16 @@ func (repo *OrderRepository) UpdateCommand(ctx context.Context, order *entity.Or
 	return err
 }
 
+func (repo *OrderRepository) UpdateDebtStatusCommand(ctx context.Context, id int, debtStatus string, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE orders SET debt_status = ? WHERE id = ?`
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, updateQuery, debtStatus, id)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, updateQuery, debtStatus, id)
+	return err
+}
+
 func (repo *OrderRepository) DeleteByIDCommand(ctx context.Context, id int, tx *sqlx.Tx) error {
 	deleteQuery := `DELETE FROM orders WHERE id = ?`
 	if tx != nil {
@@ -162,3 +295,25 @@ func (repo *OrderRepository) DeleteByIDCommand(ctx context.Context, id int, tx *
 	_, err := repo.db.ExecContext(ctx, deleteQuery, id)
 	return err
 }
+
+// ReassignCustomerCommand moves every order of a customer to another one and returns how many were moved
+func (repo *OrderRepository) ReassignCustomerCommand(ctx context.Context, fromCustomerID int, toCustomerID int, tx *sqlx.Tx) (int, error) {
+	updateQuery := `UPDATE orders SET customer_id = ? WHERE customer_id = ?`
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, updateQuery, toCustomerID, fromCustomerID)
+	} else {
+		result, err = repo.db.ExecContext(ctx, updateQuery, toCustomerID, fromCustomerID)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rowsAffected), nil
+}
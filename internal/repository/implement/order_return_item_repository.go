@@ -0,0 +1,91 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+)
+
+type OrderReturnItemRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrderReturnItemRepository(db database.Db) repository.OrderReturnItemRepository {
+	return &OrderReturnItemRepository{db: db}
+}
+
+func (repo *OrderReturnItemRepository) GetAllByOrderReturnIDsQuery(ctx context.Context, orderReturnIDs []int, tx *sqlx.Tx) ([]entity.OrderReturnItem, error) {
+	if len(orderReturnIDs) == 0 {
+		return []entity.OrderReturnItem{}, nil
+	}
+	query, args, err := sqlx.In("SELECT * FROM order_return_items WHERE order_return_id IN (?) ORDER BY id", orderReturnIDs)
+	if err != nil {
+		return nil, err
+	}
+	query = repo.db.Rebind(query)
+
+	var orderReturnItems []entity.OrderReturnItem
+	if tx != nil {
+		err = tx.SelectContext(ctx, &orderReturnItems, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &orderReturnItems, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if orderReturnItems == nil {
+		return []entity.OrderReturnItem{}, nil
+	}
+	return orderReturnItems, nil
+}
+
+// GetReturnedQuantitiesByOrderIDQuery returns the total returned quantity of each order item of an order, keyed by order item ID
+func (repo *OrderReturnItemRepository) GetReturnedQuantitiesByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) (map[int]int, error) {
+	query := `SELECT ri.order_item_id, COALESCE(SUM(ri.quantity), 0) AS returned_quantity FROM order_return_items ri
+		JOIN order_returns r ON r.id = ri.order_return_id
+		WHERE r.order_id = ? GROUP BY ri.order_item_id`
+
+	var rows []struct {
+		OrderItemID      int `db:"order_item_id"`
+		ReturnedQuantity int `db:"returned_quantity"`
+	}
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &rows, query, orderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &rows, query, orderID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	returnedQuantities := make(map[int]int)
+	for _, row := range rows {
+		returnedQuantities[row.OrderItemID] = row.ReturnedQuantity
+	}
+	return returnedQuantities, nil
+}
+
+func (repo *OrderReturnItemRepository) CreateCommand(ctx context.Context, orderReturnItem *entity.OrderReturnItem, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO order_return_items(order_return_id, order_item_id, product_id, quantity, refund_amount, original_cost, export_from) VALUES (:order_return_id, :order_item_id, :product_id, :quantity, :refund_amount, :original_cost, :export_from)`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, orderReturnItem)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, orderReturnItem)
+	}
+	if err != nil {
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	orderReturnItem.ID = int(lastID)
+	return nil
+}
@@ -0,0 +1,59 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+)
+
+type OrderReturnRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrderReturnRepository(db database.Db) repository.OrderReturnRepository {
+	return &OrderReturnRepository{db: db}
+}
+
+func (repo *OrderReturnRepository) GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.OrderReturn, error) {
+	var orderReturns []entity.OrderReturn
+	query := `SELECT r.*, u.username AS created_by_name FROM order_returns r
+		JOIN users u ON u.id = r.created_by
+		WHERE r.order_id = ? ORDER BY r.returned_at, r.id`
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &orderReturns, query, orderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &orderReturns, query, orderID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if orderReturns == nil {
+		return []entity.OrderReturn{}, nil
+	}
+	return orderReturns, nil
+}
+
+func (repo *OrderReturnRepository) CreateCommand(ctx context.Context, orderReturn *entity.OrderReturn, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO order_returns(order_id, returned_at, reason, total_refund_amount, total_original_cost, created_by) VALUES (:order_id, :returned_at, :reason, :total_refund_amount, :total_original_cost, :created_by)`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, orderReturn)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, orderReturn)
+	}
+	if err != nil {
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	orderReturn.ID = int(lastID)
+	return nil
+}
@@ -0,0 +1,59 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+)
+
+type OrderStatusHistoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrderStatusHistoryRepository(db database.Db) repository.OrderStatusHistoryRepository {
+	return &OrderStatusHistoryRepository{db: db}
+}
+
+func (repo *OrderStatusHistoryRepository) GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.OrderStatusHistory, error) {
+	var histories []entity.OrderStatusHistory
+	query := `SELECT h.*, u.username AS changed_by_name FROM order_status_histories h
+		JOIN users u ON u.id = h.changed_by
+		WHERE h.order_id = ? ORDER BY h.changed_at, h.id`
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &histories, query, orderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &histories, query, orderID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if histories == nil {
+		return []entity.OrderStatusHistory{}, nil
+	}
+	return histories, nil
+}
+
+func (repo *OrderStatusHistoryRepository) CreateCommand(ctx context.Context, orderStatusHistory *entity.OrderStatusHistory, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO order_status_histories(order_id, from_status, to_status, changed_by, changed_at, note) VALUES (:order_id, :from_status, :to_status, :changed_by, :changed_at, :note)`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, orderStatusHistory)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, orderStatusHistory)
+	}
+	if err != nil {
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	orderStatusHistory.ID = int(lastID)
+	return nil
+}
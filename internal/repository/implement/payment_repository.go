@@ -0,0 +1,119 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+)
+
+type PaymentRepository struct {
+	db *sqlx.DB
+}
+
+func NewPaymentRepository(db database.Db) repository.PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+func (repo *PaymentRepository) GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	query := `SELECT p.*, u.username AS recorded_by_name FROM payments p
+		JOIN users u ON u.id = p.recorded_by
+		WHERE p.order_id = ? ORDER BY p.paid_at, p.id`
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &payments, query, orderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &payments, query, orderID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if payments == nil {
+		return []entity.Payment{}, nil
+	}
+	return payments, nil
+}
+
+func (repo *PaymentRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Payment, error) {
+	var payment entity.Payment
+	query := `SELECT p.*, u.username AS recorded_by_name FROM payments p
+		JOIN users u ON u.id = p.recorded_by
+		WHERE p.id = ?`
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &payment, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &payment, query, id)
+	}
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (repo *PaymentRepository) GetTotalPaidByOrderIDsQuery(ctx context.Context, orderIDs []int, tx *sqlx.Tx) (map[int]int, error) {
+	totalPaid := make(map[int]int)
+	if len(orderIDs) == 0 {
+		return totalPaid, nil
+	}
+	query, args, err := sqlx.In("SELECT order_id, COALESCE(SUM(amount), 0) AS total_paid FROM payments WHERE order_id IN (?) GROUP BY order_id", orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	query = repo.db.Rebind(query)
+
+	var rows []struct {
+		OrderID   int `db:"order_id"`
+		TotalPaid int `db:"total_paid"`
+	}
+	if tx != nil {
+		err = tx.SelectContext(ctx, &rows, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &rows, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		totalPaid[row.OrderID] = row.TotalPaid
+	}
+	return totalPaid, nil
+}
+
+func (repo *PaymentRepository) CreateCommand(ctx context.Context, payment *entity.Payment, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO payments(order_id, amount, method, paid_at, note, recorded_by) VALUES (:order_id, :amount, :method, :paid_at, :note, :recorded_by)`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, payment)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, payment)
+	}
+	if err != nil {
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	payment.ID = int(lastID)
+	return nil
+}
+
+func (repo *PaymentRepository) DeleteByIDCommand(ctx context.Context, id int, tx *sqlx.Tx) error {
+	deleteQuery := `DELETE FROM payments WHERE id = ?`
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, deleteQuery, id)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, deleteQuery, id)
+	return err
+}
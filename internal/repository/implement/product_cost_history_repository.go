@@ -0,0 +1,73 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+)
+
+type ProductCostHistoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewProductCostHistoryRepository(db database.Db) repository.ProductCostHistoryRepository {
+	return &ProductCostHistoryRepository{db: db}
+}
+
+func (repo *ProductCostHistoryRepository) GetAllByProductIDQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.ProductCostHistory, error) {
+	var productCostHistories []entity.ProductCostHistory
+	query := `
+		SELECT pch.*, u.username AS created_by_name
+		FROM product_cost_histories pch
+		LEFT JOIN users u ON u.id = pch.created_by
+		WHERE pch.product_id = ?
+		ORDER BY pch.created_at DESC, pch.id DESC`
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &productCostHistories, query, productID)
+	} else {
+		err = repo.db.SelectContext(ctx, &productCostHistories, query, productID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if productCostHistories == nil {
+		return []entity.ProductCostHistory{}, nil
+	}
+
+	return productCostHistories, nil
+}
+
+func (repo *ProductCostHistoryRepository) CreateCommand(ctx context.Context, productCostHistory *entity.ProductCostHistory, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO product_cost_histories(product_id, source, reference_id, quantity_before, received_quantity, unit_cost, previous_average_cost, average_cost, created_by, created_at) VALUES (:product_id, :source, :reference_id, :quantity_before, :received_quantity, :unit_cost, :previous_average_cost, :average_cost, :created_by, :created_at)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, productCostHistory)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, productCostHistory)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Get the last inserted ID
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	// Set the ID to the product cost history entity
+	productCostHistory.ID = int(lastID)
+	return nil
+}
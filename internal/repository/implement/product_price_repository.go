@@ -0,0 +1,143 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+)
+
+type ProductPriceRepository struct {
+	db *sqlx.DB
+}
+
+func NewProductPriceRepository(db database.Db) repository.ProductPriceRepository {
+	return &ProductPriceRepository{db: db}
+}
+
+// productPriceSelect joins the product, customer and creator names
+const productPriceSelect = `SELECT pp.*, p.name AS product_name, c.name AS customer_name, u.username AS created_by_name
+	FROM product_prices pp
+	JOIN products p ON p.id = pp.product_id
+	LEFT JOIN customers c ON c.id = pp.customer_id
+	JOIN users u ON u.id = pp.created_by`
+
+// GetAllWithFiltersQuery lists the prices of one product and one customer's overrides, 0 meaning all,
+// by product then with the default prices first and the latest effective date first
+func (repo *ProductPriceRepository) GetAllWithFiltersQuery(ctx context.Context, productID int, customerID int, tx *sqlx.Tx) ([]entity.ProductPrice, error) {
+	query := productPriceSelect + " WHERE 1=1"
+	var args []interface{}
+	if productID != 0 {
+		query += " AND pp.product_id = ?"
+		args = append(args, productID)
+	}
+	if customerID != 0 {
+		query += " AND pp.customer_id = ?"
+		args = append(args, customerID)
+	}
+	query += " ORDER BY pp.product_id, pp.customer_id IS NOT NULL, pp.customer_id, pp.effective_from DESC"
+
+	var productPrices []entity.ProductPrice
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &productPrices, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &productPrices, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if productPrices == nil {
+		return []entity.ProductPrice{}, nil
+	}
+	return productPrices, nil
+}
+
+func (repo *ProductPriceRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.ProductPrice, error) {
+	return repo.getOne(ctx, productPriceSelect+" WHERE pp.id = ?", []interface{}{id}, tx)
+}
+
+// GetOneByEffectiveFromQuery returns the price of a product starting on the given date, the default price when customerID is nil
+func (repo *ProductPriceRepository) GetOneByEffectiveFromQuery(ctx context.Context, productID int, customerID *int, effectiveFrom time.Time, tx *sqlx.Tx) (*entity.ProductPrice, error) {
+	query := productPriceSelect + " WHERE pp.product_id = ? AND pp.customer_id <=> ? AND pp.effective_from = ?"
+	return repo.getOne(ctx, query, []interface{}{productID, customerID, effectiveFrom}, tx)
+}
+
+// GetEffectiveQuery returns the price of a product in force on the given date, the default price when customerID is nil
+func (repo *ProductPriceRepository) GetEffectiveQuery(ctx context.Context, productID int, customerID *int, date time.Time, tx *sqlx.Tx) (*entity.ProductPrice, error) {
+	query := productPriceSelect + " WHERE pp.product_id = ? AND pp.customer_id <=> ? AND pp.effective_from <= ? ORDER BY pp.effective_from DESC LIMIT 1"
+	return repo.getOne(ctx, query, []interface{}{productID, customerID, date}, tx)
+}
+
+func (repo *ProductPriceRepository) getOne(ctx context.Context, query string, args []interface{}, tx *sqlx.Tx) (*entity.ProductPrice, error) {
+	var productPrice entity.ProductPrice
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &productPrice, query, args...)
+	} else {
+		err = repo.db.GetContext(ctx, &productPrice, query, args...)
+	}
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &productPrice, nil
+}
+
+func (repo *ProductPriceRepository) CreateCommand(ctx context.Context, productPrice *entity.ProductPrice, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO product_prices(product_id, customer_id, selling_price, effective_from, created_by) VALUES (:product_id, :customer_id, :selling_price, :effective_from, :created_by)`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, productPrice)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, productPrice)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "unique_product_price_effective_from") {
+			return &error_utils.ConstraintViolationError{Message: "Sản phẩm đã có giá bắt đầu từ ngày này"}
+		}
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	productPrice.ID = int(lastID)
+	return nil
+}
+
+func (repo *ProductPriceRepository) DeleteByIDCommand(ctx context.Context, id int, tx *sqlx.Tx) error {
+	deleteQuery := `DELETE FROM product_prices WHERE id = ?`
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, deleteQuery, id)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, deleteQuery, id)
+	return err
+}
+
+// ReassignCustomerCommand moves the price overrides of a customer to another one. Overrides the other customer
+// already has for the same product and date win and the moved customer's ones are left behind
+func (repo *ProductPriceRepository) ReassignCustomerCommand(ctx context.Context, fromCustomerID int, toCustomerID int, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE product_prices SET customer_id = ?
+		WHERE customer_id = ? AND (product_id, effective_from) NOT IN (
+			SELECT product_id, effective_from FROM (
+				SELECT product_id, effective_from FROM product_prices WHERE customer_id = ?
+			) existing
+		)`
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, updateQuery, toCustomerID, fromCustomerID, toCustomerID)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, updateQuery, toCustomerID, fromCustomerID, toCustomerID)
+	return err
+}
@@ -42,8 +42,15 @@ func (repo *ProductRepository) GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]
 }
 
 func (repo *ProductRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Product, error) {
+	return repo.getOne(ctx, "SELECT * FROM products WHERE id = ?", id, tx)
+}
+
+func (repo *ProductRepository) GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Product, error) {
+	return repo.getOne(ctx, "SELECT * FROM products WHERE id = ? FOR UPDATE", id, tx)
+}
+
+func (repo *ProductRepository) getOne(ctx context.Context, query string, id int, tx *sqlx.Tx) (*entity.Product, error) {
 	var product entity.Product
-	query := "SELECT * FROM products WHERE id = ?"
 	var err error
 
 	if tx != nil {
@@ -63,7 +70,7 @@ func (repo *ProductRepository) GetOneByIDQuery(ctx context.Context, id int, tx *
 }
 
 func (repo *ProductRepository) CreateCommand(ctx context.Context, product *entity.Product, tx *sqlx.Tx) error {
-	insertQuery := `INSERT INTO products(name, spec, original_price) VALUES (:name, :spec, :original_price)`
+	insertQuery := `INSERT INTO products(name, spec, original_price, average_cost, min_stock, reorder_quantity) VALUES (:name, :spec, :original_price, :average_cost, :min_stock, :reorder_quantity)`
 
 	var result sql.Result
 	var err error
@@ -90,7 +97,7 @@ func (repo *ProductRepository) CreateCommand(ctx context.Context, product *entit
 }
 
 func (repo *ProductRepository) UpdateCommand(ctx context.Context, product *entity.Product, tx *sqlx.Tx) error {
-	updateQuery := `UPDATE products SET name = :name, spec = :spec, original_price = :original_price WHERE id = :id`
+	updateQuery := `UPDATE products SET name = :name, spec = :spec, original_price = :original_price, min_stock = :min_stock, reorder_quantity = :reorder_quantity WHERE id = :id`
 
 	if tx != nil {
 		_, err := tx.NamedExecContext(ctx, updateQuery, product)
@@ -99,3 +106,14 @@ func (repo *ProductRepository) UpdateCommand(ctx context.Context, product *entit
 	_, err := repo.db.NamedExecContext(ctx, updateQuery, product)
 	return err
 }
+
+func (repo *ProductRepository) UpdateAverageCostCommand(ctx context.Context, productID int, averageCost int, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE products SET average_cost = ? WHERE id = ?`
+
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, updateQuery, averageCost, productID)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, updateQuery, averageCost, productID)
+	return err
+}
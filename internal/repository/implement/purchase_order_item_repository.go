@@ -0,0 +1,69 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+)
+
+type PurchaseOrderItemRepository struct {
+	db *sqlx.DB
+}
+
+func NewPurchaseOrderItemRepository(db database.Db) repository.PurchaseOrderItemRepository {
+	return &PurchaseOrderItemRepository{db: db}
+}
+
+func (repo *PurchaseOrderItemRepository) GetAllByPurchaseOrderIDQuery(ctx context.Context, purchaseOrderID int, tx *sqlx.Tx) ([]entity.PurchaseOrderItem, error) {
+	var purchaseOrderItems []entity.PurchaseOrderItem
+	query := `SELECT i.*, p.name AS product_name FROM purchase_order_items i
+		JOIN products p ON p.id = i.product_id
+		WHERE i.purchase_order_id = ? ORDER BY i.id`
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &purchaseOrderItems, query, purchaseOrderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &purchaseOrderItems, query, purchaseOrderID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if purchaseOrderItems == nil {
+		return []entity.PurchaseOrderItem{}, nil
+	}
+	return purchaseOrderItems, nil
+}
+
+func (repo *PurchaseOrderItemRepository) CreateCommand(ctx context.Context, purchaseOrderItem *entity.PurchaseOrderItem, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO purchase_order_items(purchase_order_id, product_id, quantity, received_quantity, purchase_price) VALUES (:purchase_order_id, :product_id, :quantity, :received_quantity, :purchase_price)`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, purchaseOrderItem)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, purchaseOrderItem)
+	}
+	if err != nil {
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	purchaseOrderItem.ID = int(lastID)
+	return nil
+}
+
+func (repo *PurchaseOrderItemRepository) AddReceivedQuantityCommand(ctx context.Context, id int, quantity int, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE purchase_order_items SET received_quantity = received_quantity + ? WHERE id = ?`
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, updateQuery, quantity, id)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, updateQuery, quantity, id)
+	return err
+}
@@ -0,0 +1,117 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+)
+
+type PurchaseOrderRepository struct {
+	db *sqlx.DB
+}
+
+func NewPurchaseOrderRepository(db database.Db) repository.PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: db}
+}
+
+// purchaseOrderSelect joins the supplier, warehouse and creator names and sums the lines at purchase price
+const purchaseOrderSelect = `SELECT po.*, s.name AS supplier_name, w.name AS warehouse_name, u.username AS created_by_name,
+		(SELECT COALESCE(SUM(i.quantity * i.purchase_price), 0) FROM purchase_order_items i WHERE i.purchase_order_id = po.id) AS total_amount
+	FROM purchase_orders po
+	JOIN suppliers s ON s.id = po.supplier_id
+	JOIN warehouses w ON w.id = po.warehouse_id
+	JOIN users u ON u.id = po.created_by`
+
+func (repo *PurchaseOrderRepository) GetAllWithFiltersQuery(ctx context.Context, supplierID int, statuses []string, tx *sqlx.Tx) ([]entity.PurchaseOrder, error) {
+	query := purchaseOrderSelect + " WHERE 1=1"
+	var args []interface{}
+
+	if supplierID != 0 {
+		query += " AND po.supplier_id = ?"
+		args = append(args, supplierID)
+	}
+	if len(statuses) > 0 {
+		inQuery, inArgs, err := sqlx.In(" AND po.status IN (?)", statuses)
+		if err != nil {
+			return nil, err
+		}
+		query += inQuery
+		args = append(args, inArgs...)
+	}
+	query += " ORDER BY po.id DESC"
+
+	var purchaseOrders []entity.PurchaseOrder
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &purchaseOrders, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &purchaseOrders, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if purchaseOrders == nil {
+		return []entity.PurchaseOrder{}, nil
+	}
+	return purchaseOrders, nil
+}
+
+func (repo *PurchaseOrderRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.PurchaseOrder, error) {
+	return repo.getOne(ctx, purchaseOrderSelect+" WHERE po.id = ?", id, tx)
+}
+
+func (repo *PurchaseOrderRepository) GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.PurchaseOrder, error) {
+	return repo.getOne(ctx, "SELECT * FROM purchase_orders WHERE id = ? FOR UPDATE", id, tx)
+}
+
+func (repo *PurchaseOrderRepository) getOne(ctx context.Context, query string, id int, tx *sqlx.Tx) (*entity.PurchaseOrder, error) {
+	var purchaseOrder entity.PurchaseOrder
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &purchaseOrder, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &purchaseOrder, query, id)
+	}
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &purchaseOrder, nil
+}
+
+func (repo *PurchaseOrderRepository) CreateCommand(ctx context.Context, purchaseOrder *entity.PurchaseOrder, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO purchase_orders(supplier_id, warehouse_id, status, expected_date, note, created_by) VALUES (:supplier_id, :warehouse_id, :status, :expected_date, :note, :created_by)`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, purchaseOrder)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, purchaseOrder)
+	}
+	if err != nil {
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	purchaseOrder.ID = int(lastID)
+	return nil
+}
+
+func (repo *PurchaseOrderRepository) UpdateStatusCommand(ctx context.Context, purchaseOrder *entity.PurchaseOrder, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE purchase_orders SET status = :status, received_at = :received_at, cancelled_at = :cancelled_at WHERE id = :id`
+	if tx != nil {
+		_, err := tx.NamedExecContext(ctx, updateQuery, purchaseOrder)
+		return err
+	}
+	_, err := repo.db.NamedExecContext(ctx, updateQuery, purchaseOrder)
+	return err
+}
@@ -0,0 +1,80 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+)
+
+type RefreshTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewRefreshTokenRepository(db database.Db) repository.RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+func (repo *RefreshTokenRepository) GetOneByTokenHashForUpdateQuery(ctx context.Context, tokenHash string, tx *sqlx.Tx) (*entity.RefreshToken, error) {
+	var refreshToken entity.RefreshToken
+	query := "SELECT * FROM refresh_tokens WHERE token_hash = ? FOR UPDATE"
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &refreshToken, query, tokenHash)
+	} else {
+		err = repo.db.GetContext(ctx, &refreshToken, query, tokenHash)
+	}
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &refreshToken, nil
+}
+
+func (repo *RefreshTokenRepository) CreateCommand(ctx context.Context, refreshToken *entity.RefreshToken, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO refresh_tokens(user_id, token_hash, expires_at) VALUES (:user_id, :token_hash, :expires_at)`
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, refreshToken)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, refreshToken)
+	}
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	refreshToken.ID = int(lastID)
+	return nil
+}
+
+func (repo *RefreshTokenRepository) RevokeCommand(ctx context.Context, id int, replacedByID *int, tx *sqlx.Tx) error {
+	query := "UPDATE refresh_tokens SET revoked_at = NOW(), replaced_by_id = ? WHERE id = ? AND revoked_at IS NULL"
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, query, replacedByID, id)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, query, replacedByID, id)
+	return err
+}
+
+func (repo *RefreshTokenRepository) RevokeAllByUserIDCommand(ctx context.Context, userID int, tx *sqlx.Tx) error {
+	query := "UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = ? AND revoked_at IS NULL"
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, query, userID)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, query, userID)
+	return err
+}
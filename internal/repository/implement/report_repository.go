@@ -0,0 +1,143 @@
+package repositoryimplement
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+)
+
+type ReportRepository struct {
+	db *sqlx.DB
+}
+
+func NewReportRepository(db database.Db) repository.ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// reportFiguresColumns aggregates the stored order figures of the orders (o) of reportOrdersFrom.
+// Profit/loss follows calculateOrderProfitLoss in the order service.
+const reportFiguresColumns = `COUNT(*) AS order_count,
+		COALESCE(SUM(o.total_sales_revenue - o.total_returned_revenue), 0) AS revenue,
+		COALESCE(SUM(o.total_original_cost - o.total_returned_cost), 0) AS original_cost,
+		COALESCE(SUM(o.additional_cost), 0) AS additional_cost,
+		COALESCE(SUM(TRUNCATE(` + orderNetAmountExpr + ` * o.tax_percent / 100, 0)), 0) AS tax,
+		COALESCE(SUM(o.total_sales_revenue - o.total_returned_revenue - (o.total_original_cost - o.total_returned_cost) + o.additional_cost), 0) AS profit_loss`
+
+// reportOrdersFrom keeps the non-cancelled orders within the date range, with their customer and items amount
+const reportOrdersFrom = orderWithItemsFrom + `
+		WHERE o.delivery_status <> ? AND o.order_date BETWEEN ? AND ?`
+
+func (repo *ReportRepository) GetFiguresByPeriodQuery(ctx context.Context, period string, fromDate time.Time, toDate time.Time, tx *sqlx.Tx) ([]entity.PeriodReportRow, error) {
+	var periodExpr string
+	switch period {
+	case entity.ReportPeriod.DAY:
+		periodExpr = "DATE_FORMAT(o.order_date, '%Y-%m-%d')"
+	case entity.ReportPeriod.WEEK:
+		// ISO week, e.g. 2025-W07
+		periodExpr = "DATE_FORMAT(o.order_date, '%x-W%v')"
+	case entity.ReportPeriod.MONTH:
+		periodExpr = "DATE_FORMAT(o.order_date, '%Y-%m')"
+	default:
+		return nil, errors.New("unsupported report period: " + period)
+	}
+
+	var rows []entity.PeriodReportRow
+	query := `SELECT ` + periodExpr + ` AS period, ` + reportFiguresColumns + reportOrdersFrom + `
+		GROUP BY period ORDER BY period`
+	args := []interface{}{entity.OrderDeliveryStatus.CANCELLED, fromDate, toDate}
+
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &rows, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &rows, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		return []entity.PeriodReportRow{}, nil
+	}
+	return rows, nil
+}
+
+func (repo *ReportRepository) GetFiguresByCustomerQuery(ctx context.Context, fromDate time.Time, toDate time.Time, tx *sqlx.Tx) ([]entity.CustomerReportRow, error) {
+	var rows []entity.CustomerReportRow
+	query := `SELECT o.customer_id, c.name AS customer_name, ` + reportFiguresColumns + reportOrdersFrom + `
+		GROUP BY o.customer_id, c.name ORDER BY profit_loss DESC, o.customer_id`
+	args := []interface{}{entity.OrderDeliveryStatus.CANCELLED, fromDate, toDate}
+
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &rows, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &rows, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		return []entity.CustomerReportRow{}, nil
+	}
+	return rows, nil
+}
+
+// GetFiguresByProductQuery values each product at the selling price and the original price snapshot of its order items,
+// minus what customers returned. Additional cost and tax belong to the whole order and are not split across products.
+func (repo *ReportRepository) GetFiguresByProductQuery(ctx context.Context, fromDate time.Time, toDate time.Time, tx *sqlx.Tx) ([]entity.ProductReportRow, error) {
+	var rows []entity.ProductReportRow
+	query := `SELECT p.id AS product_id, p.name AS product_name,
+			sold.order_count,
+			sold.quantity_sold,
+			COALESCE(returned.quantity_returned, 0) AS quantity_returned,
+			sold.revenue - COALESCE(returned.returned_revenue, 0) AS revenue,
+			sold.original_cost - COALESCE(returned.returned_cost, 0) AS original_cost,
+			sold.revenue - COALESCE(returned.returned_revenue, 0) - (sold.original_cost - COALESCE(returned.returned_cost, 0)) AS profit_loss
+		FROM (
+			SELECT oi.product_id,
+				COUNT(DISTINCT oi.order_id) AS order_count,
+				SUM(oi.quantity) AS quantity_sold,
+				SUM(oi.quantity * oi.selling_price - (oi.quantity * oi.selling_price * oi.discount) DIV 100) AS revenue,
+				SUM(oi.quantity * oi.original_price) AS original_cost
+			FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			WHERE o.delivery_status <> ? AND o.order_date BETWEEN ? AND ?
+			GROUP BY oi.product_id
+		) sold
+		JOIN products p ON p.id = sold.product_id
+		LEFT JOIN (
+			SELECT ri.product_id,
+				SUM(ri.quantity) AS quantity_returned,
+				SUM(ri.refund_amount) AS returned_revenue,
+				SUM(ri.original_cost) AS returned_cost
+			FROM order_return_items ri
+			JOIN order_returns rt ON rt.id = ri.order_return_id
+			JOIN orders o ON o.id = rt.order_id
+			WHERE o.delivery_status <> ? AND o.order_date BETWEEN ? AND ?
+			GROUP BY ri.product_id
+		) returned ON returned.product_id = sold.product_id
+		ORDER BY profit_loss DESC, p.id`
+	args := []interface{}{
+		entity.OrderDeliveryStatus.CANCELLED, fromDate, toDate,
+		entity.OrderDeliveryStatus.CANCELLED, fromDate, toDate,
+	}
+
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &rows, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &rows, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		return []entity.ProductReportRow{}, nil
+	}
+	return rows, nil
+}
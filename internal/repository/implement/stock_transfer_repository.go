@@ -0,0 +1,97 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+)
+
+type StockTransferRepository struct {
+	db *sqlx.DB
+}
+
+func NewStockTransferRepository(db database.Db) repository.StockTransferRepository {
+	return &StockTransferRepository{db: db}
+}
+
+// stockTransferSelect joins the product, warehouse and creator names
+const stockTransferSelect = `SELECT st.*, p.name AS product_name, fw.name AS from_warehouse_name, tw.name AS to_warehouse_name, u.username AS created_by_name
+	FROM stock_transfers st
+	JOIN products p ON p.id = st.product_id
+	JOIN warehouses fw ON fw.id = st.from_warehouse_id
+	JOIN warehouses tw ON tw.id = st.to_warehouse_id
+	JOIN users u ON u.id = st.created_by`
+
+// GetAllWithFiltersQuery returns the transfers of a product and/or touching a warehouse on either side, 0 means no filter
+func (repo *StockTransferRepository) GetAllWithFiltersQuery(ctx context.Context, productID int, warehouseID int, tx *sqlx.Tx) ([]entity.StockTransfer, error) {
+	query := stockTransferSelect + " WHERE 1=1"
+	var args []interface{}
+
+	if productID != 0 {
+		query += " AND st.product_id = ?"
+		args = append(args, productID)
+	}
+	if warehouseID != 0 {
+		query += " AND (st.from_warehouse_id = ? OR st.to_warehouse_id = ?)"
+		args = append(args, warehouseID, warehouseID)
+	}
+	query += " ORDER BY st.id DESC"
+
+	var stockTransfers []entity.StockTransfer
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &stockTransfers, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &stockTransfers, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stockTransfers == nil {
+		return []entity.StockTransfer{}, nil
+	}
+	return stockTransfers, nil
+}
+
+func (repo *StockTransferRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.StockTransfer, error) {
+	var stockTransfer entity.StockTransfer
+	query := stockTransferSelect + " WHERE st.id = ?"
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &stockTransfer, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &stockTransfer, query, id)
+	}
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &stockTransfer, nil
+}
+
+func (repo *StockTransferRepository) CreateCommand(ctx context.Context, stockTransfer *entity.StockTransfer, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO stock_transfers(product_id, from_warehouse_id, to_warehouse_id, quantity, note, created_by) VALUES (:product_id, :from_warehouse_id, :to_warehouse_id, :quantity, :note, :created_by)`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, stockTransfer)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, stockTransfer)
+	}
+	if err != nil {
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	stockTransfer.ID = int(lastID)
+	return nil
+}
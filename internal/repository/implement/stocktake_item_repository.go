@@ -0,0 +1,63 @@
+package repositoryimplement
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+)
+
+type StocktakeItemRepository struct {
+	db *sqlx.DB
+}
+
+func NewStocktakeItemRepository(db database.Db) repository.StocktakeItemRepository {
+	return &StocktakeItemRepository{db: db}
+}
+
+func (repo *StocktakeItemRepository) GetAllByStocktakeIDQuery(ctx context.Context, stocktakeID int, tx *sqlx.Tx) ([]entity.StocktakeItem, error) {
+	var stocktakeItems []entity.StocktakeItem
+	query := `SELECT i.*, p.name AS product_name, u.username AS counted_by_name FROM stocktake_items i
+		JOIN products p ON p.id = i.product_id
+		LEFT JOIN users u ON u.id = i.counted_by
+		WHERE i.stocktake_id = ? ORDER BY i.product_id`
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &stocktakeItems, query, stocktakeID)
+	} else {
+		err = repo.db.SelectContext(ctx, &stocktakeItems, query, stocktakeID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stocktakeItems == nil {
+		return []entity.StocktakeItem{}, nil
+	}
+	return stocktakeItems, nil
+}
+
+// CreateSnapshotCommand adds an item for every product of the warehouse, holding its current book quantity and version
+func (repo *StocktakeItemRepository) CreateSnapshotCommand(ctx context.Context, stocktakeID int, warehouseID int, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO stocktake_items(stocktake_id, product_id, snapshot_quantity, snapshot_version)
+		SELECT ?, product_id, quantity, version FROM inventory WHERE warehouse_id = ?`
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, insertQuery, stocktakeID, warehouseID)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, insertQuery, stocktakeID, warehouseID)
+	return err
+}
+
+// UpdateCountCommand records a count together with the book quantity and version it was taken against
+func (repo *StocktakeItemRepository) UpdateCountCommand(ctx context.Context, stocktakeItem *entity.StocktakeItem, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE stocktake_items SET snapshot_quantity = :snapshot_quantity, snapshot_version = :snapshot_version,
+		counted_quantity = :counted_quantity, counted_by = :counted_by, counted_at = :counted_at WHERE id = :id`
+	if tx != nil {
+		_, err := tx.NamedExecContext(ctx, updateQuery, stocktakeItem)
+		return err
+	}
+	_, err := repo.db.NamedExecContext(ctx, updateQuery, stocktakeItem)
+	return err
+}
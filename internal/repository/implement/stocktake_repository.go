@@ -0,0 +1,133 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+)
+
+type StocktakeRepository struct {
+	db *sqlx.DB
+}
+
+func NewStocktakeRepository(db database.Db) repository.StocktakeRepository {
+	return &StocktakeRepository{db: db}
+}
+
+// stocktakeSelect joins the warehouse and creator names
+const stocktakeSelect = `SELECT st.*, w.name AS warehouse_name, u.username AS created_by_name
+	FROM stocktakes st
+	JOIN warehouses w ON w.id = st.warehouse_id
+	JOIN users u ON u.id = st.created_by`
+
+func (repo *StocktakeRepository) GetAllWithFiltersQuery(ctx context.Context, warehouseID int, statuses []string, tx *sqlx.Tx) ([]entity.Stocktake, error) {
+	query := stocktakeSelect + " WHERE 1=1"
+	var args []interface{}
+
+	if warehouseID != 0 {
+		query += " AND st.warehouse_id = ?"
+		args = append(args, warehouseID)
+	}
+	if len(statuses) > 0 {
+		inQuery, inArgs, err := sqlx.In(" AND st.status IN (?)", statuses)
+		if err != nil {
+			return nil, err
+		}
+		query += inQuery
+		args = append(args, inArgs...)
+	}
+	query += " ORDER BY st.id DESC"
+
+	var stocktakes []entity.Stocktake
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &stocktakes, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &stocktakes, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stocktakes == nil {
+		return []entity.Stocktake{}, nil
+	}
+	return stocktakes, nil
+}
+
+func (repo *StocktakeRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Stocktake, error) {
+	return repo.getOne(ctx, stocktakeSelect+" WHERE st.id = ?", id, tx)
+}
+
+func (repo *StocktakeRepository) GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Stocktake, error) {
+	return repo.getOne(ctx, "SELECT * FROM stocktakes WHERE id = ? FOR UPDATE", id, tx)
+}
+
+func (repo *StocktakeRepository) GetOpenByWarehouseIDQuery(ctx context.Context, warehouseID int, tx *sqlx.Tx) (*entity.Stocktake, error) {
+	var stocktake entity.Stocktake
+	query := "SELECT * FROM stocktakes WHERE warehouse_id = ? AND status = ? ORDER BY id LIMIT 1"
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &stocktake, query, warehouseID, entity.StocktakeStatus.OPEN)
+	} else {
+		err = repo.db.GetContext(ctx, &stocktake, query, warehouseID, entity.StocktakeStatus.OPEN)
+	}
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &stocktake, nil
+}
+
+func (repo *StocktakeRepository) getOne(ctx context.Context, query string, id int, tx *sqlx.Tx) (*entity.Stocktake, error) {
+	var stocktake entity.Stocktake
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &stocktake, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &stocktake, query, id)
+	}
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &stocktake, nil
+}
+
+func (repo *StocktakeRepository) CreateCommand(ctx context.Context, stocktake *entity.Stocktake, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO stocktakes(warehouse_id, status, note, created_by) VALUES (:warehouse_id, :status, :note, :created_by)`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, stocktake)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, stocktake)
+	}
+	if err != nil {
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	stocktake.ID = int(lastID)
+	return nil
+}
+
+func (repo *StocktakeRepository) UpdateStatusCommand(ctx context.Context, stocktake *entity.Stocktake, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE stocktakes SET status = :status, posted_by = :posted_by, posted_at = :posted_at, cancelled_at = :cancelled_at WHERE id = :id`
+	if tx != nil {
+		_, err := tx.NamedExecContext(ctx, updateQuery, stocktake)
+		return err
+	}
+	_, err := repo.db.NamedExecContext(ctx, updateQuery, stocktake)
+	return err
+}
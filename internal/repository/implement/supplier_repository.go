@@ -0,0 +1,86 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+)
+
+type SupplierRepository struct {
+	db *sqlx.DB
+}
+
+func NewSupplierRepository(db database.Db) repository.SupplierRepository {
+	return &SupplierRepository{db: db}
+}
+
+func (repo *SupplierRepository) GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Supplier, error) {
+	var suppliers []entity.Supplier
+	query := "SELECT * FROM suppliers ORDER BY name, id"
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &suppliers, query)
+	} else {
+		err = repo.db.SelectContext(ctx, &suppliers, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if suppliers == nil {
+		return []entity.Supplier{}, nil
+	}
+	return suppliers, nil
+}
+
+func (repo *SupplierRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Supplier, error) {
+	var supplier entity.Supplier
+	query := "SELECT * FROM suppliers WHERE id = ?"
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &supplier, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &supplier, query, id)
+	}
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+func (repo *SupplierRepository) CreateCommand(ctx context.Context, supplier *entity.Supplier, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO suppliers(name, phone, address, note) VALUES (:name, :phone, :address, :note)`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, supplier)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, supplier)
+	}
+	if err != nil {
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	supplier.ID = int(lastID)
+	return nil
+}
+
+func (repo *SupplierRepository) UpdateCommand(ctx context.Context, supplier *entity.Supplier, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE suppliers SET name = :name, phone = :phone, address = :address, note = :note WHERE id = :id`
+	if tx != nil {
+		_, err := tx.NamedExecContext(ctx, updateQuery, supplier)
+		return err
+	}
+	_, err := repo.db.NamedExecContext(ctx, updateQuery, supplier)
+	return err
+}
@@ -2,6 +2,7 @@ package repositoryimplement
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pna/order-app-backend/internal/database"
@@ -19,13 +20,28 @@ func NewUserRepository(db database.Db) repository.UserRepository {
 }
 
 func (repo *UserRepository) CreateCommand(ctx context.Context, user *entity.User, tx *sqlx.Tx) error {
-	insertQuery := `INSERT INTO users(username, password) VALUES (:username, :password)`
+	insertQuery := `INSERT INTO users(username, password, role, is_active) VALUES (:username, :password, :role, :is_active)`
+
+	var result sql.Result
+	var err error
+
 	if tx != nil {
-		_, err := tx.NamedExecContext(ctx, insertQuery, user)
+		result, err = tx.NamedExecContext(ctx, insertQuery, user)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, user)
+	}
+
+	if err != nil {
 		return err
 	}
-	_, err := repo.db.NamedExecContext(ctx, insertQuery, user)
-	return err
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	user.ID = int(lastID)
+	return nil
 }
 
 func (repo *UserRepository) FindByUsernameQuery(ctx context.Context, username string, tx *sqlx.Tx) (*entity.User, error) {
@@ -67,3 +83,49 @@ func (repo *UserRepository) FindByIDQuery(ctx context.Context, id int, tx *sqlx.
 
 	return &user, nil
 }
+
+func (repo *UserRepository) GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.User, error) {
+	users := make([]entity.User, 0)
+	query := "SELECT * FROM users ORDER BY id"
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &users, query)
+	} else {
+		err = repo.db.SelectContext(ctx, &users, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (repo *UserRepository) UpdateIsActiveCommand(ctx context.Context, id int, isActive bool, tx *sqlx.Tx) error {
+	query := "UPDATE users SET is_active = ? WHERE id = ?"
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, query, isActive, id)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, query, isActive, id)
+	return err
+}
+
+func (repo *UserRepository) UpdatePasswordCommand(ctx context.Context, id int, password string, tx *sqlx.Tx) error {
+	query := "UPDATE users SET password = ? WHERE id = ?"
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, query, password, id)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, query, password, id)
+	return err
+}
+
+func (repo *UserRepository) IncrementTokenVersionCommand(ctx context.Context, id int, tx *sqlx.Tx) error {
+	query := "UPDATE users SET token_version = token_version + 1 WHERE id = ?"
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, query, id)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, query, id)
+	return err
+}
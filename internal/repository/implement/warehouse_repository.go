@@ -0,0 +1,93 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/database"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+)
+
+type WarehouseRepository struct {
+	db *sqlx.DB
+}
+
+func NewWarehouseRepository(db database.Db) repository.WarehouseRepository {
+	return &WarehouseRepository{db: db}
+}
+
+func (repo *WarehouseRepository) GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Warehouse, error) {
+	var warehouses []entity.Warehouse
+	query := "SELECT * FROM warehouses ORDER BY is_default DESC, id"
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &warehouses, query)
+	} else {
+		err = repo.db.SelectContext(ctx, &warehouses, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if warehouses == nil {
+		return []entity.Warehouse{}, nil
+	}
+	return warehouses, nil
+}
+
+func (repo *WarehouseRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Warehouse, error) {
+	return repo.getOne(ctx, "SELECT * FROM warehouses WHERE id = ?", []interface{}{id}, tx)
+}
+
+func (repo *WarehouseRepository) GetDefaultQuery(ctx context.Context, tx *sqlx.Tx) (*entity.Warehouse, error) {
+	return repo.getOne(ctx, "SELECT * FROM warehouses WHERE is_default = TRUE ORDER BY id LIMIT 1", nil, tx)
+}
+
+func (repo *WarehouseRepository) getOne(ctx context.Context, query string, args []interface{}, tx *sqlx.Tx) (*entity.Warehouse, error) {
+	var warehouse entity.Warehouse
+	var err error
+	if tx != nil {
+		err = tx.GetContext(ctx, &warehouse, query, args...)
+	} else {
+		err = repo.db.GetContext(ctx, &warehouse, query, args...)
+	}
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (repo *WarehouseRepository) CreateCommand(ctx context.Context, warehouse *entity.Warehouse, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO warehouses(name, address) VALUES (:name, :address)`
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, warehouse)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, warehouse)
+	}
+	if err != nil {
+		return err
+	}
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	warehouse.ID = int(lastID)
+	return nil
+}
+
+func (repo *WarehouseRepository) UpdateCommand(ctx context.Context, warehouse *entity.Warehouse, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE warehouses SET name = :name, address = :address WHERE id = :id`
+	if tx != nil {
+		_, err := tx.NamedExecContext(ctx, updateQuery, warehouse)
+		return err
+	}
+	_, err := repo.db.NamedExecContext(ctx, updateQuery, warehouse)
+	return err
+}
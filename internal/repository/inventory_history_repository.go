@@ -2,12 +2,16 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pna/order-app-backend/internal/domain/entity"
 )
 
 type InventoryHistoryRepository interface {
-	GetAllByProductIDQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.InventoryHistory, error)
+	GetAllByProductIDQuery(ctx context.Context, productID int, warehouseID int, tx *sqlx.Tx) ([]entity.InventoryHistory, error)
+	GetMovementSummariesQuery(ctx context.Context, fromDate time.Time, toDate time.Time, productID int, warehouseID int, tx *sqlx.Tx) ([]entity.InventoryMovementSummary, error)
+	GetBalancesBeforeQuery(ctx context.Context, before time.Time, productID int, warehouseID int, tx *sqlx.Tx) (map[int]int, error)
+	GetInconsistentProductIDsQuery(ctx context.Context, productID int, warehouseID int, tx *sqlx.Tx) ([]int, error)
 	CreateCommand(ctx context.Context, inventoryHistory *entity.InventoryHistory, tx *sqlx.Tx) error
 }
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type LoginAttemptFilter struct {
+	Username  string
+	IPAddress string
+	Success   *bool
+	FromDate  *time.Time
+	ToDate    *time.Time
+	Limit     int
+}
+
+type LoginAttemptRepository interface {
+	GetAllWithFiltersQuery(ctx context.Context, filter LoginAttemptFilter, tx *sqlx.Tx) ([]entity.LoginAttempt, error)
+	// Failed attempts since the later of `since` and the last successful login; LOCKED attempts are not counted
+	GetFailureStatsByUsernameQuery(ctx context.Context, username string, since time.Time, tx *sqlx.Tx) (*entity.LoginFailureStats, error)
+	// Failed attempts since `since`, successful logins from the same address do not reset them
+	GetFailureStatsByIPAddressQuery(ctx context.Context, ipAddress string, since time.Time, tx *sqlx.Tx) (*entity.LoginFailureStats, error)
+	CreateCommand(ctx context.Context, loginAttempt *entity.LoginAttempt, tx *sqlx.Tx) error
+}
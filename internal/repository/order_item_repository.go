@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pna/order-app-backend/internal/domain/entity"
@@ -9,6 +10,10 @@ import (
 
 type OrderItemRepository interface {
 	GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.OrderItem, error)
+	GetAllByOrderIDsQuery(ctx context.Context, orderIDs []int, tx *sqlx.Tx) ([]entity.OrderItem, error)
+	GetSoldQuantitiesSinceQuery(ctx context.Context, since time.Time, tx *sqlx.Tx) (map[int]int, error)
+	GetTopProductsByCustomerIDQuery(ctx context.Context, customerID int, limit int, tx *sqlx.Tx) ([]entity.CustomerProductPurchase, error)
 	CreateCommand(ctx context.Context, orderItem *entity.OrderItem, tx *sqlx.Tx) error
+	UpdateCommand(ctx context.Context, orderItem *entity.OrderItem, tx *sqlx.Tx) error
 	DeleteByIDCommand(ctx context.Context, id int, tx *sqlx.Tx) error
 }
@@ -8,11 +8,33 @@ import (
 	"github.com/pna/order-app-backend/internal/domain/entity"
 )
 
+// OrderFilter narrows down an order listing. Zero values mean "no filter".
+type OrderFilter struct {
+	CustomerID       int
+	DeliveryStatuses []string
+	DebtStatuses     []string
+	Search           string // Matched against customer name and phone
+	MinAmount        *int
+	MaxAmount        *int
+	FromDate         *time.Time
+	ToDate           *time.Time
+	SortBy           string // order_date_asc, order_date_desc, default id DESC
+
+	// Keyset pagination: rows strictly after the (CursorOrderDate, CursorID) row in the sort order
+	CursorOrderDate *time.Time
+	CursorID        int
+	Limit           int // 0 means no limit
+}
+
 type OrderRepository interface {
 	GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Order, error)
-	GetAllWithFiltersQuery(ctx context.Context, customerID int, deliveryStatuses string, sortBy string, fromDate *time.Time, toDate *time.Time, tx *sqlx.Tx) ([]entity.Order, error)
+	GetAllWithFiltersQuery(ctx context.Context, filter OrderFilter, tx *sqlx.Tx) ([]entity.OrderWithSummary, error)
+	GetSummaryWithFiltersQuery(ctx context.Context, filter OrderFilter, tx *sqlx.Tx) (*entity.OrderListSummary, error)
 	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Order, error)
+	GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Order, error)
 	CreateCommand(ctx context.Context, order *entity.Order, tx *sqlx.Tx) error
 	UpdateCommand(ctx context.Context, order *entity.Order, tx *sqlx.Tx) error
+	UpdateDebtStatusCommand(ctx context.Context, id int, debtStatus string, tx *sqlx.Tx) error
 	DeleteByIDCommand(ctx context.Context, id int, tx *sqlx.Tx) error
+	ReassignCustomerCommand(ctx context.Context, fromCustomerID int, toCustomerID int, tx *sqlx.Tx) (int, error)
 }
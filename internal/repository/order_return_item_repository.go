@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type OrderReturnItemRepository interface {
+	GetAllByOrderReturnIDsQuery(ctx context.Context, orderReturnIDs []int, tx *sqlx.Tx) ([]entity.OrderReturnItem, error)
+	GetReturnedQuantitiesByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) (map[int]int, error)
+	CreateCommand(ctx context.Context, orderReturnItem *entity.OrderReturnItem, tx *sqlx.Tx) error
+}
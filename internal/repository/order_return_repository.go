@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type OrderReturnRepository interface {
+	GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.OrderReturn, error)
+	CreateCommand(ctx context.Context, orderReturn *entity.OrderReturn, tx *sqlx.Tx) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type OrderStatusHistoryRepository interface {
+	GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.OrderStatusHistory, error)
+	CreateCommand(ctx context.Context, orderStatusHistory *entity.OrderStatusHistory, tx *sqlx.Tx) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type PaymentRepository interface {
+	GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.Payment, error)
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Payment, error)
+	GetTotalPaidByOrderIDsQuery(ctx context.Context, orderIDs []int, tx *sqlx.Tx) (map[int]int, error)
+	CreateCommand(ctx context.Context, payment *entity.Payment, tx *sqlx.Tx) error
+	DeleteByIDCommand(ctx context.Context, id int, tx *sqlx.Tx) error
+}
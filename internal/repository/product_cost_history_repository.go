@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type ProductCostHistoryRepository interface {
+	GetAllByProductIDQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.ProductCostHistory, error)
+	CreateCommand(ctx context.Context, productCostHistory *entity.ProductCostHistory, tx *sqlx.Tx) error
+}
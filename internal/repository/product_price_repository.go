@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type ProductPriceRepository interface {
+	GetAllWithFiltersQuery(ctx context.Context, productID int, customerID int, tx *sqlx.Tx) ([]entity.ProductPrice, error)
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.ProductPrice, error)
+	GetOneByEffectiveFromQuery(ctx context.Context, productID int, customerID *int, effectiveFrom time.Time, tx *sqlx.Tx) (*entity.ProductPrice, error)
+	GetEffectiveQuery(ctx context.Context, productID int, customerID *int, date time.Time, tx *sqlx.Tx) (*entity.ProductPrice, error)
+	CreateCommand(ctx context.Context, productPrice *entity.ProductPrice, tx *sqlx.Tx) error
+	DeleteByIDCommand(ctx context.Context, id int, tx *sqlx.Tx) error
+	ReassignCustomerCommand(ctx context.Context, fromCustomerID int, toCustomerID int, tx *sqlx.Tx) error
+}
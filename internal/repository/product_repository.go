@@ -10,6 +10,8 @@ import (
 type ProductRepository interface {
 	GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Product, error)
 	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Product, error)
+	GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Product, error)
 	CreateCommand(ctx context.Context, product *entity.Product, tx *sqlx.Tx) error
 	UpdateCommand(ctx context.Context, product *entity.Product, tx *sqlx.Tx) error
+	UpdateAverageCostCommand(ctx context.Context, productID int, averageCost int, tx *sqlx.Tx) error
 }
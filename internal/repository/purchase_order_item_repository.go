@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type PurchaseOrderItemRepository interface {
+	GetAllByPurchaseOrderIDQuery(ctx context.Context, purchaseOrderID int, tx *sqlx.Tx) ([]entity.PurchaseOrderItem, error)
+	CreateCommand(ctx context.Context, purchaseOrderItem *entity.PurchaseOrderItem, tx *sqlx.Tx) error
+	AddReceivedQuantityCommand(ctx context.Context, id int, quantity int, tx *sqlx.Tx) error
+}
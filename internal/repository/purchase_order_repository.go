@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type PurchaseOrderRepository interface {
+	GetAllWithFiltersQuery(ctx context.Context, supplierID int, statuses []string, tx *sqlx.Tx) ([]entity.PurchaseOrder, error)
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.PurchaseOrder, error)
+	GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.PurchaseOrder, error)
+	CreateCommand(ctx context.Context, purchaseOrder *entity.PurchaseOrder, tx *sqlx.Tx) error
+	UpdateStatusCommand(ctx context.Context, purchaseOrder *entity.PurchaseOrder, tx *sqlx.Tx) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type RefreshTokenRepository interface {
+	GetOneByTokenHashForUpdateQuery(ctx context.Context, tokenHash string, tx *sqlx.Tx) (*entity.RefreshToken, error)
+	CreateCommand(ctx context.Context, refreshToken *entity.RefreshToken, tx *sqlx.Tx) error
+	RevokeCommand(ctx context.Context, id int, replacedByID *int, tx *sqlx.Tx) error
+	RevokeAllByUserIDCommand(ctx context.Context, userID int, tx *sqlx.Tx) error
+}
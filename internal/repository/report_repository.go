@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type ReportRepository interface {
+	GetFiguresByPeriodQuery(ctx context.Context, period string, fromDate time.Time, toDate time.Time, tx *sqlx.Tx) ([]entity.PeriodReportRow, error)
+	GetFiguresByCustomerQuery(ctx context.Context, fromDate time.Time, toDate time.Time, tx *sqlx.Tx) ([]entity.CustomerReportRow, error)
+	GetFiguresByProductQuery(ctx context.Context, fromDate time.Time, toDate time.Time, tx *sqlx.Tx) ([]entity.ProductReportRow, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type StockTransferRepository interface {
+	GetAllWithFiltersQuery(ctx context.Context, productID int, warehouseID int, tx *sqlx.Tx) ([]entity.StockTransfer, error)
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.StockTransfer, error)
+	CreateCommand(ctx context.Context, stockTransfer *entity.StockTransfer, tx *sqlx.Tx) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type StocktakeItemRepository interface {
+	GetAllByStocktakeIDQuery(ctx context.Context, stocktakeID int, tx *sqlx.Tx) ([]entity.StocktakeItem, error)
+	CreateSnapshotCommand(ctx context.Context, stocktakeID int, warehouseID int, tx *sqlx.Tx) error
+	UpdateCountCommand(ctx context.Context, stocktakeItem *entity.StocktakeItem, tx *sqlx.Tx) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+)
+
+type StocktakeRepository interface {
+	GetAllWithFiltersQuery(ctx context.Context, warehouseID int, statuses []string, tx *sqlx.Tx) ([]entity.Stocktake, error)
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Stocktake, error)
+	GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Stocktake, error)
+	GetOpenByWarehouseIDQuery(ctx context.Context, warehouseID int, tx *sqlx.Tx) (*entity.Stocktake, error)
+	CreateCommand(ctx context.Context, stocktake *entity.Stocktake, tx *sqlx.Tx) error
+	UpdateStatusCommand(ctx context.Context, stocktake *entity.Stocktake, tx *sqlx.Tx) error
+}
@@ -11,4 +11,8 @@ type UserRepository interface {
 	CreateCommand(ctx context.Context, user *entity.User, tx *sqlx.Tx) error
 	FindByUsernameQuery(ctx context.Context, username string, tx *sqlx.Tx) (*entity.User, error)
 	FindByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.User, error)
+	GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.User, error)
+	UpdateIsActiveCommand(ctx context.Context, id int, isActive bool, tx *sqlx.Tx) error
+	UpdatePasswordCommand(ctx context.Context, id int, password string, tx *sqlx.Tx) error
+	IncrementTokenVersionCommand(ctx context.Context, id int, tx *sqlx.Tx) error
 }
@@ -0,0 +1,10 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/model"
+)
+
+type AuditLogService interface {
+	GetAll(ctx *gin.Context, request model.GetAllAuditLogsRequest) (*model.GetAllAuditLogsResponse, string)
+}
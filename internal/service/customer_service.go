@@ -8,6 +8,10 @@ import (
 type CustomerService interface {
 	Create(ctx *gin.Context, request model.CreateCustomerRequest) (*model.CustomerResponse, string)
 	Update(ctx *gin.Context, customerID int, request model.UpdateCustomerRequest) (*model.CustomerResponse, string)
-	GetAll(ctx *gin.Context) (*model.GetAllCustomersResponse, string)
+	GetAll(ctx *gin.Context, search string) (*model.GetAllCustomersResponse, string)
+	GetDuplicates(ctx *gin.Context) (*model.GetCustomerDuplicatesResponse, string)
 	GetOne(ctx *gin.Context, id int) (*model.GetOneCustomerResponse, string)
+	GetSummary(ctx *gin.Context, id int) (*model.CustomerSummaryResponse, string)
+	Merge(ctx *gin.Context, targetCustomerID int, request model.MergeCustomerRequest) (*model.MergeCustomerResponse, string)
+	Delete(ctx *gin.Context, id int) string
 }
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pna/order-app-backend/internal/domain/model"
+)
+
+type DocumentService interface {
+	RenderInvoice(ctx context.Context, orderID int) (*model.ExportFile, string)
+	RenderDeliveryNote(ctx context.Context, orderID int) (*model.ExportFile, string)
+}
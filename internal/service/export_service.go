@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/pna/order-app-backend/internal/domain/model"
+)
+
+type ExportService interface {
+	ExportOrders(ctx context.Context, req model.GetAllOrdersRequest, format string) (*model.ExportFile, string)
+	ExportInventory(ctx context.Context, format string) (*model.ExportFile, string)
+	ExportReportByPeriod(ctx context.Context, groupBy string, fromDate time.Time, toDate time.Time, format string) (*model.ExportFile, string)
+	ExportReportByCustomer(ctx context.Context, fromDate time.Time, toDate time.Time, format string) (*model.ExportFile, string)
+	ExportReportByProduct(ctx context.Context, fromDate time.Time, toDate time.Time, format string) (*model.ExportFile, string)
+}
@@ -0,0 +1,131 @@
+package serviceimplement
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type AuditLogService struct {
+	auditLogRepository repository.AuditLogRepository
+}
+
+func NewAuditLogService(auditLogRepository repository.AuditLogRepository) service.AuditLogService {
+	return &AuditLogService{
+		auditLogRepository: auditLogRepository,
+	}
+}
+
+func (s *AuditLogService) GetAll(ctx *gin.Context, request model.GetAllAuditLogsRequest) (*model.GetAllAuditLogsResponse, string) {
+	limit := request.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	auditLogs, err := s.auditLogRepository.GetAllWithFiltersQuery(ctx, repository.AuditLogFilter{
+		UserID:     request.UserID,
+		Action:     request.Action,
+		EntityType: request.EntityType,
+		EntityID:   request.EntityID,
+		RequestID:  request.RequestID,
+		FromDate:   request.FromDate,
+		ToDate:     request.ToDate,
+		Limit:      limit,
+	}, nil)
+	if err != nil {
+		log.Error("AuditLogService.GetAll Error when get audit logs: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	auditLogResponses := make([]model.AuditLogResponse, len(auditLogs))
+	for i, auditLog := range auditLogs {
+		auditLogResponses[i] = model.AuditLogResponse{
+			ID:         auditLog.ID,
+			UserID:     auditLog.UserID,
+			Username:   auditLog.Username,
+			Action:     auditLog.Action,
+			EntityType: auditLog.EntityType,
+			EntityID:   auditLog.EntityID,
+			Before:     toRawJSON(auditLog.BeforeData),
+			After:      toRawJSON(auditLog.AfterData),
+			RequestID:  auditLog.RequestID,
+			CreatedAt:  auditLog.CreatedAt,
+		}
+	}
+
+	return &model.GetAllAuditLogsResponse{
+		AuditLogs: auditLogResponses,
+	}, ""
+}
+
+// Helper to record who changed what, written with the caller's transaction so the audit row
+// is committed or rolled back together with the change. before is nil for a create and after is nil for a delete.
+func recordAuditLog(ctx *gin.Context, auditLogRepository repository.AuditLogRepository, action string, entityType string, entityID int, before interface{}, after interface{}, tx *sqlx.Tx) string {
+	auditLog := &entity.AuditLog{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		CreatedAt:  time.Now(),
+	}
+	if userID := int(middleware.GetUserIdHelper(ctx)); userID != 0 {
+		auditLog.UserID = &userID
+	}
+	if requestID := middleware.GetRequestIdHelper(ctx); requestID != "" {
+		auditLog.RequestID = &requestID
+	}
+
+	var err error
+	if auditLog.BeforeData, err = toAuditSnapshot(before); err != nil {
+		log.Error("recordAuditLog Error when marshal before snapshot: " + err.Error())
+		return error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+	if auditLog.AfterData, err = toAuditSnapshot(after); err != nil {
+		log.Error("recordAuditLog Error when marshal after snapshot: " + err.Error())
+		return error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+
+	err = auditLogRepository.CreateCommand(ctx, auditLog, tx)
+	if err != nil {
+		log.Error("recordAuditLog Error when create audit log: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	return ""
+}
+
+// orderAuditSnapshot is the state of an order recorded in the audit log, items included
+// so that a price change on a single item is visible
+type orderAuditSnapshot struct {
+	Order      entity.Order
+	OrderItems []entity.OrderItem `json:",omitempty"`
+}
+
+func toAuditSnapshot(value interface{}) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := string(data)
+	return &snapshot, nil
+}
+
+func toRawJSON(value *string) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	return json.RawMessage(*value)
+}
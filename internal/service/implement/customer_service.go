@@ -1,24 +1,50 @@
 package serviceimplement
 
 import (
+	"context"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
 	"github.com/pna/order-app-backend/internal/domain/entity"
 	"github.com/pna/order-app-backend/internal/domain/model"
 	"github.com/pna/order-app-backend/internal/repository"
 	"github.com/pna/order-app-backend/internal/service"
 	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	stringutils "github.com/pna/order-app-backend/internal/utils/string_utils"
 	log "github.com/sirupsen/logrus"
 )
 
+// customerTopProductCount is how many of a customer's most bought products the summary lists
+const customerTopProductCount = 5
+
 type CustomerService struct {
-	customerRepository repository.CustomerRepository
-	unitOfWork         repository.UnitOfWork
+	customerRepository         repository.CustomerRepository
+	orderRepository            repository.OrderRepository
+	orderItemRepository        repository.OrderItemRepository
+	productPriceRepository     repository.ProductPriceRepository
+	customerDiscountRepository repository.CustomerDiscountRepository
+	auditLogRepository         repository.AuditLogRepository
+	unitOfWork                 repository.UnitOfWork
 }
 
-func NewCustomerService(customerRepository repository.CustomerRepository, unitOfWork repository.UnitOfWork) service.CustomerService {
+func NewCustomerService(
+	customerRepository repository.CustomerRepository,
+	orderRepository repository.OrderRepository,
+	orderItemRepository repository.OrderItemRepository,
+	productPriceRepository repository.ProductPriceRepository,
+	customerDiscountRepository repository.CustomerDiscountRepository,
+	auditLogRepository repository.AuditLogRepository,
+	unitOfWork repository.UnitOfWork,
+) service.CustomerService {
 	return &CustomerService{
-		customerRepository: customerRepository,
-		unitOfWork:         unitOfWork,
+		customerRepository:         customerRepository,
+		orderRepository:            orderRepository,
+		orderItemRepository:        orderItemRepository,
+		productPriceRepository:     productPriceRepository,
+		customerDiscountRepository: customerDiscountRepository,
+		auditLogRepository:         auditLogRepository,
+		unitOfWork:                 unitOfWork,
 	}
 }
 
@@ -39,9 +65,10 @@ func (s *CustomerService) Create(ctx *gin.Context, request model.CreateCustomerR
 
 	// Create customer entity
 	customer := &entity.Customer{
-		Name:    request.Name,
-		Phone:   request.Phone,
-		Address: request.Address,
+		Name:            request.Name,
+		Phone:           request.Phone,
+		NormalizedPhone: stringutils.NormalizePhone(request.Phone),
+		Address:         request.Address,
 	}
 
 	// Save customer to database
@@ -51,6 +78,10 @@ func (s *CustomerService) Create(ctx *gin.Context, request model.CreateCustomerR
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
+	if errCode := recordAuditLog(ctx, s.auditLogRepository, entity.AuditAction.CREATE, entity.AuditEntityType.CUSTOMER, customer.ID, nil, customer, tx); errCode != "" {
+		return nil, errCode
+	}
+
 	// Commit transaction
 	err = s.unitOfWork.Commit(tx)
 	if err != nil {
@@ -59,12 +90,8 @@ func (s *CustomerService) Create(ctx *gin.Context, request model.CreateCustomerR
 	}
 
 	// Return response
-	return &model.CustomerResponse{
-		ID:      customer.ID,
-		Name:    customer.Name,
-		Phone:   customer.Phone,
-		Address: customer.Address,
-	}, ""
+	response := toCustomerResponse(*customer)
+	return &response, ""
 }
 
 func (s *CustomerService) Update(ctx *gin.Context, customerID int, request model.UpdateCustomerRequest) (*model.CustomerResponse, string) {
@@ -78,6 +105,9 @@ func (s *CustomerService) Update(ctx *gin.Context, customerID int, request model
 	if existingCustomer == nil {
 		return nil, error_utils.ErrorCode.NOT_FOUND
 	}
+	if existingCustomer.DeletedAt != nil {
+		return nil, error_utils.ErrorCode.CUSTOMER_DELETED
+	}
 
 	// Update customer entity - only update non-empty fields
 	customer := &entity.Customer{
@@ -94,29 +124,51 @@ func (s *CustomerService) Update(ctx *gin.Context, customerID int, request model
 	if request.Phone != "" {
 		customer.Phone = request.Phone
 	}
+	customer.NormalizedPhone = stringutils.NormalizePhone(customer.Phone)
 	if request.Address != "" {
 		customer.Address = request.Address
 	}
 
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("CustomerService.Update Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("CustomerService.Update Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
 	// Save to database
-	err = s.customerRepository.UpdateCommand(ctx, customer, nil)
+	err = s.customerRepository.UpdateCommand(ctx, customer, tx)
 	if err != nil {
 		log.Error("CustomerService.Update Error when update customer: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
+	if errCode := recordAuditLog(ctx, s.auditLogRepository, entity.AuditAction.UPDATE, entity.AuditEntityType.CUSTOMER, customer.ID, existingCustomer, customer, tx); errCode != "" {
+		return nil, errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("CustomerService.Update Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
 	// Return response
-	return &model.CustomerResponse{
-		ID:      customer.ID,
-		Name:    customer.Name,
-		Phone:   customer.Phone,
-		Address: customer.Address,
-	}, ""
+	response := toCustomerResponse(*customer)
+	return &response, ""
 }
 
-func (s *CustomerService) GetAll(ctx *gin.Context) (*model.GetAllCustomersResponse, string) {
-	// Get all customers
-	customers, err := s.customerRepository.GetAllQuery(ctx, nil)
+func (s *CustomerService) GetAll(ctx *gin.Context, search string) (*model.GetAllCustomersResponse, string) {
+	// Get active customers matching the search
+	customers, err := s.customerRepository.GetAllWithSearchQuery(ctx, search, nil)
 	if err != nil {
 		log.Error("CustomerService.GetAll Error when get customers: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
@@ -125,12 +177,7 @@ func (s *CustomerService) GetAll(ctx *gin.Context) (*model.GetAllCustomersRespon
 	// Convert to response models
 	customerResponses := make([]model.CustomerResponse, len(customers))
 	for i, customer := range customers {
-		customerResponses[i] = model.CustomerResponse{
-			ID:      customer.ID,
-			Name:    customer.Name,
-			Phone:   customer.Phone,
-			Address: customer.Address,
-		}
+		customerResponses[i] = toCustomerResponse(customer)
 	}
 
 	return &model.GetAllCustomersResponse{
@@ -152,11 +199,274 @@ func (s *CustomerService) GetOne(ctx *gin.Context, id int) (*model.GetOneCustome
 
 	// Return response
 	return &model.GetOneCustomerResponse{
-		Customer: model.CustomerResponse{
-			ID:      customer.ID,
-			Name:    customer.Name,
-			Phone:   customer.Phone,
-			Address: customer.Address,
-		},
+		Customer: toCustomerResponse(*customer),
 	}, ""
 }
+
+func (s *CustomerService) GetSummary(ctx *gin.Context, id int) (*model.CustomerSummaryResponse, string) {
+	customer, err := s.customerRepository.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("CustomerService.GetSummary Error when get customer: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if customer == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	orders, err := s.orderRepository.GetAllWithFiltersQuery(ctx, repository.OrderFilter{CustomerID: id, SortBy: "order_date_desc"}, nil)
+	if err != nil {
+		log.Error("CustomerService.GetSummary Error when get orders: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	topProducts, err := s.orderItemRepository.GetTopProductsByCustomerIDQuery(ctx, id, customerTopProductCount, nil)
+	if err != nil {
+		log.Error("CustomerService.GetSummary Error when get top products: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	lifetimeProfit := 0
+	response := &model.CustomerSummaryResponse{
+		Customer:       toCustomerResponse(*customer),
+		LifetimeProfit: &lifetimeProfit,
+		TopProducts:    make([]model.CustomerProductPurchaseResponse, len(topProducts)),
+		Orders:         make([]model.OrderResponse, len(orders)),
+	}
+	for i, order := range orders {
+		response.Orders[i] = toOrderListResponse(order)
+
+		// Cancelled orders are listed but neither earn nor owe anything
+		if order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+			continue
+		}
+		profitLoss, _ := calculateOrderProfitLoss(&order.Order)
+		response.OrderCount++
+		response.LifetimeRevenue += order.TotalSalesRevenue - order.TotalReturnedRevenue
+		lifetimeProfit += profitLoss
+		response.UnpaidAmount += order.TotalAmount - order.PaidAmount
+		if response.LastOrderDate == nil || order.OrderDate.After(*response.LastOrderDate) {
+			orderDate := order.OrderDate
+			response.LastOrderDate = &orderDate
+		}
+	}
+	if response.OrderCount > 0 {
+		response.AverageOrderValue = response.LifetimeRevenue / response.OrderCount
+	}
+
+	for i, product := range topProducts {
+		response.TopProducts[i] = model.CustomerProductPurchaseResponse{
+			ProductID:   product.ProductID,
+			ProductName: product.ProductName,
+			Quantity:    product.Quantity,
+			OrderCount:  product.OrderCount,
+			Amount:      product.Amount,
+		}
+	}
+
+	return response, ""
+}
+
+func (s *CustomerService) GetDuplicates(ctx *gin.Context) (*model.GetCustomerDuplicatesResponse, string) {
+	phoneDuplicates, err := s.customerRepository.GetDuplicatesByPhoneQuery(ctx, nil)
+	if err != nil {
+		log.Error("CustomerService.GetDuplicates Error when get customers with the same phone: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	nameDuplicates, err := s.customerRepository.GetDuplicatesByNameQuery(ctx, nil)
+	if err != nil {
+		log.Error("CustomerService.GetDuplicates Error when get customers with the same name: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	groups := make([]model.CustomerDuplicateGroupResponse, 0)
+	groups = appendCustomerDuplicateGroups(groups, entity.CustomerDuplicateReason.PHONE, phoneDuplicates)
+	groups = appendCustomerDuplicateGroups(groups, entity.CustomerDuplicateReason.NAME, nameDuplicates)
+
+	return &model.GetCustomerDuplicatesResponse{
+		Groups: groups,
+	}, ""
+}
+
+// customerMergeAuditSnapshot is what a merged away customer turned into
+type customerMergeAuditSnapshot struct {
+	Customer        entity.Customer
+	MovedOrderCount int
+}
+
+func (s *CustomerService) Merge(ctx *gin.Context, targetCustomerID int, request model.MergeCustomerRequest) (*model.MergeCustomerResponse, string) {
+	if request.SourceCustomerID == targetCustomerID {
+		return nil, error_utils.ErrorCode.CUSTOMER_MERGE_INVALID
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("CustomerService.Merge Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("CustomerService.Merge Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock both customers in id order so two merges of the same pair cannot deadlock
+	lockedCustomers := make(map[int]*entity.Customer, 2)
+	firstID, secondID := request.SourceCustomerID, targetCustomerID
+	if firstID > secondID {
+		firstID, secondID = secondID, firstID
+	}
+	for _, id := range []int{firstID, secondID} {
+		customer, err := s.customerRepository.GetOneByIDForUpdateQuery(ctx, id, tx)
+		if err != nil {
+			log.Error("CustomerService.Merge Error when get customer: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if customer == nil {
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+		lockedCustomers[id] = customer
+	}
+	source, target := lockedCustomers[request.SourceCustomerID], lockedCustomers[targetCustomerID]
+	if source.DeletedAt != nil {
+		return nil, error_utils.ErrorCode.CUSTOMER_DELETED
+	}
+	if target.DeletedAt != nil {
+		return nil, error_utils.ErrorCode.CUSTOMER_MERGE_INVALID
+	}
+
+	movedOrderCount, err := s.orderRepository.ReassignCustomerCommand(ctx, source.ID, target.ID, tx)
+	if err != nil {
+		log.Error("CustomerService.Merge Error when move orders: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	err = s.productPriceRepository.ReassignCustomerCommand(ctx, source.ID, target.ID, tx)
+	if err != nil {
+		log.Error("CustomerService.Merge Error when move product prices: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	err = s.customerDiscountRepository.ReassignCustomerCommand(ctx, source.ID, target.ID, tx)
+	if err != nil {
+		log.Error("CustomerService.Merge Error when move customer discounts: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	err = s.customerRepository.SoftDeleteCommand(ctx, source.ID, &target.ID, tx)
+	if err != nil {
+		log.Error("CustomerService.Merge Error when delete customer: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	mergedSource := *source
+	deletedAt := time.Now()
+	mergedSource.DeletedAt = &deletedAt
+	mergedSource.MergedIntoID = &target.ID
+	after := customerMergeAuditSnapshot{Customer: mergedSource, MovedOrderCount: movedOrderCount}
+	if errCode := recordAuditLog(ctx, s.auditLogRepository, entity.AuditAction.DELETE, entity.AuditEntityType.CUSTOMER, source.ID, source, after, tx); errCode != "" {
+		return nil, errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("CustomerService.Merge Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return &model.MergeCustomerResponse{
+		Customer:        toCustomerResponse(*target),
+		MovedOrderCount: movedOrderCount,
+	}, ""
+}
+
+func (s *CustomerService) Delete(ctx *gin.Context, id int) string {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("CustomerService.Delete Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("CustomerService.Delete Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	customer, err := s.customerRepository.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("CustomerService.Delete Error when get customer: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if customer == nil || customer.DeletedAt != nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	// The row stays so existing orders keep their customer, it is only hidden from listings and new orders
+	err = s.customerRepository.SoftDeleteCommand(ctx, id, nil, tx)
+	if err != nil {
+		log.Error("CustomerService.Delete Error when delete customer: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	if errCode := recordAuditLog(ctx, s.auditLogRepository, entity.AuditAction.DELETE, entity.AuditEntityType.CUSTOMER, id, customer, nil, tx); errCode != "" {
+		return errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("CustomerService.Delete Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+// getActiveCustomer loads a customer that new orders and prices can still be attached to
+func getActiveCustomer(ctx context.Context, customerRepository repository.CustomerRepository, id int, tx *sqlx.Tx) (*entity.Customer, string) {
+	customer, err := customerRepository.GetOneByIDQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("getActiveCustomer Error when get customer: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if customer == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if customer.DeletedAt != nil {
+		return nil, error_utils.ErrorCode.CUSTOMER_DELETED
+	}
+	return customer, ""
+}
+
+// appendCustomerDuplicateGroups splits duplicates, sorted by the oldest customer of their group, into groups
+func appendCustomerDuplicateGroups(groups []model.CustomerDuplicateGroupResponse, reason string, duplicates []entity.CustomerDuplicate) []model.CustomerDuplicateGroupResponse {
+	for i, duplicate := range duplicates {
+		if i == 0 || duplicate.DuplicateOfID != duplicates[i-1].DuplicateOfID {
+			groups = append(groups, model.CustomerDuplicateGroupResponse{
+				Reason:    reason,
+				Customers: make([]model.CustomerResponse, 0, duplicate.DuplicateCount),
+			})
+		}
+		group := &groups[len(groups)-1]
+		group.Customers = append(group.Customers, toCustomerResponse(duplicate.Customer))
+	}
+	return groups
+}
+
+func toCustomerResponse(customer entity.Customer) model.CustomerResponse {
+	return model.CustomerResponse{
+		ID:           customer.ID,
+		Name:         customer.Name,
+		Phone:        customer.Phone,
+		Address:      customer.Address,
+		DeletedAt:    customer.DeletedAt,
+		MergedIntoID: customer.MergedIntoID,
+	}
+}
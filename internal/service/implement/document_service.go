@@ -0,0 +1,213 @@
+package serviceimplement
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	moneyutils "github.com/pna/order-app-backend/internal/utils/money_utils"
+	pdfutils "github.com/pna/order-app-backend/internal/utils/pdf_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type DocumentService struct {
+	orderService service.OrderService
+}
+
+func NewDocumentService(orderService service.OrderService) service.DocumentService {
+	return &DocumentService{
+		orderService: orderService,
+	}
+}
+
+func (s *DocumentService) RenderInvoice(ctx context.Context, orderID int) (*model.ExportFile, string) {
+	order, errCode := s.getPrintableOrder(ctx, orderID)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	pdf, err := pdfutils.NewDocument()
+	if err != nil {
+		log.Error("DocumentService.RenderInvoice Error when create document: " + err.Error())
+		return nil, error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+	writeDocumentHeader(pdf, "HOÁ ĐƠN BÁN HÀNG", order)
+
+	columns := []pdfutils.Column{
+		{Header: "STT", Width: 10, Align: "C"},
+		{Header: "Sản phẩm", Width: 52, Align: "L"},
+		{Header: "Số thùng × Quy cách", Width: 28, Align: "C"},
+		{Header: "Số lượng", Width: 18, Align: "R"},
+		{Header: "Đơn giá", Width: 24, Align: "R"},
+		{Header: "CK (%)", Width: 14, Align: "R"},
+		{Header: "Thành tiền", Width: 34, Align: "R"},
+	}
+	rows := make([][]string, 0, len(order.OrderItems))
+	itemsAmount := 0
+	for i, item := range order.OrderItems {
+		finalAmount := 0
+		if item.FinalAmount != nil {
+			finalAmount = *item.FinalAmount
+		}
+		itemsAmount += finalAmount
+
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			item.ProductName,
+			formatBoxesAndSpec(item),
+			moneyutils.FormatVND(item.Quantity),
+			moneyutils.FormatVND(item.SellingPrice),
+			strconv.Itoa(item.Discount),
+			moneyutils.FormatVND(finalAmount),
+		})
+	}
+	pdfutils.DrawTable(pdf, columns, rows)
+	pdf.Ln(3)
+
+	totalAmount := 0
+	if order.TotalAmount != nil {
+		totalAmount = *order.TotalAmount
+	}
+	taxPercent := 0
+	if order.TaxPercent != nil {
+		taxPercent = *order.TaxPercent
+	}
+	// Tax is whatever the order total adds on top of the net amount, so the printed lines always add up
+	netAmount := itemsAmount - order.TotalReturnedRevenue + order.AdditionalCost
+	taxAmount := totalAmount - netAmount
+
+	writeTotalLine(pdf, "Tiền hàng", itemsAmount, false)
+	if order.TotalReturnedRevenue > 0 {
+		writeTotalLine(pdf, "Hàng trả lại", -order.TotalReturnedRevenue, false)
+	}
+	if order.AdditionalCost != 0 {
+		label := "Chi phí khác"
+		if order.AdditionalCostNote != nil && *order.AdditionalCostNote != "" {
+			label += " (" + *order.AdditionalCostNote + ")"
+		}
+		writeTotalLine(pdf, label, order.AdditionalCost, false)
+	}
+	if taxPercent > 0 {
+		writeTotalLine(pdf, "Thuế ("+strconv.Itoa(taxPercent)+"%)", taxAmount, false)
+	}
+	writeTotalLine(pdf, "Tổng cộng", totalAmount, true)
+
+	pdf.Ln(2)
+	pdf.SetFont(pdfutils.FontFamily, "", 10)
+	pdf.MultiCell(0, 6, "Bằng chữ: "+moneyutils.AmountInWords(totalAmount), "", "L", false)
+
+	writeSignatures(pdf, "Người mua hàng", "Người bán hàng")
+
+	return renderDocument(pdf, "hoa-don-"+strconv.Itoa(order.ID))
+}
+
+func (s *DocumentService) RenderDeliveryNote(ctx context.Context, orderID int) (*model.ExportFile, string) {
+	order, errCode := s.getPrintableOrder(ctx, orderID)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	pdf, err := pdfutils.NewDocument()
+	if err != nil {
+		log.Error("DocumentService.RenderDeliveryNote Error when create document: " + err.Error())
+		return nil, error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+	writeDocumentHeader(pdf, "PHIẾU GIAO HÀNG", order)
+
+	// Same items as the invoice, without any prices
+	columns := []pdfutils.Column{
+		{Header: "STT", Width: 12, Align: "C"},
+		{Header: "Sản phẩm", Width: 88, Align: "L"},
+		{Header: "Số thùng × Quy cách", Width: 40, Align: "C"},
+		{Header: "Số lượng", Width: 40, Align: "R"},
+	}
+	rows := make([][]string, 0, len(order.OrderItems))
+	totalQuantity := 0
+	for i, item := range order.OrderItems {
+		totalQuantity += item.Quantity
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			item.ProductName,
+			formatBoxesAndSpec(item),
+			moneyutils.FormatVND(item.Quantity),
+		})
+	}
+	pdfutils.DrawTable(pdf, columns, rows)
+
+	pdf.SetFont(pdfutils.FontFamily, "B", 10)
+	pdf.CellFormat(140, 7, "Tổng số lượng", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(40, 7, moneyutils.FormatVND(totalQuantity), "1", 1, "R", false, 0, "")
+
+	writeSignatures(pdf, "Người nhận hàng", "Người giao hàng")
+
+	return renderDocument(pdf, "phieu-giao-hang-"+strconv.Itoa(order.ID))
+}
+
+// Helper to load an order through the order service, refusing orders that should not be handed to a customer
+func (s *DocumentService) getPrintableOrder(ctx context.Context, orderID int) (model.OrderResponse, string) {
+	resp, errCode := s.orderService.GetOne(ctx, orderID)
+	if errCode != "" {
+		return model.OrderResponse{}, errCode
+	}
+	if resp.Order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+		return model.OrderResponse{}, error_utils.ErrorCode.ORDER_CANCELLED
+	}
+	return resp.Order, ""
+}
+
+func writeDocumentHeader(pdf *fpdf.Fpdf, title string, order model.OrderResponse) {
+	pdf.SetFont(pdfutils.FontFamily, "B", 16)
+	pdf.CellFormat(0, 10, title, "", 1, "C", false, 0, "")
+	pdf.SetFont(pdfutils.FontFamily, "", 10)
+	pdf.CellFormat(0, 6, "Số: "+strconv.Itoa(order.ID)+" - Ngày: "+order.OrderDate.Format("02/01/2006"), "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	pdf.CellFormat(0, 6, "Khách hàng: "+order.Customer.Name, "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, "Điện thoại: "+order.Customer.Phone, "", 1, "L", false, 0, "")
+	pdf.MultiCell(0, 6, "Địa chỉ: "+order.Customer.Address, "", "L", false)
+	pdf.Ln(3)
+}
+
+func writeTotalLine(pdf *fpdf.Fpdf, label string, amount int, bold bool) {
+	style := ""
+	if bold {
+		style = "B"
+	}
+	pdf.SetFont(pdfutils.FontFamily, style, 10)
+	pdf.CellFormat(140, 6, label+":", "", 0, "R", false, 0, "")
+	pdf.CellFormat(40, 6, moneyutils.FormatVND(amount)+" ₫", "", 1, "R", false, 0, "")
+}
+
+func writeSignatures(pdf *fpdf.Fpdf, left string, right string) {
+	pdf.Ln(10)
+	pdf.SetFont(pdfutils.FontFamily, "B", 10)
+	pdf.CellFormat(90, 6, left, "", 0, "C", false, 0, "")
+	pdf.CellFormat(90, 6, right, "", 1, "C", false, 0, "")
+	pdf.SetFont(pdfutils.FontFamily, "", 9)
+	pdf.CellFormat(90, 5, "(Ký, ghi rõ họ tên)", "", 0, "C", false, 0, "")
+	pdf.CellFormat(90, 5, "(Ký, ghi rõ họ tên)", "", 1, "C", false, 0, "")
+}
+
+func formatBoxesAndSpec(item model.OrderItemResponse) string {
+	if item.NumberOfBoxes == nil || item.Spec == nil {
+		return ""
+	}
+	return strconv.Itoa(*item.NumberOfBoxes) + " × " + strconv.Itoa(*item.Spec)
+}
+
+func renderDocument(pdf *fpdf.Fpdf, baseName string) (*model.ExportFile, string) {
+	content, err := pdfutils.Render(pdf)
+	if err != nil {
+		log.Error("DocumentService.renderDocument Error when render pdf: " + err.Error())
+		return nil, error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+	return &model.ExportFile{
+		FileName:    baseName + ".pdf",
+		ContentType: pdfutils.ContentTypePDF,
+		Content:     content,
+	}, ""
+}
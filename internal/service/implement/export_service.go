@@ -0,0 +1,378 @@
+package serviceimplement
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	exportutils "github.com/pna/order-app-backend/internal/utils/export_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type ExportService struct {
+	orderRepo         repository.OrderRepository
+	orderItemRepo     repository.OrderItemRepository
+	productRepo       repository.ProductRepository
+	inventoryRepo     repository.InventoryRepository
+	statisticsService service.StatisticsService
+}
+
+func NewExportService(
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	productRepo repository.ProductRepository,
+	inventoryRepo repository.InventoryRepository,
+	statisticsService service.StatisticsService,
+) service.ExportService {
+	return &ExportService{
+		orderRepo:         orderRepo,
+		orderItemRepo:     orderItemRepo,
+		productRepo:       productRepo,
+		inventoryRepo:     inventoryRepo,
+		statisticsService: statisticsService,
+	}
+}
+
+var deliveryStatusLabels = map[string]string{
+	entity.OrderDeliveryStatus.PENDING:   "Chờ giao",
+	entity.OrderDeliveryStatus.DELIVERED: "Đã giao",
+	entity.OrderDeliveryStatus.UNPAID:    "Chưa thanh toán",
+	entity.OrderDeliveryStatus.COMPLETED: "Hoàn thành",
+	entity.OrderDeliveryStatus.CANCELLED: "Đã huỷ",
+}
+
+var debtStatusLabels = map[string]string{
+	entity.OrderDebtStatus.UNPAID:  "Chưa trả",
+	entity.OrderDebtStatus.PARTIAL: "Trả một phần",
+	entity.OrderDebtStatus.PAID:    "Đã trả",
+}
+
+var exportFromLabels = map[string]string{
+	entity.OrderExportFrom.INVENTORY: "Kho",
+	entity.OrderExportFrom.EXTERNAL:  "Bên ngoài",
+}
+
+func (s *ExportService) ExportOrders(ctx context.Context, req model.GetAllOrdersRequest, format string) (*model.ExportFile, string) {
+	// Same filters as the order listing, but every matching order at once
+	filter := repository.OrderFilter{
+		CustomerID:       req.CustomerID,
+		DeliveryStatuses: req.DeliveryStatuses,
+		DebtStatuses:     req.DebtStatuses,
+		Search:           req.Search,
+		MinAmount:        req.MinAmount,
+		MaxAmount:        req.MaxAmount,
+		FromDate:         req.FromDate,
+		ToDate:           req.ToDate,
+		SortBy:           req.SortBy,
+	}
+
+	orders, err := s.orderRepo.GetAllWithFiltersQuery(ctx, filter, nil)
+	if err != nil {
+		log.Error("ExportService.ExportOrders Error fetching orders: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	summary, err := s.orderRepo.GetSummaryWithFiltersQuery(ctx, filter, nil)
+	if err != nil {
+		log.Error("ExportService.ExportOrders Error fetching summary: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	ordersTable := exportutils.Table{
+		SheetName: "Đơn hàng",
+		Columns: []exportutils.Column{
+			{Header: "Mã đơn", Type: exportutils.ColumnNumber, Width: 10},
+			{Header: "Ngày đặt", Type: exportutils.ColumnDate, Width: 12},
+			{Header: "Khách hàng", Width: 28},
+			{Header: "Số điện thoại", Width: 16},
+			{Header: "Trạng thái giao hàng", Width: 18},
+			{Header: "Trạng thái công nợ", Width: 16},
+			{Header: "Số sản phẩm", Type: exportutils.ColumnNumber, Width: 12},
+			{Header: "Tiền hàng", Type: exportutils.ColumnMoney},
+			{Header: "Hàng trả", Type: exportutils.ColumnMoney},
+			{Header: "Chi phí phát sinh", Type: exportutils.ColumnMoney},
+			{Header: "Thuế (%)", Type: exportutils.ColumnNumber, Width: 10},
+			{Header: "Tổng tiền", Type: exportutils.ColumnMoney},
+			{Header: "Đã thanh toán", Type: exportutils.ColumnMoney},
+			{Header: "Còn nợ", Type: exportutils.ColumnMoney},
+			{Header: "Lãi/lỗ", Type: exportutils.ColumnMoney},
+		},
+	}
+	orderIDs := make([]int, 0, len(orders))
+	for _, o := range orders {
+		orderIDs = append(orderIDs, o.ID)
+		profitLoss, _ := calculateOrderProfitLoss(&o.Order)
+		debtStatus := ""
+		if o.DebtStatus != nil {
+			debtStatus = debtStatusLabels[*o.DebtStatus]
+		}
+		ordersTable.AddRow(
+			o.ID,
+			o.OrderDate,
+			o.CustomerName,
+			o.CustomerPhone,
+			deliveryStatusLabels[o.DeliveryStatus],
+			debtStatus,
+			o.ProductCount,
+			o.ItemsAmount,
+			o.TotalReturnedRevenue,
+			o.AdditionalCost,
+			o.TaxPercent,
+			o.TotalAmount,
+			o.PaidAmount,
+			o.TotalAmount-o.PaidAmount,
+			profitLoss,
+		)
+	}
+	// Totals match the order listing: cancelled orders are left out of the amounts
+	ordersTable.AddSummaryRow(
+		nil, nil, "Tổng cộng ("+strconv.Itoa(summary.TotalCount)+" đơn, không tính đơn huỷ)", nil, nil, nil, nil, nil, nil, nil, nil,
+		summary.TotalAmount, nil, nil, summary.TotalProfitLoss,
+	)
+
+	if format == exportutils.FormatCSV {
+		return writeExportFile("don-hang", format, ordersTable)
+	}
+
+	orderItems, err := s.orderItemRepo.GetAllByOrderIDsQuery(ctx, orderIDs, nil)
+	if err != nil {
+		log.Error("ExportService.ExportOrders Error fetching order items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	productNames, errCode := s.getProductNames(ctx)
+	if errCode != "" {
+		return nil, errCode
+	}
+	itemsByOrderID := make(map[int][]entity.OrderItem)
+	for _, item := range orderItems {
+		itemsByOrderID[item.OrderID] = append(itemsByOrderID[item.OrderID], item)
+	}
+
+	itemsTable := exportutils.Table{
+		SheetName: "Chi tiết hàng",
+		Columns: []exportutils.Column{
+			{Header: "Mã đơn", Type: exportutils.ColumnNumber, Width: 10},
+			{Header: "Ngày đặt", Type: exportutils.ColumnDate, Width: 12},
+			{Header: "Khách hàng", Width: 28},
+			{Header: "Sản phẩm", Width: 28},
+			{Header: "Số thùng", Type: exportutils.ColumnNumber, Width: 10},
+			{Header: "Quy cách", Type: exportutils.ColumnNumber, Width: 10},
+			{Header: "Số lượng", Type: exportutils.ColumnNumber, Width: 10},
+			{Header: "Giá bán", Type: exportutils.ColumnMoney},
+			{Header: "Chiết khấu (%)", Type: exportutils.ColumnNumber, Width: 12},
+			{Header: "Thành tiền", Type: exportutils.ColumnMoney},
+			{Header: "Nguồn xuất", Width: 12},
+		},
+	}
+	for _, o := range orders {
+		items := itemsByOrderID[o.ID]
+		if len(items) == 0 {
+			continue
+		}
+		for _, item := range items {
+			itemAmount, _ := calculateOrderAmountsAndProductCount([]entity.OrderItem{item})
+			itemsTable.AddRow(
+				o.ID,
+				o.OrderDate,
+				o.CustomerName,
+				productNames[item.ProductID],
+				intOrNil(item.NumberOfBoxes),
+				intOrNil(item.Spec),
+				item.Quantity,
+				item.SellingPrice,
+				item.Discount,
+				itemAmount,
+				exportFromLabels[item.ExportFrom],
+			)
+		}
+		itemsTable.AddSummaryRow(o.ID, nil, "Cộng đơn #"+strconv.Itoa(o.ID), nil, nil, nil, nil, nil, nil, o.ItemsAmount, nil)
+	}
+
+	return writeExportFile("don-hang", format, ordersTable, itemsTable)
+}
+
+func (s *ExportService) ExportInventory(ctx context.Context, format string) (*model.ExportFile, string) {
+	inventories, err := s.inventoryRepo.GetAllQuery(ctx, 0, nil)
+	if err != nil {
+		log.Error("ExportService.ExportInventory Error fetching inventories: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	products, err := s.productRepo.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("ExportService.ExportInventory Error fetching products: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	productMap := make(map[int]entity.Product)
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	table := exportutils.Table{
+		SheetName: "Tồn kho",
+		Columns: []exportutils.Column{
+			{Header: "Kho", Width: 20},
+			{Header: "Mã sản phẩm", Type: exportutils.ColumnNumber, Width: 12},
+			{Header: "Tên sản phẩm", Width: 32},
+			{Header: "Quy cách", Type: exportutils.ColumnNumber, Width: 10},
+			{Header: "Số lượng tồn", Type: exportutils.ColumnNumber, Width: 14},
+			{Header: "Giá vốn bình quân", Type: exportutils.ColumnMoney, Width: 18},
+			{Header: "Giá trị tồn", Type: exportutils.ColumnMoney, Width: 18},
+		},
+	}
+	totalQuantity := 0
+	totalValue := 0
+	for _, inventory := range inventories {
+		product := productMap[inventory.ProductID]
+		value := inventory.Quantity * product.AverageCost
+		table.AddRow(inventory.WarehouseName, inventory.ProductID, product.Name, product.Spec, inventory.Quantity, product.AverageCost, value)
+		totalQuantity += inventory.Quantity
+		totalValue += value
+	}
+	table.AddSummaryRow(nil, nil, "Tổng cộng", nil, totalQuantity, nil, totalValue)
+
+	return writeExportFile("ton-kho", format, table)
+}
+
+func (s *ExportService) ExportReportByPeriod(ctx context.Context, groupBy string, fromDate time.Time, toDate time.Time, format string) (*model.ExportFile, string) {
+	report, errCode := s.statisticsService.GetReportByPeriod(ctx, groupBy, fromDate, toDate)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	table := exportutils.Table{SheetName: "Doanh thu theo kỳ", Columns: append([]exportutils.Column{{Header: "Kỳ", Width: 14}}, reportFiguresColumns...)}
+	for _, row := range report.Rows {
+		table.AddRow(append([]interface{}{row.Period}, reportFiguresValues(row.ReportFigures)...)...)
+	}
+	table.AddSummaryRow(append([]interface{}{"Tổng cộng"}, reportFiguresValues(report.Total)...)...)
+
+	return writeExportFile("doanh-thu-theo-ky", format, table)
+}
+
+func (s *ExportService) ExportReportByCustomer(ctx context.Context, fromDate time.Time, toDate time.Time, format string) (*model.ExportFile, string) {
+	report, errCode := s.statisticsService.GetReportByCustomer(ctx, fromDate, toDate)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	table := exportutils.Table{
+		SheetName: "Doanh thu theo khách",
+		Columns: append([]exportutils.Column{
+			{Header: "Mã khách hàng", Type: exportutils.ColumnNumber, Width: 14},
+			{Header: "Khách hàng", Width: 28},
+		}, reportFiguresColumns...),
+	}
+	for _, row := range report.Rows {
+		table.AddRow(append([]interface{}{row.CustomerID, row.CustomerName}, reportFiguresValues(row.ReportFigures)...)...)
+	}
+	table.AddSummaryRow(append([]interface{}{nil, "Tổng cộng"}, reportFiguresValues(report.Total)...)...)
+
+	return writeExportFile("doanh-thu-theo-khach-hang", format, table)
+}
+
+func (s *ExportService) ExportReportByProduct(ctx context.Context, fromDate time.Time, toDate time.Time, format string) (*model.ExportFile, string) {
+	report, errCode := s.statisticsService.GetReportByProduct(ctx, fromDate, toDate)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	table := exportutils.Table{
+		SheetName: "Doanh thu theo sản phẩm",
+		Columns: []exportutils.Column{
+			{Header: "Mã sản phẩm", Type: exportutils.ColumnNumber, Width: 12},
+			{Header: "Sản phẩm", Width: 28},
+			{Header: "Số đơn", Type: exportutils.ColumnNumber, Width: 10},
+			{Header: "Số lượng bán", Type: exportutils.ColumnNumber, Width: 14},
+			{Header: "Số lượng trả", Type: exportutils.ColumnNumber, Width: 14},
+			{Header: "Doanh thu", Type: exportutils.ColumnMoney},
+			{Header: "Giá vốn", Type: exportutils.ColumnMoney},
+			{Header: "Lãi/lỗ", Type: exportutils.ColumnMoney},
+			{Header: "Lãi/lỗ (%)", Type: exportutils.ColumnPercent, Width: 12},
+		},
+	}
+	totalRevenue := 0
+	totalOriginalCost := 0
+	totalProfitLoss := 0
+	for _, row := range report.Rows {
+		table.AddRow(row.ProductID, row.ProductName, row.OrderCount, row.QuantitySold, row.QuantityReturned, row.Revenue, row.OriginalCost, row.ProfitLoss, row.ProfitLossPercentage)
+		totalRevenue += row.Revenue
+		totalOriginalCost += row.OriginalCost
+		totalProfitLoss += row.ProfitLoss
+	}
+	table.AddSummaryRow(nil, "Tổng cộng", nil, nil, nil, totalRevenue, totalOriginalCost, totalProfitLoss, calculateProfitLossPercentage(totalProfitLoss, totalOriginalCost))
+
+	return writeExportFile("doanh-thu-theo-san-pham", format, table)
+}
+
+var reportFiguresColumns = []exportutils.Column{
+	{Header: "Số đơn", Type: exportutils.ColumnNumber, Width: 10},
+	{Header: "Doanh thu", Type: exportutils.ColumnMoney},
+	{Header: "Giá vốn", Type: exportutils.ColumnMoney},
+	{Header: "Chi phí phát sinh", Type: exportutils.ColumnMoney},
+	{Header: "Thuế", Type: exportutils.ColumnMoney},
+	{Header: "Lãi/lỗ", Type: exportutils.ColumnMoney},
+	{Header: "Lãi/lỗ (%)", Type: exportutils.ColumnPercent, Width: 12},
+}
+
+func reportFiguresValues(figures model.ReportFigures) []interface{} {
+	return []interface{}{
+		figures.OrderCount,
+		figures.Revenue,
+		figures.OriginalCost,
+		figures.AdditionalCost,
+		figures.Tax,
+		figures.ProfitLoss,
+		figures.ProfitLossPercentage,
+	}
+}
+
+// Helper to get the name of every product keyed by product ID
+func (s *ExportService) getProductNames(ctx context.Context) (map[int]string, string) {
+	products, err := s.productRepo.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("ExportService.getProductNames Error fetching products: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	productNames := make(map[int]string, len(products))
+	for _, product := range products {
+		productNames[product.ID] = product.Name
+	}
+	return productNames, ""
+}
+
+// Helper to render tables into an xlsx workbook, or the first table into a CSV file
+func writeExportFile(baseName string, format string, tables ...exportutils.Table) (*model.ExportFile, string) {
+	var buffer bytes.Buffer
+	file := &model.ExportFile{FileName: baseName + "-" + time.Now().Format("20060102") + "." + format}
+
+	switch format {
+	case exportutils.FormatXLSX:
+		file.ContentType = exportutils.ContentTypeXLSX
+		if err := exportutils.WriteXLSX(&buffer, tables...); err != nil {
+			log.Error("ExportService.writeExportFile Error when write xlsx: " + err.Error())
+			return nil, error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+		}
+	case exportutils.FormatCSV:
+		file.ContentType = exportutils.ContentTypeCSV
+		if err := exportutils.WriteCSV(&buffer, tables[0]); err != nil {
+			log.Error("ExportService.writeExportFile Error when write csv: " + err.Error())
+			return nil, error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+		}
+	default:
+		return nil, error_utils.ErrorCode.BAD_REQUEST
+	}
+
+	file.Content = buffer.Bytes()
+	return file, ""
+}
+
+func intOrNil(value *int) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -0,0 +1,502 @@
+package serviceimplement
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	exportutils "github.com/pna/order-app-backend/internal/utils/export_utils"
+	stringutils "github.com/pna/order-app-backend/internal/utils/string_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	importMaxNameLength    = 255
+	importMaxPhoneLength   = 70
+	importMaxAddressLength = 255
+	openingStockNote       = "Tồn kho đầu kỳ (nhập từ file)"
+)
+
+// Accepted header titles for each imported field; the Vietnamese titles match the export files
+var productImportColumns = map[string][]string{
+	"name":             {"name", "Tên sản phẩm"},
+	"spec":             {"spec", "Quy cách"},
+	"original_price":   {"original_price", "Giá gốc"},
+	"opening_quantity": {"opening_quantity", "quantity", "Tồn đầu kỳ", "Số lượng tồn"},
+}
+
+var customerImportColumns = map[string][]string{
+	"name":    {"name", "Tên khách hàng"},
+	"phone":   {"phone", "Số điện thoại"},
+	"address": {"address", "Địa chỉ"},
+}
+
+type ImportService struct {
+	productRepo            repository.ProductRepository
+	inventoryRepo          repository.InventoryRepository
+	inventoryHistoryRepo   repository.InventoryHistoryRepository
+	productCostHistoryRepo repository.ProductCostHistoryRepository
+	customerRepo           repository.CustomerRepository
+	userRepo               repository.UserRepository
+	warehouseRepo          repository.WarehouseRepository
+	auditLogRepo           repository.AuditLogRepository
+	unitOfWork             repository.UnitOfWork
+}
+
+func NewImportService(
+	productRepo repository.ProductRepository,
+	inventoryRepo repository.InventoryRepository,
+	inventoryHistoryRepo repository.InventoryHistoryRepository,
+	productCostHistoryRepo repository.ProductCostHistoryRepository,
+	customerRepo repository.CustomerRepository,
+	userRepo repository.UserRepository,
+	warehouseRepo repository.WarehouseRepository,
+	auditLogRepo repository.AuditLogRepository,
+	unitOfWork repository.UnitOfWork,
+) service.ImportService {
+	return &ImportService{
+		productRepo:            productRepo,
+		inventoryRepo:          inventoryRepo,
+		inventoryHistoryRepo:   inventoryHistoryRepo,
+		productCostHistoryRepo: productCostHistoryRepo,
+		customerRepo:           customerRepo,
+		userRepo:               userRepo,
+		warehouseRepo:          warehouseRepo,
+		auditLogRepo:           auditLogRepo,
+		unitOfWork:             unitOfWork,
+	}
+}
+
+type importProductRow struct {
+	product         entity.Product
+	openingQuantity int
+}
+
+func (s *ImportService) ImportProducts(ctx *gin.Context, file io.Reader, format string, dryRun bool) (*model.ImportProductsResponse, string) {
+	rows, columns, errCode := readImportFile(file, format, productImportColumns, "name", "original_price")
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	existingProducts, err := s.productRepo.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("ImportService.ImportProducts Error when get products: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	existingNames := make(map[string]bool)
+	for _, product := range existingProducts {
+		existingNames[normalizeImportName(product.Name)] = true
+	}
+
+	resp := &model.ImportProductsResponse{
+		DryRun:   dryRun,
+		Errors:   make([]model.ImportRowError, 0),
+		Products: make([]model.ProductResponse, 0),
+	}
+	importRows := make([]importProductRow, 0, len(rows))
+	seenNames := make(map[string]int)
+
+	for i, row := range rows {
+		rowNumber := i + 2 // header is row 1
+		if isEmptyImportRow(row) {
+			continue
+		}
+		resp.TotalRows++
+		rowErrors := make([]model.ImportRowError, 0)
+
+		name := importCell(row, columns, "name")
+		normalizedName := normalizeImportName(name)
+		switch {
+		case name == "":
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "name", Message: "Tên sản phẩm không được để trống"})
+		case utf8.RuneCountInString(name) > importMaxNameLength:
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "name", Message: "Tên sản phẩm quá dài"})
+		case existingNames[normalizedName]:
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "name", Message: "Sản phẩm đã tồn tại"})
+		case seenNames[normalizedName] > 0:
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "name", Message: "Trùng tên với dòng " + strconv.Itoa(seenNames[normalizedName])})
+		default:
+			seenNames[normalizedName] = rowNumber
+		}
+
+		spec, ok := parseImportInt(importCell(row, columns, "spec"))
+		if !ok || spec < 0 {
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "spec", Message: "Quy cách phải là số nguyên không âm"})
+		}
+
+		originalPrice, ok := parseImportInt(importCell(row, columns, "original_price"))
+		if !ok || importCell(row, columns, "original_price") == "" {
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "original_price", Message: "Giá gốc phải là số nguyên"})
+		} else if originalPrice < 0 {
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "original_price", Message: "Giá gốc không được âm"})
+		}
+
+		openingQuantity, ok := parseImportInt(importCell(row, columns, "opening_quantity"))
+		if !ok || openingQuantity < 0 {
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "opening_quantity", Message: "Tồn đầu kỳ phải là số nguyên không âm"})
+		}
+
+		if len(rowErrors) > 0 {
+			resp.Errors = append(resp.Errors, rowErrors...)
+			continue
+		}
+		importRows = append(importRows, importProductRow{
+			product: entity.Product{
+				Name:          name,
+				Spec:          spec,
+				OriginalPrice: originalPrice,
+				AverageCost:   originalPrice,
+				MinStock:      entity.DefaultProductMinStock,
+			},
+			openingQuantity: openingQuantity,
+		})
+	}
+
+	// A dry run previews the valid rows alongside the errors; a real import is all or nothing
+	if dryRun {
+		for _, row := range importRows {
+			resp.Products = append(resp.Products, model.ProductResponse{
+				Name:          row.product.Name,
+				Spec:          row.product.Spec,
+				OriginalPrice: &row.product.OriginalPrice,
+				AverageCost:   &row.product.AverageCost,
+				Inventory:     &model.InventoryInfo{Quantity: row.openingQuantity},
+			})
+		}
+		return resp, ""
+	}
+	if len(resp.Errors) > 0 {
+		return resp, error_utils.ErrorCode.IMPORT_ROWS_INVALID
+	}
+
+	username, errCode := s.getUsername(ctx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("ImportService.ImportProducts Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("ImportService.ImportProducts Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Products get an inventory in every warehouse, the opening stock goes into the default one
+	defaultWarehouseID, errCode := resolveWarehouseID(ctx, s.warehouseRepo, nil, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+	warehouses, err := s.warehouseRepo.GetAllQuery(ctx, tx)
+	if err != nil {
+		log.Error("ImportService.ImportProducts Error when get warehouses: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	importedAt := time.Now()
+	for _, row := range importRows {
+		product := row.product
+		err = s.productRepo.CreateCommand(ctx, &product, tx)
+		if err != nil {
+			log.Error("ImportService.ImportProducts Error when create product: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+
+		inventories := make([]entity.Inventory, 0, len(warehouses))
+		for _, warehouse := range warehouses {
+			inventory := entity.Inventory{
+				WarehouseID:   warehouse.ID,
+				WarehouseName: warehouse.Name,
+				ProductID:     product.ID,
+				Version:       uuid.New().String(),
+			}
+			if warehouse.ID == defaultWarehouseID {
+				inventory.Quantity = row.openingQuantity
+			}
+			err = s.inventoryRepo.CreateCommand(ctx, &inventory, tx)
+			if err != nil {
+				log.Error("ImportService.ImportProducts Error when create inventory: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+			inventories = append(inventories, inventory)
+		}
+
+		if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.CREATE, entity.AuditEntityType.PRODUCT, product.ID, nil, product, tx); errCode != "" {
+			return nil, errCode
+		}
+
+		if row.openingQuantity > 0 {
+			inventoryHistory := &entity.InventoryHistory{
+				ProductID:     product.ID,
+				WarehouseID:   defaultWarehouseID,
+				Quantity:      row.openingQuantity,
+				FinalQuantity: row.openingQuantity,
+				MovementType:  entity.InventoryMovementType.ADJUSTMENT,
+				ImporterName:  username,
+				ImportedAt:    importedAt,
+				Note:          openingStockNote,
+			}
+			err = s.inventoryHistoryRepo.CreateCommand(ctx, inventoryHistory, tx)
+			if err != nil {
+				log.Error("ImportService.ImportProducts Error when create inventory history: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+
+			// Opening stock is valued at the imported original price
+			errCode = applyReceivedCost(ctx, s.productRepo, s.inventoryRepo, s.productCostHistoryRepo, product.ID, row.openingQuantity, product.OriginalPrice, entity.ProductCostSource.OPENING_STOCK, nil, tx)
+			if errCode != "" {
+				return nil, errCode
+			}
+		}
+
+		resp.Products = append(resp.Products, toProductResponse(product, inventories, defaultWarehouseID))
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("ImportService.ImportProducts Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return resp, ""
+}
+
+func (s *ImportService) ImportCustomers(ctx *gin.Context, file io.Reader, format string, dryRun bool) (*model.ImportCustomersResponse, string) {
+	rows, columns, errCode := readImportFile(file, format, customerImportColumns, "name", "phone", "address")
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	existingCustomers, err := s.customerRepo.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("ImportService.ImportCustomers Error when get customers: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	existingPhones := make(map[string]bool)
+	for _, customer := range existingCustomers {
+		if customer.NormalizedPhone != "" {
+			existingPhones[customer.NormalizedPhone] = true
+		}
+	}
+
+	resp := &model.ImportCustomersResponse{
+		DryRun:    dryRun,
+		Errors:    make([]model.ImportRowError, 0),
+		Customers: make([]model.CustomerResponse, 0),
+	}
+	customers := make([]entity.Customer, 0, len(rows))
+	seenPhones := make(map[string]int)
+
+	for i, row := range rows {
+		rowNumber := i + 2 // header is row 1
+		if isEmptyImportRow(row) {
+			continue
+		}
+		resp.TotalRows++
+		rowErrors := make([]model.ImportRowError, 0)
+
+		name := importCell(row, columns, "name")
+		if name == "" {
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "name", Message: "Tên khách hàng không được để trống"})
+		} else if utf8.RuneCountInString(name) > importMaxNameLength {
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "name", Message: "Tên khách hàng quá dài"})
+		}
+
+		phone := importCell(row, columns, "phone")
+		normalizedPhone := stringutils.NormalizePhone(phone)
+		switch {
+		case normalizedPhone == "":
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "phone", Message: "Số điện thoại không hợp lệ"})
+		case utf8.RuneCountInString(phone) > importMaxPhoneLength:
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "phone", Message: "Số điện thoại quá dài"})
+		case existingPhones[normalizedPhone]:
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "phone", Message: "Số điện thoại đã thuộc về khách hàng khác"})
+		case seenPhones[normalizedPhone] > 0:
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "phone", Message: "Trùng số điện thoại với dòng " + strconv.Itoa(seenPhones[normalizedPhone])})
+		default:
+			seenPhones[normalizedPhone] = rowNumber
+		}
+
+		address := importCell(row, columns, "address")
+		if address == "" {
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "address", Message: "Địa chỉ không được để trống"})
+		} else if utf8.RuneCountInString(address) > importMaxAddressLength {
+			rowErrors = append(rowErrors, model.ImportRowError{Row: rowNumber, Column: "address", Message: "Địa chỉ quá dài"})
+		}
+
+		if len(rowErrors) > 0 {
+			resp.Errors = append(resp.Errors, rowErrors...)
+			continue
+		}
+		customers = append(customers, entity.Customer{
+			Name:            name,
+			Phone:           phone,
+			NormalizedPhone: normalizedPhone,
+			Address:         address,
+		})
+	}
+
+	// A dry run previews the valid rows alongside the errors; a real import is all or nothing
+	if dryRun {
+		for _, customer := range customers {
+			resp.Customers = append(resp.Customers, model.CustomerResponse{
+				Name:    customer.Name,
+				Phone:   customer.Phone,
+				Address: customer.Address,
+			})
+		}
+		return resp, ""
+	}
+	if len(resp.Errors) > 0 {
+		return resp, error_utils.ErrorCode.IMPORT_ROWS_INVALID
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("ImportService.ImportCustomers Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("ImportService.ImportCustomers Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	for _, customer := range customers {
+		err = s.customerRepo.CreateCommand(ctx, &customer, tx)
+		if err != nil {
+			log.Error("ImportService.ImportCustomers Error when create customer: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.CREATE, entity.AuditEntityType.CUSTOMER, customer.ID, nil, customer, tx); errCode != "" {
+			return nil, errCode
+		}
+		resp.Customers = append(resp.Customers, model.CustomerResponse{
+			ID:      customer.ID,
+			Name:    customer.Name,
+			Phone:   customer.Phone,
+			Address: customer.Address,
+		})
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("ImportService.ImportCustomers Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return resp, ""
+}
+
+func (s *ImportService) getUsername(ctx *gin.Context) (string, string) {
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("ImportService.getUsername Error: user ID not found in context")
+		return "", error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	user, err := s.userRepo.FindByIDQuery(ctx, int(userID), nil)
+	if err != nil {
+		log.Error("ImportService.getUsername Error when get user: " + err.Error())
+		return "", error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		log.Error("ImportService.getUsername Error: user not found")
+		return "", error_utils.ErrorCode.UNAUTHORIZED
+	}
+	return user.Username, ""
+}
+
+// Helper to read the data rows of an import file and locate its columns; every required field needs a header
+func readImportFile(file io.Reader, format string, aliases map[string][]string, requiredFields ...string) ([][]string, map[string]int, string) {
+	rows, err := exportutils.ReadRows(file, format)
+	if err != nil {
+		log.Error("ImportService.readImportFile Error when read file: " + err.Error())
+		return nil, nil, error_utils.ErrorCode.IMPORT_FILE_INVALID
+	}
+	if len(rows) == 0 {
+		return nil, nil, error_utils.ErrorCode.IMPORT_FILE_INVALID
+	}
+
+	columns := exportutils.ColumnIndexes(rows[0], aliases)
+	for _, field := range requiredFields {
+		if _, ok := columns[field]; !ok {
+			return nil, nil, error_utils.ErrorCode.IMPORT_FILE_INVALID
+		}
+	}
+	return rows[1:], columns, ""
+}
+
+func importCell(row []string, columns map[string]int, field string) string {
+	index, ok := columns[field]
+	if !ok || index >= len(row) {
+		return ""
+	}
+	return row[index]
+}
+
+func isEmptyImportRow(row []string) bool {
+	for _, cell := range row {
+		if cell != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Helper to parse a whole number typed with thousand separators or a currency sign, e.g. "1.250.000 ₫".
+// A dot or comma only counts as a thousand separator when every group after it has exactly three digits,
+// so decimals such as "12.5" or "1,5" are rejected instead of silently losing their separator.
+// An empty cell parses as 0.
+func parseImportInt(value string) (int, bool) {
+	cleaned := strings.NewReplacer(" ", "", "₫", "", "đ", "").Replace(value)
+	if cleaned == "" {
+		return 0, true
+	}
+
+	digits := strings.TrimLeft(cleaned, "+-")
+	sign := cleaned[:len(cleaned)-len(digits)]
+	for _, separator := range []string{".", ","} {
+		if !strings.Contains(digits, separator) {
+			continue
+		}
+		groups := strings.Split(digits, separator)
+		if len(groups[0]) == 0 || len(groups[0]) > 3 {
+			return 0, false
+		}
+		for _, group := range groups[1:] {
+			if len(group) != 3 {
+				return 0, false
+			}
+		}
+		digits = strings.Join(groups, "")
+	}
+
+	number, err := strconv.Atoi(sign + digits)
+	return number, err == nil
+}
+
+func normalizeImportName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,37 @@
+package serviceimplement
+
+import "testing"
+
+func TestParseImportInt(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   int
+		wantOk bool
+	}{
+		{"", 0, true},
+		{"0", 0, true},
+		{"125", 125, true},
+		{"-40", -40, true},
+		{"1.250.000", 1250000, true},
+		{"1,250,000", 1250000, true},
+		{"1.250.000 ₫", 1250000, true},
+		{"250.000đ", 250000, true},
+		{"12 500", 12500, true},
+		{"12.5", 0, false},
+		{"1,5", 0, false},
+		{"12.50", 0, false},
+		{"1.2345", 0, false},
+		{"1234.567", 0, false},
+		{".500", 0, false},
+		{"1.250,000", 0, false},
+		{"1.250.000,50", 0, false},
+		{"abc", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseImportInt(tt.value)
+		if ok != tt.wantOk || (ok && got != tt.want) {
+			t.Errorf("parseImportInt(%q) = %d, %v; want %d, %v", tt.value, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
@@ -14,17 +14,28 @@ import (
 
 type InventoryHistoryService struct {
 	inventoryHistoryRepository repository.InventoryHistoryRepository
+	warehouseRepository        repository.WarehouseRepository
+	inventoryRepository        repository.InventoryRepository
+	productRepository          repository.ProductRepository
 }
 
-func NewInventoryHistoryService(inventoryHistoryRepository repository.InventoryHistoryRepository) service.InventoryHistoryService {
+func NewInventoryHistoryService(
+	inventoryHistoryRepository repository.InventoryHistoryRepository,
+	warehouseRepository repository.WarehouseRepository,
+	inventoryRepository repository.InventoryRepository,
+	productRepository repository.ProductRepository,
+) service.InventoryHistoryService {
 	return &InventoryHistoryService{
 		inventoryHistoryRepository: inventoryHistoryRepository,
+		warehouseRepository:        warehouseRepository,
+		inventoryRepository:        inventoryRepository,
+		productRepository:          productRepository,
 	}
 }
 
-func (s *InventoryHistoryService) GetAll(ctx *gin.Context, productID int) (*model.GetAllInventoryHistoriesResponse, string) {
-	// Get all inventory histories
-	inventoryHistories, err := s.inventoryHistoryRepository.GetAllByProductIDQuery(ctx, productID, nil)
+func (s *InventoryHistoryService) GetAll(ctx *gin.Context, productID int, warehouseID int) (*model.GetAllInventoryHistoriesResponse, string) {
+	// Get all inventory histories, of every warehouse when no warehouse is given
+	inventoryHistories, err := s.inventoryHistoryRepository.GetAllByProductIDQuery(ctx, productID, warehouseID, nil)
 	if err != nil {
 		log.Error("InventoryHistoryService.GetAll Error when get inventory histories: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
@@ -36,8 +47,11 @@ func (s *InventoryHistoryService) GetAll(ctx *gin.Context, productID int) (*mode
 		inventoryHistoryResponses[i] = model.InventoryHistoryResponse{
 			ID:            inventoryHistory.ID,
 			ProductID:     inventoryHistory.ProductID,
+			WarehouseID:   inventoryHistory.WarehouseID,
+			WarehouseName: inventoryHistory.WarehouseName,
 			Quantity:      inventoryHistory.Quantity,
 			FinalQuantity: inventoryHistory.FinalQuantity,
+			MovementType:  inventoryHistory.MovementType,
 			ImporterName:  inventoryHistory.ImporterName,
 			ImportedAt:    inventoryHistory.ImportedAt,
 			Note:          inventoryHistory.Note,
@@ -50,11 +64,109 @@ func (s *InventoryHistoryService) GetAll(ctx *gin.Context, productID int) (*mode
 	}, ""
 }
 
+// GetMovements summarises the stock movements of each product from fromDate to toDate, both days included
+func (s *InventoryHistoryService) GetMovements(ctx *gin.Context, fromDate time.Time, toDate time.Time, productID int, warehouseID int) (*model.GetInventoryMovementsResponse, string) {
+	products, err := s.productRepository.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("InventoryHistoryService.GetMovements Error when get products: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	inventories, err := s.inventoryRepository.GetAllQuery(ctx, warehouseID, nil)
+	if err != nil {
+		log.Error("InventoryHistoryService.GetMovements Error when get inventories: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	stocks := sumInventoriesByProduct(inventories)
+
+	openingBalances, err := s.inventoryHistoryRepository.GetBalancesBeforeQuery(ctx, fromDate, productID, warehouseID, nil)
+	if err != nil {
+		log.Error("InventoryHistoryService.GetMovements Error when get opening balances: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	summaries, err := s.inventoryHistoryRepository.GetMovementSummariesQuery(ctx, fromDate, toDate.AddDate(0, 0, 1), productID, warehouseID, nil)
+	if err != nil {
+		log.Error("InventoryHistoryService.GetMovements Error when get movement summaries: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	inconsistentProductIDs, err := s.inventoryHistoryRepository.GetInconsistentProductIDsQuery(ctx, productID, warehouseID, nil)
+	if err != nil {
+		log.Error("InventoryHistoryService.GetMovements Error when check history consistency: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	inconsistent := make(map[int]bool, len(inconsistentProductIDs))
+	for _, id := range inconsistentProductIDs {
+		inconsistent[id] = true
+	}
+
+	movements := make(map[int]*model.InventoryMovementResponse)
+	movementResponses := make([]model.InventoryMovementResponse, 0, len(products))
+	for _, product := range products {
+		if productID != 0 && product.ID != productID {
+			continue
+		}
+		movementResponses = append(movementResponses, model.InventoryMovementResponse{
+			ProductID:       product.ID,
+			ProductName:     product.Name,
+			OpeningBalance:  openingBalances[product.ID],
+			CurrentQuantity: stocks[product.ID].Quantity,
+			IsConsistent:    !inconsistent[product.ID],
+		})
+	}
+	for i := range movementResponses {
+		movements[movementResponses[i].ProductID] = &movementResponses[i]
+	}
+
+	for _, summary := range summaries {
+		movement, ok := movements[summary.ProductID]
+		if !ok {
+			continue
+		}
+
+		var totals *model.InventoryMovementTotals
+		switch summary.MovementType {
+		case entity.InventoryMovementType.SALE:
+			totals = &movement.Sales
+		case entity.InventoryMovementType.RETURN:
+			totals = &movement.Returns
+		case entity.InventoryMovementType.PURCHASE:
+			totals = &movement.Purchases
+		case entity.InventoryMovementType.TRANSFER:
+			totals = &movement.Transfers
+		default:
+			totals = &movement.Adjustments
+		}
+		totals.In += summary.QuantityIn
+		totals.Out += summary.QuantityOut
+		movement.TotalIn += summary.QuantityIn
+		movement.TotalOut += summary.QuantityOut
+	}
+
+	for i := range movementResponses {
+		movementResponses[i].ClosingBalance = movementResponses[i].OpeningBalance + movementResponses[i].TotalIn - movementResponses[i].TotalOut
+	}
+
+	return &model.GetInventoryMovementsResponse{
+		FromDate: fromDate,
+		ToDate:   toDate,
+		Products: movementResponses,
+	}, ""
+}
+
 func (s *InventoryHistoryService) Create(ctx *gin.Context, request model.CreateInventoryHistoryRequest) (*model.InventoryHistoryResponse, string) {
+	warehouseID, errCode := resolveWarehouseID(ctx, s.warehouseRepository, request.WarehouseID, nil)
+	if errCode != "" {
+		return nil, errCode
+	}
+
 	// Create inventory history entity
 	inventoryHistory := &entity.InventoryHistory{
 		ProductID:    request.ProductID,
+		WarehouseID:  warehouseID,
 		Quantity:     request.Quantity,
+		MovementType: entity.InventoryMovementType.ADJUSTMENT,
 		ImporterName: request.ImporterName,
 		ImportedAt:   time.Now(),
 		Note:         request.Note,
@@ -71,8 +183,10 @@ func (s *InventoryHistoryService) Create(ctx *gin.Context, request model.CreateI
 	return &model.InventoryHistoryResponse{
 		ID:            inventoryHistory.ID,
 		ProductID:     inventoryHistory.ProductID,
+		WarehouseID:   inventoryHistory.WarehouseID,
 		Quantity:      inventoryHistory.Quantity,
 		FinalQuantity: inventoryHistory.FinalQuantity,
+		MovementType:  inventoryHistory.MovementType,
 		ImporterName:  inventoryHistory.ImporterName,
 		ImportedAt:    inventoryHistory.ImportedAt,
 		Note:          inventoryHistory.Note,
@@ -3,10 +3,12 @@ package serviceimplement
 import (
 	"context"
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/pna/order-app-backend/internal/controller/http/middleware"
 	"github.com/pna/order-app-backend/internal/domain/entity"
 	"github.com/pna/order-app-backend/internal/domain/model"
@@ -17,11 +19,15 @@ import (
 )
 
 type InventoryService struct {
-	inventoryRepository        repository.InventoryRepository
-	inventoryHistoryRepository repository.InventoryHistoryRepository
-	userRepository             repository.UserRepository
-	productRepository          repository.ProductRepository
-	unitOfWork                 repository.UnitOfWork
+	inventoryRepository          repository.InventoryRepository
+	inventoryHistoryRepository   repository.InventoryHistoryRepository
+	userRepository               repository.UserRepository
+	productRepository            repository.ProductRepository
+	orderItemRepository          repository.OrderItemRepository
+	productCostHistoryRepository repository.ProductCostHistoryRepository
+	warehouseRepository          repository.WarehouseRepository
+	auditLogRepository           repository.AuditLogRepository
+	unitOfWork                   repository.UnitOfWork
 }
 
 func NewInventoryService(
@@ -29,20 +35,28 @@ func NewInventoryService(
 	inventoryHistoryRepository repository.InventoryHistoryRepository,
 	userRepository repository.UserRepository,
 	productRepository repository.ProductRepository,
+	orderItemRepository repository.OrderItemRepository,
+	productCostHistoryRepository repository.ProductCostHistoryRepository,
+	warehouseRepository repository.WarehouseRepository,
+	auditLogRepository repository.AuditLogRepository,
 	unitOfWork repository.UnitOfWork,
 ) service.InventoryService {
 	return &InventoryService{
-		inventoryRepository:        inventoryRepository,
-		inventoryHistoryRepository: inventoryHistoryRepository,
-		userRepository:             userRepository,
-		productRepository:          productRepository,
-		unitOfWork:                 unitOfWork,
+		inventoryRepository:          inventoryRepository,
+		inventoryHistoryRepository:   inventoryHistoryRepository,
+		userRepository:               userRepository,
+		productRepository:            productRepository,
+		orderItemRepository:          orderItemRepository,
+		productCostHistoryRepository: productCostHistoryRepository,
+		warehouseRepository:          warehouseRepository,
+		auditLogRepository:           auditLogRepository,
+		unitOfWork:                   unitOfWork,
 	}
 }
 
-func (s *InventoryService) GetAll(ctx context.Context) (*model.GetAllInventoryResponse, string) {
-	// Get all inventory
-	inventories, err := s.inventoryRepository.GetAllQuery(ctx, nil)
+func (s *InventoryService) GetAll(ctx context.Context, warehouseID int) (*model.GetAllInventoryResponse, string) {
+	// Get the inventory of the warehouse, or of every warehouse
+	inventories, err := s.inventoryRepository.GetAllQuery(ctx, warehouseID, nil)
 	if err != nil {
 		log.Error("InventoryService.GetAll Error when get inventories: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
@@ -57,30 +71,38 @@ func (s *InventoryService) GetAll(ctx context.Context) (*model.GetAllInventoryRe
 			log.Error("InventoryService.GetAll Error when get product for inventory " + string(rune(inventory.ID)) + ": " + err.Error())
 			// Continue without product info for this inventory
 			inventoryResponses[i] = model.InventoryWithProductResponse{
-				ID:        inventory.ID,
-				ProductID: inventory.ProductID,
-				Quantity:  inventory.Quantity,
-				Version:   inventory.Version,
+				ID:                inventory.ID,
+				WarehouseID:       inventory.WarehouseID,
+				WarehouseName:     inventory.WarehouseName,
+				ProductID:         inventory.ProductID,
+				Quantity:          inventory.Quantity,
+				ReservedQuantity:  inventory.ReservedQuantity,
+				AvailableQuantity: availableQuantity(&inventory),
+				Version:           inventory.Version,
 				Product: model.ProductInfo{
-					ID:            inventory.ProductID,
-					Name:          "N/A",
-					Spec:          0,
-					OriginalPrice: 0,
+					ID:   inventory.ProductID,
+					Name: "N/A",
+					Spec: 0,
 				},
 			}
 			continue
 		}
 
 		inventoryResponses[i] = model.InventoryWithProductResponse{
-			ID:        inventory.ID,
-			ProductID: inventory.ProductID,
-			Quantity:  inventory.Quantity,
-			Version:   inventory.Version,
+			ID:                inventory.ID,
+			WarehouseID:       inventory.WarehouseID,
+			WarehouseName:     inventory.WarehouseName,
+			ProductID:         inventory.ProductID,
+			Quantity:          inventory.Quantity,
+			ReservedQuantity:  inventory.ReservedQuantity,
+			AvailableQuantity: availableQuantity(&inventory),
+			Version:           inventory.Version,
 			Product: model.ProductInfo{
 				ID:            product.ID,
 				Name:          product.Name,
 				Spec:          product.Spec,
-				OriginalPrice: product.OriginalPrice,
+				OriginalPrice: &product.OriginalPrice,
+				AverageCost:   &product.AverageCost,
 			},
 		}
 	}
@@ -90,9 +112,14 @@ func (s *InventoryService) GetAll(ctx context.Context) (*model.GetAllInventoryRe
 	}, ""
 }
 
-func (s *InventoryService) GetByProductID(ctx *gin.Context, productID int) (*model.InventoryResponse, string) {
-	// Get inventory by product ID
-	inventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, productID, nil)
+func (s *InventoryService) GetByProductID(ctx *gin.Context, productID int, warehouseID *int) (*model.InventoryResponse, string) {
+	resolvedWarehouseID, errCode := resolveWarehouseID(ctx, s.warehouseRepository, warehouseID, nil)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	// Get inventory of the product in the warehouse
+	inventory, err := s.inventoryRepository.GetOneByWarehouseAndProductIDQuery(ctx, resolvedWarehouseID, productID, nil)
 	if err != nil {
 		log.Error("InventoryService.GetByProductID Error when get inventory: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
@@ -103,12 +130,8 @@ func (s *InventoryService) GetByProductID(ctx *gin.Context, productID int) (*mod
 	}
 
 	// Return response
-	return &model.InventoryResponse{
-		ID:        inventory.ID,
-		ProductID: inventory.ProductID,
-		Quantity:  inventory.Quantity,
-		Version:   inventory.Version,
-	}, ""
+	response := toInventoryResponse(*inventory)
+	return &response, ""
 }
 
 func (s *InventoryService) UpdateQuantity(ctx *gin.Context, productID int, request model.UpdateInventoryQuantityRequest) (*model.InventoryResponse, string) {
@@ -131,6 +154,22 @@ func (s *InventoryService) UpdateQuantity(ctx *gin.Context, productID int, reque
 		return nil, error_utils.ErrorCode.UNAUTHORIZED
 	}
 
+	// A unit cost only applies to stock coming in, and since it moves the average cost
+	// only roles that can see cost figures may set it
+	if request.UnitCost != nil {
+		if request.Quantity <= 0 || *request.UnitCost < 0 {
+			return nil, error_utils.ErrorCode.BAD_REQUEST
+		}
+		if !middleware.CanViewCostHelper(ctx) {
+			return nil, error_utils.ErrorCode.FORBIDDEN
+		}
+	}
+
+	warehouseID, errCode := resolveWarehouseID(ctx, s.warehouseRepository, request.WarehouseID, nil)
+	if errCode != "" {
+		return nil, errCode
+	}
+
 	// Begin transaction
 	tx, err := s.unitOfWork.Begin(ctx)
 	if err != nil {
@@ -145,22 +184,14 @@ func (s *InventoryService) UpdateQuantity(ctx *gin.Context, productID int, reque
 		}
 	}()
 
-	toBeLockedInventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, productID, tx)
-	if err != nil {
-		log.Error("InventoryService.UpdateQuantity Error when get inventory: " + err.Error())
-		return nil, error_utils.ErrorCode.DB_DOWN
-	}
-	if toBeLockedInventory == nil {
-		return nil, error_utils.ErrorCode.NOT_FOUND
-	}
-
-	// Get inventory with FOR UPDATE lock
-	existingInventory, err := s.inventoryRepository.GetOneByIDForUpdateQuery(ctx, toBeLockedInventory.ID, tx)
+	// Lock the product's inventories in every warehouse, in the same order as orders and transfers do
+	inventoryMap, err := lockInventories(ctx, s.inventoryRepository, []int{productID}, tx)
 	if err != nil {
-		log.Error("InventoryService.UpdateQuantity Error when get inventory: " + err.Error())
+		log.Error("InventoryService.UpdateQuantity Error when lock inventories: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
+	existingInventory := inventoryMap[inventoryKey{warehouseID: warehouseID, productID: productID}]
 	if existingInventory == nil {
 		return nil, error_utils.ErrorCode.NOT_FOUND
 	}
@@ -170,17 +201,26 @@ func (s *InventoryService) UpdateQuantity(ctx *gin.Context, productID int, reque
 		return nil, error_utils.ErrorCode.INVENTORY_VERSION_MISMATCH
 	}
 
+	// Goods reserved for pending orders have to stay on hand until those orders are delivered or cancelled
+	if request.Quantity < 0 && availableQuantity(existingInventory) < -request.Quantity {
+		return nil, error_utils.ErrorCode.INVENTORY_QUANTITY_EXCEEDED
+	}
+
 	// Generate new version UUID
 	newVersion := uuid.New().String()
 
 	// Update inventory quantity with version check
-	err = s.inventoryRepository.UpdateQuantityWithVersionCommand(ctx, productID, request.Quantity, request.Version, newVersion, tx)
+	err = s.inventoryRepository.UpdateQuantityWithVersionCommand(ctx, existingInventory.ID, request.Quantity, request.Version, newVersion, tx)
 	if err != nil {
 		// Check for specific error types
 		var constraintViolationError *error_utils.ConstraintViolationError
 		if errors.As(err, &constraintViolationError) {
 			return nil, error_utils.ErrorCode.INVENTORY_QUANTITY_NEGATIVE
 		}
+		var reservedQuantityViolationError *error_utils.ReservedQuantityViolationError
+		if errors.As(err, &reservedQuantityViolationError) {
+			return nil, error_utils.ErrorCode.INVENTORY_QUANTITY_EXCEEDED
+		}
 
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
@@ -188,8 +228,10 @@ func (s *InventoryService) UpdateQuantity(ctx *gin.Context, productID int, reque
 	// Create inventory history record
 	inventoryHistory := &entity.InventoryHistory{
 		ProductID:     productID,
+		WarehouseID:   warehouseID,
 		Quantity:      request.Quantity,
 		FinalQuantity: existingInventory.Quantity + request.Quantity,
+		MovementType:  entity.InventoryMovementType.ADJUSTMENT,
 		ImporterName:  user.Username,
 		ImportedAt:    time.Now(),
 		Note:          request.Note,
@@ -201,6 +243,20 @@ func (s *InventoryService) UpdateQuantity(ctx *gin.Context, productID int, reque
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
+	if request.UnitCost != nil {
+		errCode := applyReceivedCost(ctx, s.productRepository, s.inventoryRepository, s.productCostHistoryRepository, productID, request.Quantity, *request.UnitCost, entity.ProductCostSource.INVENTORY_ADJUSTMENT, nil, tx)
+		if errCode != "" {
+			return nil, errCode
+		}
+	}
+
+	updatedInventorySnapshot := *existingInventory
+	updatedInventorySnapshot.Quantity += request.Quantity
+	updatedInventorySnapshot.Version = newVersion
+	if errCode := recordAuditLog(ctx, s.auditLogRepository, entity.AuditAction.UPDATE, entity.AuditEntityType.INVENTORY, existingInventory.ID, existingInventory, updatedInventorySnapshot, tx); errCode != "" {
+		return nil, errCode
+	}
+
 	// Commit transaction
 	err = s.unitOfWork.Commit(tx)
 	if err != nil {
@@ -209,17 +265,222 @@ func (s *InventoryService) UpdateQuantity(ctx *gin.Context, productID int, reque
 	}
 
 	// Get updated inventory
-	updatedInventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, productID, nil)
+	updatedInventory, err := s.inventoryRepository.GetOneByWarehouseAndProductIDQuery(ctx, warehouseID, productID, nil)
 	if err != nil {
 		log.Error("InventoryService.UpdateQuantity Error when get updated inventory: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
 	// Return response
-	return &model.InventoryResponse{
-		ID:        updatedInventory.ID,
-		ProductID: updatedInventory.ProductID,
-		Quantity:  updatedInventory.Quantity,
-		Version:   updatedInventory.Version,
+	response := toInventoryResponse(*updatedInventory)
+	return &response, ""
+}
+
+func (s *InventoryService) GetLowStock(ctx context.Context, days int) (*model.GetLowStockProductsResponse, string) {
+	products, err := s.productRepository.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("InventoryService.GetLowStock Error when get products: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	inventories, err := s.inventoryRepository.GetAllQuery(ctx, 0, nil)
+	if err != nil {
+		log.Error("InventoryService.GetLowStock Error when get inventories: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	productStocks := sumInventoriesByProduct(inventories)
+
+	// Sales velocity is measured over the last days, counting from the start of today
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -days)
+	soldQuantities, err := s.orderItemRepository.GetSoldQuantitiesSinceQuery(ctx, since, nil)
+	if err != nil {
+		log.Error("InventoryService.GetLowStock Error when get sold quantities: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	lowStockProducts := make([]model.LowStockProductResponse, 0)
+	for _, product := range products {
+		stock, ok := productStocks[product.ID]
+		if !ok || !isLowStock(product, stock) {
+			continue
+		}
+
+		soldQuantity := soldQuantities[product.ID]
+		lowStockProducts = append(lowStockProducts, model.LowStockProductResponse{
+			ProductID:                product.ID,
+			ProductName:              product.Name,
+			Spec:                     product.Spec,
+			Quantity:                 stock.Quantity,
+			ReservedQuantity:         stock.ReservedQuantity,
+			AvailableQuantity:        availableQuantity(&stock),
+			MinStock:                 product.MinStock,
+			ReorderQuantity:          product.ReorderQuantity,
+			SoldQuantity:             soldQuantity,
+			DailySalesVelocity:       float64(soldQuantity) / float64(days),
+			SuggestedReorderQuantity: suggestedReorderQuantity(product, stock, soldQuantity),
+		})
+	}
+
+	return &model.GetLowStockProductsResponse{
+		Days:     days,
+		Products: lowStockProducts,
 	}, ""
 }
+
+func toInventoryResponse(inventory entity.Inventory) model.InventoryResponse {
+	return model.InventoryResponse{
+		ID:                inventory.ID,
+		WarehouseID:       inventory.WarehouseID,
+		WarehouseName:     inventory.WarehouseName,
+		ProductID:         inventory.ProductID,
+		Quantity:          inventory.Quantity,
+		ReservedQuantity:  inventory.ReservedQuantity,
+		AvailableQuantity: availableQuantity(&inventory),
+		Version:           inventory.Version,
+	}
+}
+
+// inventoryKey identifies the stock of one product in one warehouse
+type inventoryKey struct {
+	warehouseID int
+	productID   int
+}
+
+// Helper to lock the inventories of the given products in every warehouse, keyed by warehouse and product.
+// Locking whole products in inventory ID order keeps the lock order the same for every caller,
+// whichever warehouses they actually touch.
+func lockInventories(ctx context.Context, inventoryRepository repository.InventoryRepository, productIDs []int, tx *sqlx.Tx) (map[inventoryKey]*entity.Inventory, error) {
+	inventoryIDs, err := inventoryRepository.GetInventoryIDsByProductIDsQuery(ctx, productIDs, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	lockedInventories, err := inventoryRepository.SelectManyForUpdate(ctx, inventoryIDs, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	inventoryMap := make(map[inventoryKey]*entity.Inventory, len(lockedInventories))
+	for i := range lockedInventories {
+		inv := &lockedInventories[i]
+		inventoryMap[inventoryKey{warehouseID: inv.WarehouseID, productID: inv.ProductID}] = inv
+	}
+	return inventoryMap, nil
+}
+
+// Helper to list the keys of a quantity map in product then warehouse order, so changes are applied deterministically
+func sortedInventoryKeys(quantities map[inventoryKey]int) []inventoryKey {
+	keys := make([]inventoryKey, 0, len(quantities))
+	for key := range quantities {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].productID != keys[j].productID {
+			return keys[i].productID < keys[j].productID
+		}
+		return keys[i].warehouseID < keys[j].warehouseID
+	})
+	return keys
+}
+
+// Helper to apply a quantity change to a locked inventory row: bumps its version, writes an inventory history row
+// and keeps the in-memory row up to date so further changes can be applied to it
+func adjustInventory(ctx context.Context, inventoryRepository repository.InventoryRepository, inventoryHistoryRepository repository.InventoryHistoryRepository, inv *entity.Inventory, quantity int, movementType string, username string, note string, referenceID *int, tx *sqlx.Tx) string {
+	newVersion := uuid.New().String()
+	err := inventoryRepository.UpdateQuantityWithVersionCommand(ctx, inv.ID, quantity, inv.Version, newVersion, tx)
+	if err != nil {
+		var constraintViolationError *error_utils.ConstraintViolationError
+		if errors.As(err, &constraintViolationError) {
+			log.Error("adjustInventory Error: inventory quantity negative for productID ", inv.ProductID, " in warehouse ", inv.WarehouseID)
+			return error_utils.ErrorCode.INVENTORY_QUANTITY_NEGATIVE
+		}
+		var reservedQuantityViolationError *error_utils.ReservedQuantityViolationError
+		if errors.As(err, &reservedQuantityViolationError) {
+			log.Error("adjustInventory Error: inventory quantity below reserved quantity for productID ", inv.ProductID, " in warehouse ", inv.WarehouseID)
+			return error_utils.ErrorCode.INVENTORY_QUANTITY_EXCEEDED
+		}
+		log.Error("adjustInventory Error when update inventory: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	inventoryHistory := &entity.InventoryHistory{
+		ProductID:     inv.ProductID,
+		WarehouseID:   inv.WarehouseID,
+		Quantity:      quantity,
+		FinalQuantity: inv.Quantity + quantity,
+		MovementType:  movementType,
+		ImporterName:  username,
+		ImportedAt:    time.Now(),
+		Note:          note,
+		ReferenceID:   referenceID,
+	}
+	err = inventoryHistoryRepository.CreateCommand(ctx, inventoryHistory, tx)
+	if err != nil {
+		log.Error("adjustInventory Error when create inventory history: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	inv.Quantity += quantity
+	inv.Version = newVersion
+	return ""
+}
+
+// Helper to get the stock that is neither sold nor promised to a pending order
+func availableQuantity(inv *entity.Inventory) int {
+	return inv.Quantity - inv.ReservedQuantity
+}
+
+// Helper to add up the inventories of every warehouse per product, the warehouse fields of the totals are left empty
+func sumInventoriesByProduct(inventories []entity.Inventory) map[int]entity.Inventory {
+	totals := make(map[int]entity.Inventory)
+	for _, inventory := range inventories {
+		total := totals[inventory.ProductID]
+		total.ProductID = inventory.ProductID
+		total.Quantity += inventory.Quantity
+		total.ReservedQuantity += inventory.ReservedQuantity
+		totals[inventory.ProductID] = total
+	}
+	return totals
+}
+
+// Helper to tell whether the stock of a product, over all warehouses, fell below its minimum.
+// Goods reserved for pending orders are already gone as far as reordering is concerned.
+func isLowStock(product entity.Product, stock entity.Inventory) bool {
+	return availableQuantity(&stock) < product.MinStock
+}
+
+// Helper to suggest how much of a low-stock product to order: enough to get back to the minimum stock and cover
+// as many sales as were made over the measured period, and never less than the product's reorder quantity
+func suggestedReorderQuantity(product entity.Product, stock entity.Inventory, soldQuantity int) int {
+	suggested := product.MinStock + soldQuantity - availableQuantity(&stock)
+	if suggested < product.ReorderQuantity {
+		return product.ReorderQuantity
+	}
+	return suggested
+}
+
+// Helper to reserve stock of a locked inventory row for a pending order, a negative quantity releases the reservation.
+// On-hand stock does not move, so no inventory history row is written; the version is bumped since availability changed.
+func reserveInventory(ctx context.Context, inventoryRepository repository.InventoryRepository, inv *entity.Inventory, quantity int, tx *sqlx.Tx) string {
+	newVersion := uuid.New().String()
+	err := inventoryRepository.UpdateReservedQuantityWithVersionCommand(ctx, inv.ID, quantity, inv.Version, newVersion, tx)
+	if err != nil {
+		var constraintViolationError *error_utils.ConstraintViolationError
+		if errors.As(err, &constraintViolationError) {
+			log.Error("reserveInventory Error: reserved quantity negative for productID ", inv.ProductID, " in warehouse ", inv.WarehouseID)
+			return error_utils.ErrorCode.INVENTORY_QUANTITY_NEGATIVE
+		}
+		var reservedQuantityViolationError *error_utils.ReservedQuantityViolationError
+		if errors.As(err, &reservedQuantityViolationError) {
+			log.Error("reserveInventory Error: reserved quantity above quantity on hand for productID ", inv.ProductID, " in warehouse ", inv.WarehouseID)
+			return error_utils.ErrorCode.INVENTORY_QUANTITY_EXCEEDED
+		}
+		log.Error("reserveInventory Error when update reserved quantity: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	inv.ReservedQuantity += quantity
+	inv.Version = newVersion
+	return ""
+}
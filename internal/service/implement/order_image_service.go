@@ -13,15 +13,21 @@ import (
 
 type OrderImageService struct {
 	orderImageRepo repository.OrderImageRepository
+	auditLogRepo   repository.AuditLogRepository
+	unitOfWork     repository.UnitOfWork
 	s3Service      bean.S3Service
 }
 
 func NewOrderImageService(
 	orderImageRepo repository.OrderImageRepository,
+	auditLogRepo repository.AuditLogRepository,
+	unitOfWork repository.UnitOfWork,
 	s3Service bean.S3Service,
 ) service.OrderImageService {
 	return &OrderImageService{
 		orderImageRepo: orderImageRepo,
+		auditLogRepo:   auditLogRepo,
+		unitOfWork:     unitOfWork,
 		s3Service:      s3Service,
 	}
 }
@@ -45,13 +51,38 @@ func (s *OrderImageService) DeleteImage(ctx *gin.Context, imageID int) string {
 		// This prevents orphaned database records
 	}
 
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("OrderImageService.DeleteImage Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("OrderImageService.DeleteImage Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
 	// Delete from database
-	err = s.orderImageRepo.DeleteByIDCommand(ctx, imageID, nil)
+	err = s.orderImageRepo.DeleteByIDCommand(ctx, imageID, tx)
 	if err != nil {
 		log.Error("OrderImageService.DeleteImage Error deleting from database: " + err.Error())
 		return error_utils.ErrorCode.DB_DOWN
 	}
 
+	if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.DELETE, entity.AuditEntityType.ORDER_IMAGE, orderImage.ID, orderImage, nil, tx); errCode != "" {
+		return errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("OrderImageService.DeleteImage Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
 	return ""
 }
 
@@ -69,13 +100,38 @@ func (s *OrderImageService) GenerateSignedUploadURL(ctx *gin.Context, orderID in
 		S3Key:   s3Key,
 	}
 
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("OrderImageService.GenerateSignedUploadURL Error when begin transaction: " + err.Error())
+		return model.GenerateSignedUploadURLResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("OrderImageService.GenerateSignedUploadURL Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
 	// Save to database
-	err = s.orderImageRepo.CreateCommand(ctx, orderImage, nil)
+	err = s.orderImageRepo.CreateCommand(ctx, orderImage, tx)
 	if err != nil {
 		log.Error("OrderImageService.GenerateSignedUploadURL Error saving to database: " + err.Error())
 		return model.GenerateSignedUploadURLResponse{}, error_utils.ErrorCode.DB_DOWN
 	}
 
+	if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.CREATE, entity.AuditEntityType.ORDER_IMAGE, orderImage.ID, nil, orderImage, tx); errCode != "" {
+		return model.GenerateSignedUploadURLResponse{}, errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("OrderImageService.GenerateSignedUploadURL Error when commit transaction: " + err.Error())
+		return model.GenerateSignedUploadURLResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+
 	response := model.GenerateSignedUploadURLResponse{
 		SignedURL: signedURL,
 		S3Key:     s3Key,
@@ -0,0 +1,300 @@
+package serviceimplement
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type OrderReturnService struct {
+	orderReturnRepo      repository.OrderReturnRepository
+	orderReturnItemRepo  repository.OrderReturnItemRepository
+	orderRepo            repository.OrderRepository
+	orderItemRepo        repository.OrderItemRepository
+	inventoryRepo        repository.InventoryRepository
+	inventoryHistoryRepo repository.InventoryHistoryRepository
+	paymentRepo          repository.PaymentRepository
+	userRepo             repository.UserRepository
+	unitOfWork           repository.UnitOfWork
+}
+
+func NewOrderReturnService(
+	orderReturnRepo repository.OrderReturnRepository,
+	orderReturnItemRepo repository.OrderReturnItemRepository,
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	inventoryRepo repository.InventoryRepository,
+	inventoryHistoryRepo repository.InventoryHistoryRepository,
+	paymentRepo repository.PaymentRepository,
+	userRepo repository.UserRepository,
+	unitOfWork repository.UnitOfWork,
+) service.OrderReturnService {
+	return &OrderReturnService{
+		orderReturnRepo:      orderReturnRepo,
+		orderReturnItemRepo:  orderReturnItemRepo,
+		orderRepo:            orderRepo,
+		orderItemRepo:        orderItemRepo,
+		inventoryRepo:        inventoryRepo,
+		inventoryHistoryRepo: inventoryHistoryRepo,
+		paymentRepo:          paymentRepo,
+		userRepo:             userRepo,
+		unitOfWork:           unitOfWork,
+	}
+}
+
+func (s *OrderReturnService) Create(ctx *gin.Context, orderID int, request model.CreateOrderReturnRequest) (*model.OrderReturnResponse, string) {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("OrderReturnService.Create Error: user ID not found in context")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Get user details to get username
+	user, err := s.userRepo.FindByIDQuery(ctx, int(userID), nil)
+	if err != nil {
+		log.Error("OrderReturnService.Create Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		log.Error("OrderReturnService.Create Error: user not found")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Each order item may appear at most once in a return
+	requestedQuantities := make(map[int]int)
+	for _, item := range request.Items {
+		if item.Quantity <= 0 {
+			log.Error("OrderReturnService.Create Error: returned quantity must be positive")
+			return nil, error_utils.ErrorCode.BAD_REQUEST
+		}
+		if _, exists := requestedQuantities[item.OrderItemID]; exists {
+			log.Error("OrderReturnService.Create Error: order item ", item.OrderItemID, " appears more than once")
+			return nil, error_utils.ErrorCode.BAD_REQUEST
+		}
+		requestedQuantities[item.OrderItemID] = item.Quantity
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("OrderReturnService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("OrderReturnService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock the order so concurrent returns cannot exceed what was sold
+	order, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("OrderReturnService.Create Error when get order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+		return nil, error_utils.ErrorCode.ORDER_CANCELLED
+	}
+	if order.DeliveryStatus == entity.OrderDeliveryStatus.PENDING {
+		return nil, error_utils.ErrorCode.ORDER_NOT_DELIVERED
+	}
+
+	orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("OrderReturnService.Create Error when get order items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	orderItemMap := make(map[int]entity.OrderItem)
+	for _, item := range orderItems {
+		orderItemMap[item.ID] = item
+	}
+
+	returnedQuantities, err := s.orderReturnItemRepo.GetReturnedQuantitiesByOrderIDQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("OrderReturnService.Create Error when get returned quantities: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Build the credit note lines, valued at the price the goods were sold and bought at
+	returnItems := make([]entity.OrderReturnItem, 0, len(request.Items))
+	quantityToRestock := make(map[inventoryKey]int)
+	totalRefundAmount := 0
+	totalOriginalCost := 0
+	for _, item := range request.Items {
+		orderItem, ok := orderItemMap[item.OrderItemID]
+		if !ok {
+			log.Error("OrderReturnService.Create Error: order item ", item.OrderItemID, " does not belong to order ", orderID)
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+		if returnedQuantities[orderItem.ID]+item.Quantity > orderItem.Quantity {
+			log.Error("OrderReturnService.Create Error: returned quantity exceeds sold quantity for order item ", orderItem.ID)
+			return nil, error_utils.ErrorCode.RETURN_QUANTITY_EXCEEDED
+		}
+
+		// Pro-rate cumulatively so an item returned in several pieces refunds exactly its whole amount in the end
+		alreadyReturned := returnedQuantities[orderItem.ID]
+		itemAmount, _ := calculateOrderAmountsAndProductCount([]entity.OrderItem{orderItem})
+		refundAmount := itemAmount*(alreadyReturned+item.Quantity)/orderItem.Quantity - itemAmount*alreadyReturned/orderItem.Quantity
+		returnedQuantities[orderItem.ID] += item.Quantity
+		originalCost := item.Quantity * orderItem.OriginalPrice
+
+		returnItems = append(returnItems, entity.OrderReturnItem{
+			OrderItemID:  orderItem.ID,
+			ProductID:    orderItem.ProductID,
+			Quantity:     item.Quantity,
+			RefundAmount: refundAmount,
+			OriginalCost: originalCost,
+			ExportFrom:   orderItem.ExportFrom,
+		})
+		totalRefundAmount += refundAmount
+		totalOriginalCost += originalCost
+
+		// Only goods that left our own inventory go back into it
+		if orderItem.ExportFrom == entity.OrderExportFrom.INVENTORY && orderItem.WarehouseID != nil {
+			quantityToRestock[inventoryKey{warehouseID: *orderItem.WarehouseID, productID: orderItem.ProductID}] += item.Quantity
+		}
+	}
+
+	if len(quantityToRestock) > 0 {
+		errCode := restockProducts(ctx, s.inventoryRepo, s.inventoryHistoryRepo, quantityToRestock, entity.InventoryMovementType.RETURN, user.Username, "Nhập lại hàng khách trả từ đơn số "+strconv.Itoa(orderID), orderID, tx)
+		if errCode != "" {
+			return nil, errCode
+		}
+	}
+
+	orderReturn := &entity.OrderReturn{
+		OrderID:           orderID,
+		ReturnedAt:        request.ReturnedAt,
+		Reason:            request.Reason,
+		TotalRefundAmount: totalRefundAmount,
+		TotalOriginalCost: totalOriginalCost,
+		CreatedBy:         user.ID,
+		CreatedByName:     user.Username,
+		CreatedAt:         time.Now(),
+	}
+	err = s.orderReturnRepo.CreateCommand(ctx, orderReturn, tx)
+	if err != nil {
+		log.Error("OrderReturnService.Create Error when create order return: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	for i := range returnItems {
+		returnItems[i].OrderReturnID = orderReturn.ID
+		err = s.orderReturnItemRepo.CreateCommand(ctx, &returnItems[i], tx)
+		if err != nil {
+			log.Error("OrderReturnService.Create Error when create order return item: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	// The credit note lowers the order's revenue and cost, so the debt status has to be derived again
+	order.TotalReturnedRevenue += totalRefundAmount
+	order.TotalReturnedCost += totalOriginalCost
+	paidAmounts, err := s.paymentRepo.GetTotalPaidByOrderIDsQuery(ctx, []int{orderID}, tx)
+	if err != nil {
+		log.Error("OrderReturnService.Create Error when get payments: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	itemsAmount, _ := calculateOrderAmountsAndProductCount(orderItems)
+	debtStatus := deriveOrderDebtStatus(calculateOrderTotalAmount(order, itemsAmount), paidAmounts[orderID])
+	order.DebtStatus = &debtStatus
+
+	err = s.orderRepo.UpdateCommand(ctx, order, tx)
+	if err != nil {
+		log.Error("OrderReturnService.Create Error when update order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("OrderReturnService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toOrderReturnResponse(*orderReturn, returnItems)
+	return &response, ""
+}
+
+func (s *OrderReturnService) GetAllByOrderID(ctx *gin.Context, orderID int) (*model.GetAllOrderReturnsResponse, string) {
+	order, err := s.orderRepo.GetOneByIDQuery(ctx, orderID, nil)
+	if err != nil {
+		log.Error("OrderReturnService.GetAllByOrderID Error when get order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	orderReturns, err := s.orderReturnRepo.GetAllByOrderIDQuery(ctx, orderID, nil)
+	if err != nil {
+		log.Error("OrderReturnService.GetAllByOrderID Error when get order returns: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	orderReturnIDs := make([]int, 0, len(orderReturns))
+	for _, orderReturn := range orderReturns {
+		orderReturnIDs = append(orderReturnIDs, orderReturn.ID)
+	}
+	orderReturnItems, err := s.orderReturnItemRepo.GetAllByOrderReturnIDsQuery(ctx, orderReturnIDs, nil)
+	if err != nil {
+		log.Error("OrderReturnService.GetAllByOrderID Error when get order return items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	itemsByReturnID := make(map[int][]entity.OrderReturnItem)
+	for _, item := range orderReturnItems {
+		itemsByReturnID[item.OrderReturnID] = append(itemsByReturnID[item.OrderReturnID], item)
+	}
+
+	returnResponses := make([]model.OrderReturnResponse, len(orderReturns))
+	for i, orderReturn := range orderReturns {
+		returnResponses[i] = toOrderReturnResponse(orderReturn, itemsByReturnID[orderReturn.ID])
+	}
+
+	return &model.GetAllOrderReturnsResponse{
+		TotalReturnedRevenue: order.TotalReturnedRevenue,
+		TotalReturnedCost:    &order.TotalReturnedCost,
+		Returns:              returnResponses,
+	}, ""
+}
+
+func toOrderReturnResponse(orderReturn entity.OrderReturn, items []entity.OrderReturnItem) model.OrderReturnResponse {
+	itemResponses := make([]model.OrderReturnItemResponse, len(items))
+	for i, item := range items {
+		itemResponses[i] = model.OrderReturnItemResponse{
+			ID:           item.ID,
+			OrderItemID:  item.OrderItemID,
+			ProductID:    item.ProductID,
+			Quantity:     item.Quantity,
+			RefundAmount: item.RefundAmount,
+			OriginalCost: &item.OriginalCost,
+			ExportFrom:   item.ExportFrom,
+		}
+	}
+
+	return model.OrderReturnResponse{
+		ID:                orderReturn.ID,
+		OrderID:           orderReturn.OrderID,
+		ReturnedAt:        orderReturn.ReturnedAt,
+		Reason:            orderReturn.Reason,
+		TotalRefundAmount: orderReturn.TotalRefundAmount,
+		TotalOriginalCost: &orderReturn.TotalOriginalCost,
+		CreatedBy:         orderReturn.CreatedBy,
+		CreatedByName:     orderReturn.CreatedByName,
+		CreatedAt:         orderReturn.CreatedAt,
+		Items:             itemResponses,
+	}
+}
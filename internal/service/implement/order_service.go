@@ -2,12 +2,14 @@ package serviceimplement
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/pna/order-app-backend/internal/bean"
 	"github.com/pna/order-app-backend/internal/controller/http/middleware"
 	"github.com/pna/order-app-backend/internal/domain/entity"
@@ -28,6 +30,13 @@ type OrderService struct {
 	unitOfWork           repository.UnitOfWork
 	customerRepo         repository.CustomerRepository
 	orderImageRepo       repository.OrderImageRepository
+	paymentRepo          repository.PaymentRepository
+	statusHistoryRepo    repository.OrderStatusHistoryRepository
+	orderReturnItemRepo  repository.OrderReturnItemRepository
+	auditLogRepo         repository.AuditLogRepository
+	warehouseRepo        repository.WarehouseRepository
+	productPriceRepo     repository.ProductPriceRepository
+	customerDiscountRepo repository.CustomerDiscountRepository
 	s3Service            bean.S3Service
 }
 
@@ -41,6 +50,13 @@ func NewOrderService(
 	productRepo repository.ProductRepository,
 	customerRepo repository.CustomerRepository,
 	orderImageRepo repository.OrderImageRepository,
+	paymentRepo repository.PaymentRepository,
+	statusHistoryRepo repository.OrderStatusHistoryRepository,
+	orderReturnItemRepo repository.OrderReturnItemRepository,
+	auditLogRepo repository.AuditLogRepository,
+	warehouseRepo repository.WarehouseRepository,
+	productPriceRepo repository.ProductPriceRepository,
+	customerDiscountRepo repository.CustomerDiscountRepository,
 	s3Service bean.S3Service) service.OrderService {
 	return &OrderService{
 		orderRepo:            orderRepo,
@@ -52,6 +68,13 @@ func NewOrderService(
 		productRepo:          productRepo,
 		customerRepo:         customerRepo,
 		orderImageRepo:       orderImageRepo,
+		paymentRepo:          paymentRepo,
+		statusHistoryRepo:    statusHistoryRepo,
+		orderReturnItemRepo:  orderReturnItemRepo,
+		auditLogRepo:         auditLogRepo,
+		warehouseRepo:        warehouseRepo,
+		productPriceRepo:     productPriceRepo,
+		customerDiscountRepo: customerDiscountRepo,
 		s3Service:            s3Service,
 	}
 }
@@ -75,6 +98,34 @@ func calculateOrderAmountsAndProductCount(orderItems []entity.OrderItem) (totalA
 	return
 }
 
+// Helper to calculate the amount the customer has to pay: items after discount, minus returned goods, plus additional cost, plus tax
+func calculateOrderTotalAmount(order *entity.Order, itemsAmount int) int {
+	totalAmount := itemsAmount - order.TotalReturnedRevenue + order.AdditionalCost
+	totalAmount += int(float64(totalAmount) * float64(order.TaxPercent) / 100)
+	return totalAmount
+}
+
+// Helper to derive the debt status of an order from its total amount and what has been paid so far
+func deriveOrderDebtStatus(totalAmount int, paidAmount int) string {
+	if paidAmount >= totalAmount {
+		return entity.OrderDebtStatus.PAID
+	}
+	if paidAmount > 0 {
+		return entity.OrderDebtStatus.PARTIAL
+	}
+	return entity.OrderDebtStatus.UNPAID
+}
+
+// Helper to calculate the profit/loss of an order from its stored cost and revenue, net of returned goods
+func calculateOrderProfitLoss(order *entity.Order) (profitLoss int, profitLossPercentage float64) {
+	netOriginalCost := order.TotalOriginalCost - order.TotalReturnedCost
+	profitLoss = order.TotalSalesRevenue - order.TotalReturnedRevenue - netOriginalCost + order.AdditionalCost
+	if netOriginalCost > 0 {
+		profitLossPercentage = float64(profitLoss) / float64(netOriginalCost) * 100
+	}
+	return profitLoss, profitLossPercentage
+}
+
 // Helper to calculate total original cost and total sales revenue for order items
 func (s *OrderService) calculateOrderCostAndRevenue(ctx context.Context, orderItems []model.OrderItemRequest) (totalOriginalCost int, totalSalesRevenue int, err string) {
 	totalOriginalCost = 0
@@ -92,8 +143,8 @@ func (s *OrderService) calculateOrderCostAndRevenue(ctx context.Context, orderIt
 			return 0, 0, error_utils.ErrorCode.NOT_FOUND
 		}
 
-		// Calculate original cost
-		originalCost := item.Quantity * product.OriginalPrice
+		// Calculate original cost at the product's current weighted-average cost
+		originalCost := item.Quantity * product.AverageCost
 		totalOriginalCost += originalCost
 
 		// Calculate final revenue (after discount)
@@ -106,73 +157,197 @@ func (s *OrderService) calculateOrderCostAndRevenue(ctx context.Context, orderIt
 	return totalOriginalCost, totalSalesRevenue, ""
 }
 
-func (s *OrderService) GetAll(ctx context.Context, userID int, customerID int, deliveryStatuses string, sortBy string, fromDate *time.Time, toDate *time.Time) (model.GetAllOrdersResponse, string) {
-	orders, err := s.orderRepo.GetAllWithFiltersQuery(ctx, customerID, deliveryStatuses, sortBy, fromDate, toDate, nil)
-	if err != nil {
-		log.Error("OrderService.GetAll Error: " + err.Error())
-		return model.GetAllOrdersResponse{}, error_utils.ErrorCode.DB_DOWN
+// Helper to calculate total original cost and total sales revenue from stored order items,
+// using the original price snapshotted on each item
+func calculateOrderItemsCostAndRevenue(orderItems []entity.OrderItem) (totalOriginalCost int, totalSalesRevenue int) {
+	for _, item := range orderItems {
+		totalOriginalCost += item.Quantity * item.OriginalPrice
+
+		sellingRevenue := item.Quantity * item.SellingPrice
+		discountAmount := (sellingRevenue * item.Discount) / 100
+		totalSalesRevenue += sellingRevenue - discountAmount
 	}
+	return totalOriginalCost, totalSalesRevenue
+}
 
-	allOrderTotalAmount := 0
-	allOrderTotalProfitLoss := 0
+// orderItemKey identifies an order item inside an order: each product has at most one item per warehouse
+// it is exported from and one item from external sources
+type orderItemKey struct {
+	productID   int
+	exportFrom  string
+	warehouseID int
+}
 
-	resp := model.GetAllOrdersResponse{Orders: make([]model.OrderResponse, 0, len(orders))}
-	for _, o := range orders {
-		// Fetch customer information
-		customer, err := s.customerRepo.GetOneByIDQuery(ctx, o.CustomerID, nil)
-		if err != nil {
-			log.Error("OrderService.GetAll Error fetching customer: " + err.Error())
+func newOrderItemKey(productID int, exportFrom string, warehouseID *int) orderItemKey {
+	key := orderItemKey{productID: productID, exportFrom: exportFrom}
+	if warehouseID != nil {
+		key.warehouseID = *warehouseID
+	}
+	return key
+}
+
+// Helper to fill in the warehouse of order items exported from inventory, defaulting to the default warehouse,
+// and to clear it on items from external sources. The default warehouse is only looked up when needed.
+func (s *OrderService) resolveOrderItemWarehouses(ctx context.Context, orderItems []model.OrderItemRequest) string {
+	defaultWarehouseID := 0
+	for i := range orderItems {
+		item := &orderItems[i]
+		if item.ExportFrom != entity.OrderExportFrom.INVENTORY {
+			item.WarehouseID = nil
 			continue
 		}
+		if item.WarehouseID != nil {
+			continue
+		}
+		if defaultWarehouseID == 0 {
+			warehouseID, errCode := resolveWarehouseID(ctx, s.warehouseRepo, nil, nil)
+			if errCode != "" {
+				return errCode
+			}
+			defaultWarehouseID = warehouseID
+		}
+		warehouseID := defaultWarehouseID
+		item.WarehouseID = &warehouseID
+	}
+	return ""
+}
 
-		// Fetch order items to calculate total amount and product count
-		orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, o.ID, nil)
+const (
+	defaultOrderPageSize = 50
+	maxOrderPageSize     = 200
+)
+
+func (s *OrderService) GetAll(ctx context.Context, req model.GetAllOrdersRequest) (model.GetAllOrdersResponse, string) {
+	filter := repository.OrderFilter{
+		CustomerID:       req.CustomerID,
+		DeliveryStatuses: req.DeliveryStatuses,
+		DebtStatuses:     req.DebtStatuses,
+		Search:           req.Search,
+		MinAmount:        req.MinAmount,
+		MaxAmount:        req.MaxAmount,
+		FromDate:         req.FromDate,
+		ToDate:           req.ToDate,
+		SortBy:           req.SortBy,
+	}
+
+	summary, err := s.orderRepo.GetSummaryWithFiltersQuery(ctx, filter, nil)
+	if err != nil {
+		log.Error("OrderService.GetAll Error fetching summary: " + err.Error())
+		return model.GetAllOrdersResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+
+	if req.Cursor != "" {
+		cursorOrderDate, cursorID, err := decodeOrderCursor(req.Cursor)
 		if err != nil {
-			log.Error("OrderService.GetAll Error fetching order items: " + err.Error())
-			continue
+			log.Error("OrderService.GetAll Error: invalid cursor: " + err.Error())
+			return model.GetAllOrdersResponse{}, error_utils.ErrorCode.BAD_REQUEST
 		}
-		totalAmount, productCount := calculateOrderAmountsAndProductCount(orderItems)
-		totalAmount += o.AdditionalCost
-		totalAmount += int(float64(totalAmount) * float64(o.TaxPercent) / 100)
-		// Calculate profit/loss from stored cost and revenue values
-		totalProfitLoss := o.TotalSalesRevenue - o.TotalOriginalCost + o.AdditionalCost
-		totalProfitLossPercentage := 0.0
-		if o.TotalOriginalCost > 0 {
-			totalProfitLossPercentage = float64(totalProfitLoss) / float64(o.TotalOriginalCost) * 100
-		}
-
-		allOrderTotalAmount += totalAmount
-		allOrderTotalProfitLoss += totalProfitLoss
-
-		resp.Orders = append(resp.Orders, model.OrderResponse{
-			ID:                   o.ID,
-			OrderDate:            o.OrderDate,
-			DeliveryStatus:       o.DeliveryStatus,
-			DebtStatus:           o.DebtStatus,
-			StatusTransitionedAt: o.StatusTransitionedAt,
-			AdditionalCost:       o.AdditionalCost,
-			AdditionalCostNote:   o.AdditionalCostNote,
-			Customer: model.CustomerResponse{
-				ID:      customer.ID,
-				Name:    customer.Name,
-				Phone:   customer.Phone,
-				Address: customer.Address,
-			},
-			OrderItems:                nil, // Omit order items in GetAll
-			TaxPercent:                &o.TaxPercent,
-			TotalAmount:               &totalAmount,
-			ProductCount:              &productCount,
-			TotalProfitLoss:           &totalProfitLoss,
-			TotalProfitLossPercentage: &totalProfitLossPercentage,
-		})
+		filter.CursorOrderDate = &cursorOrderDate
+		filter.CursorID = cursorID
 	}
 
-	resp.AllOrderTotalAmount = allOrderTotalAmount
-	resp.AllOrderTotalProfitLoss = allOrderTotalProfitLoss
+	// Fetch one extra row to know whether there is a next page
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultOrderPageSize
+	}
+	if limit > maxOrderPageSize {
+		limit = maxOrderPageSize
+	}
+	filter.Limit = limit + 1
+
+	orders, err := s.orderRepo.GetAllWithFiltersQuery(ctx, filter, nil)
+	if err != nil {
+		log.Error("OrderService.GetAll Error: " + err.Error())
+		return model.GetAllOrdersResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+
+	var nextCursor *string
+	if len(orders) > limit {
+		orders = orders[:limit]
+		cursor := encodeOrderCursor(orders[limit-1].OrderDate, orders[limit-1].ID)
+		nextCursor = &cursor
+	}
+
+	resp := model.GetAllOrdersResponse{
+		AllOrderTotalAmount:     summary.TotalAmount,
+		AllOrderTotalProfitLoss: &summary.TotalProfitLoss,
+		TotalCount:              summary.TotalCount,
+		NextCursor:              nextCursor,
+		Orders:                  make([]model.OrderResponse, 0, len(orders)),
+	}
+	for _, o := range orders {
+		resp.Orders = append(resp.Orders, toOrderListResponse(o))
+	}
 
 	return resp, ""
 }
 
+// Helper to build the listing response of an order, without its items
+func toOrderListResponse(o entity.OrderWithSummary) model.OrderResponse {
+	totalAmount := o.TotalAmount
+	paidAmount := o.PaidAmount
+	outstandingAmount := totalAmount - paidAmount
+	productCount := o.ProductCount
+	taxPercent := o.TaxPercent
+	// Calculate profit/loss from stored cost and revenue values
+	totalProfitLoss, totalProfitLossPercentage := calculateOrderProfitLoss(&o.Order)
+
+	return model.OrderResponse{
+		ID:                   o.ID,
+		OrderDate:            o.OrderDate,
+		DeliveryStatus:       o.DeliveryStatus,
+		DebtStatus:           o.DebtStatus,
+		StatusTransitionedAt: o.StatusTransitionedAt,
+		CancelledAt:          o.CancelledAt,
+		CancellationReason:   o.CancellationReason,
+		AdditionalCost:       o.AdditionalCost,
+		AdditionalCostNote:   o.AdditionalCostNote,
+		Customer: model.CustomerResponse{
+			ID:      o.CustomerID,
+			Name:    o.CustomerName,
+			Phone:   o.CustomerPhone,
+			Address: o.CustomerAddress,
+		},
+		OrderItems:                nil, // Omit order items in listings
+		TaxPercent:                &taxPercent,
+		TotalAmount:               &totalAmount,
+		PaidAmount:                &paidAmount,
+		OutstandingAmount:         &outstandingAmount,
+		ProductCount:              &productCount,
+		TotalProfitLoss:           &totalProfitLoss,
+		TotalProfitLossPercentage: &totalProfitLossPercentage,
+		TotalSalesRevenue:         o.TotalSalesRevenue,
+		TotalReturnedRevenue:      o.TotalReturnedRevenue,
+	}
+}
+
+// Helper to encode the position of the last order of a page into an opaque cursor
+func encodeOrderCursor(orderDate time.Time, id int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(orderDate.Format("2006-01-02") + "|" + strconv.Itoa(id)))
+}
+
+// Helper to decode a cursor produced by encodeOrderCursor
+func decodeOrderCursor(cursor string) (time.Time, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return time.Time{}, 0, errors.New("malformed cursor")
+	}
+	orderDate, err := time.Parse("2006-01-02", parts[0])
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	return orderDate, id, nil
+}
+
 func (s *OrderService) GetOne(ctx context.Context, id int) (model.GetOneOrderResponse, string) {
 	order, err := s.orderRepo.GetOneByIDQuery(ctx, id, nil)
 	if err != nil {
@@ -197,6 +372,12 @@ func (s *OrderService) GetOne(ctx context.Context, id int) (model.GetOneOrderRes
 		return model.GetOneOrderResponse{}, error_utils.ErrorCode.DB_DOWN
 	}
 
+	returnedQuantities, err := s.orderReturnItemRepo.GetReturnedQuantitiesByOrderIDQuery(ctx, order.ID, nil)
+	if err != nil {
+		log.Error("OrderService.GetOne Error fetching returned quantities: " + err.Error())
+		return model.GetOneOrderResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+
 	orderItemResponses := make([]model.OrderItemResponse, 0, len(orderItems))
 	totalOriginalCost := 0
 	totalProfitLoss := 0
@@ -243,6 +424,10 @@ func (s *OrderService) GetOne(ctx context.Context, id int) (model.GetOneOrderRes
 			Discount:      item.Discount,
 			FinalAmount:   finalAmount,
 			ExportFrom:    item.ExportFrom,
+			WarehouseID:   item.WarehouseID,
+			PriceSource:   item.PriceSource,
+			// Returned goods
+			ReturnedQuantity: returnedQuantities[item.ID],
 			// Profit/Loss fields
 			OriginalPrice:        &item.OriginalPrice,
 			ProfitLoss:           &profitLoss,
@@ -250,16 +435,19 @@ func (s *OrderService) GetOne(ctx context.Context, id int) (model.GetOneOrderRes
 		})
 	}
 
-	totalAmount, productCount := calculateOrderAmountsAndProductCount(orderItems)
-	totalAmount += order.AdditionalCost
-	totalAmount += int(float64(totalAmount) * float64(order.TaxPercent) / 100)
+	itemsAmount, productCount := calculateOrderAmountsAndProductCount(orderItems)
+	totalAmount := calculateOrderTotalAmount(order, itemsAmount)
 
-	// Use stored values for total order profit/loss
-	totalProfitLoss = order.TotalSalesRevenue - order.TotalOriginalCost + order.AdditionalCost
-	totalProfitLossPercentage := 0.0
-	if order.TotalOriginalCost > 0 {
-		totalProfitLossPercentage = float64(totalProfitLoss) / float64(order.TotalOriginalCost) * 100
+	paidAmounts, err := s.paymentRepo.GetTotalPaidByOrderIDsQuery(ctx, []int{order.ID}, nil)
+	if err != nil {
+		log.Error("OrderService.GetOne Error fetching payments: " + err.Error())
+		return model.GetOneOrderResponse{}, error_utils.ErrorCode.DB_DOWN
 	}
+	paidAmount := paidAmounts[order.ID]
+	outstandingAmount := totalAmount - paidAmount
+
+	// Use stored values for total order profit/loss
+	totalProfitLoss, totalProfitLossPercentage := calculateOrderProfitLoss(order)
 
 	// Fetch order images and generate signed URLs
 	orderImages, err := s.orderImageRepo.GetAllByOrderIDQuery(ctx, order.ID, nil)
@@ -296,6 +484,8 @@ func (s *OrderService) GetOne(ctx context.Context, id int) (model.GetOneOrderRes
 		DeliveryStatus:       order.DeliveryStatus,
 		DebtStatus:           order.DebtStatus,
 		StatusTransitionedAt: order.StatusTransitionedAt,
+		CancelledAt:          order.CancelledAt,
+		CancellationReason:   order.CancellationReason,
 		AdditionalCost:       order.AdditionalCost,
 		AdditionalCostNote:   order.AdditionalCostNote,
 		Customer: model.CustomerResponse{
@@ -304,15 +494,18 @@ func (s *OrderService) GetOne(ctx context.Context, id int) (model.GetOneOrderRes
 			Phone:   customer.Phone,
 			Address: customer.Address,
 		},
-		OrderItems:   orderItemResponses,
-		Images:       imageResponses,
-		TotalAmount:  &totalAmount,
-		ProductCount: &productCount,
-		TaxPercent:   &order.TaxPercent,
+		OrderItems:        orderItemResponses,
+		Images:            imageResponses,
+		TotalAmount:       &totalAmount,
+		PaidAmount:        &paidAmount,
+		OutstandingAmount: &outstandingAmount,
+		ProductCount:      &productCount,
+		TaxPercent:        &order.TaxPercent,
 		// Profit/Loss fields for total order
 		TotalProfitLoss:           &totalProfitLoss,
 		TotalProfitLossPercentage: &totalProfitLossPercentage,
 		TotalSalesRevenue:         order.TotalSalesRevenue,
+		TotalReturnedRevenue:      order.TotalReturnedRevenue,
 	}}
 	return resp, ""
 }
@@ -337,6 +530,20 @@ func (s *OrderService) Create(ctx *gin.Context, req model.CreateOrderRequest) st
 		return error_utils.ErrorCode.UNAUTHORIZED
 	}
 
+	if !entity.IsValidOrderDeliveryStatus(req.DeliveryStatus) || req.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+		log.Error("OrderService.Create Error: invalid delivery status " + req.DeliveryStatus)
+		return error_utils.ErrorCode.BAD_REQUEST
+	}
+
+	// Deleted customers keep their past orders but cannot place new ones
+	if _, errCode := getActiveCustomer(ctx, s.customerRepo, req.CustomerID, nil); errCode != "" {
+		return errCode
+	}
+
+	if errCode := s.resolveOrderItemWarehouses(ctx, req.OrderItems); errCode != "" {
+		return errCode
+	}
+
 	tx, err := s.unitOfWork.Begin(ctx)
 	if err != nil {
 		log.Error("OrderService.Create Error when begin transaction: " + err.Error())
@@ -353,22 +560,11 @@ func (s *OrderService) Create(ctx *gin.Context, req model.CreateOrderRequest) st
 		productIDs = append(productIDs, item.ProductID)
 	}
 
-	inventoryIDs, err := s.inventoryRepo.GetInventoryIDsByProductIDsQuery(ctx, productIDs, tx)
-	if err != nil {
-		log.Error("OrderService.Create Error when get inventory IDs: " + err.Error())
-		return error_utils.ErrorCode.DB_DOWN
-	}
-
-	lockedInventories, err := s.inventoryRepo.SelectManyForUpdate(ctx, inventoryIDs, tx)
+	inventoryMap, err := lockInventories(ctx, s.inventoryRepo, productIDs, tx)
 	if err != nil {
 		log.Error("OrderService.Create Error when lock inventories: " + err.Error())
 		return error_utils.ErrorCode.DB_DOWN
 	}
-	inventoryMap := make(map[int]*entity.Inventory)
-	for i := range lockedInventories {
-		inv := &lockedInventories[i]
-		inventoryMap[inv.ProductID] = inv
-	}
 
 	// Calculate total original cost and total sales revenue
 	totalOriginalCost, totalSalesRevenue, errCode := s.calculateOrderCostAndRevenue(ctx, req.OrderItems)
@@ -380,7 +576,6 @@ func (s *OrderService) Create(ctx *gin.Context, req model.CreateOrderRequest) st
 		CustomerID:         req.CustomerID,
 		OrderDate:          req.OrderDate,
 		DeliveryStatus:     req.DeliveryStatus,
-		DebtStatus:         req.DebtStatus,
 		TotalOriginalCost:  totalOriginalCost,
 		TotalSalesRevenue:  totalSalesRevenue,
 		AdditionalCost:     req.AdditionalCost,
@@ -389,6 +584,8 @@ func (s *OrderService) Create(ctx *gin.Context, req model.CreateOrderRequest) st
 	}
 	now := time.Now()
 	orderEntity.StatusTransitionedAt = &now
+	debtStatus := deriveOrderDebtStatus(calculateOrderTotalAmount(&orderEntity, totalSalesRevenue), 0)
+	orderEntity.DebtStatus = &debtStatus
 
 	err = s.orderRepo.CreateCommand(ctx, &orderEntity, tx)
 	if err != nil {
@@ -396,26 +593,41 @@ func (s *OrderService) Create(ctx *gin.Context, req model.CreateOrderRequest) st
 		return error_utils.ErrorCode.DB_DOWN
 	}
 
-	// Validate that each product has at most 2 order items (1 from inventory, 1 from external)
-	productOrderItemCount := make(map[int]map[string]int) // productID -> exportFrom -> count
-	for _, item := range req.OrderItems {
-		if productOrderItemCount[item.ProductID] == nil {
-			productOrderItemCount[item.ProductID] = make(map[string]int)
-		}
-		productOrderItemCount[item.ProductID][item.ExportFrom]++
+	// Record the initial delivery status of the order
+	err = s.statusHistoryRepo.CreateCommand(ctx, &entity.OrderStatusHistory{
+		OrderID:   orderEntity.ID,
+		ToStatus:  orderEntity.DeliveryStatus,
+		ChangedBy: user.ID,
+		ChangedAt: now,
+	}, tx)
+	if err != nil {
+		log.Error("OrderService.Create Error when create order status history: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
 
-		if productOrderItemCount[item.ProductID][item.ExportFrom] > 1 {
+	// Validate that each product has at most 1 order item per warehouse and 1 from external
+	requestedKeys := make(map[orderItemKey]struct{})
+	for _, item := range req.OrderItems {
+		key := newOrderItemKey(item.ProductID, item.ExportFrom, item.WarehouseID)
+		if _, exists := requestedKeys[key]; exists {
 			log.Error("OrderService.Create Error: product ", item.ProductID, " has more than 1 order item from ", item.ExportFrom)
 			return error_utils.ErrorCode.DUPLICATE_ORDER_ITEMS
 		}
+		requestedKeys[key] = struct{}{}
 	}
 
+	createdItems := make([]entity.OrderItem, 0, len(req.OrderItems))
 	for _, item := range req.OrderItems {
-		inv := inventoryMap[item.ProductID]
+		var inv *entity.Inventory
 		quantityToExport := item.Quantity
 
 		// Only check version for inventory items
 		if item.ExportFrom == entity.OrderExportFrom.INVENTORY {
+			inv = inventoryMap[inventoryKey{warehouseID: *item.WarehouseID, productID: item.ProductID}]
+			if inv == nil {
+				log.Error("OrderService.Create Error: inventory not found for productID ", item.ProductID, " in warehouse ", *item.WarehouseID)
+				return error_utils.ErrorCode.NOT_FOUND
+			}
 			itemVersion := item.Version
 			if inv.Version != itemVersion {
 				log.Error("OrderService.Create Error: inventory version mismatch for productID ", item.ProductID)
@@ -429,6 +641,13 @@ func (s *OrderService) Create(ctx *gin.Context, req model.CreateOrderRequest) st
 			return error_utils.ErrorCode.DB_DOWN
 		}
 
+		// Record whether the item was sold at the price list suggestion or at a manually typed price
+		suggestion, err := suggestPrice(ctx, s.productPriceRepo, s.customerDiscountRepo, orderEntity.CustomerID, item.ProductID, orderEntity.OrderDate, tx)
+		if err != nil {
+			log.Error("OrderService.Create Error when suggest price: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+
 		// Calculate final amount for the item
 		itemTotal := quantityToExport * item.SellingPrice
 		discountAmount := (itemTotal * item.Discount) / 100
@@ -440,53 +659,33 @@ func (s *OrderService) Create(ctx *gin.Context, req model.CreateOrderRequest) st
 			Spec:          item.Spec,
 			Quantity:      quantityToExport,
 			SellingPrice:  item.SellingPrice,
-			OriginalPrice: product.OriginalPrice,
+			OriginalPrice: product.AverageCost, // Snapshot the weighted-average cost at the time of sale
 			Discount:      item.Discount,
 			FinalAmount:   &finalAmount,
 			OrderID:       orderEntity.ID,
 			ExportFrom:    item.ExportFrom,
+			WarehouseID:   item.WarehouseID,
+			PriceSource:   priceSourceOf(suggestion, item.SellingPrice, item.Discount),
 		}
 
 		// Handle based on export source
 		if item.ExportFrom == entity.OrderExportFrom.INVENTORY {
-			// Check if inventory has enough quantity
-			if inv.Quantity < quantityToExport {
+			// Check if inventory has enough quantity that is not already promised to other pending orders
+			if availableQuantity(inv) < quantityToExport {
 				log.Error("OrderService.Create Error: inventory quantity exceeded for productID ", item.ProductID)
 				return error_utils.ErrorCode.INVENTORY_QUANTITY_EXCEEDED
 			}
 
-			// Update inventory quantity
-			newVersion := uuid.New().String()
-			err = s.inventoryRepo.UpdateQuantityWithVersionCommand(ctx, inv.ProductID, -quantityToExport, inv.Version, newVersion, tx)
-			if err != nil {
-				var constraintViolationError *error_utils.ConstraintViolationError
-				if errors.As(err, &constraintViolationError) {
-					log.Error("OrderService.Create Error: inventory quantity negative for productID ", item.ProductID)
-					return error_utils.ErrorCode.INVENTORY_QUANTITY_NEGATIVE
-				}
-				log.Error("OrderService.Create Error when update inventory: " + err.Error())
-				return error_utils.ErrorCode.DB_DOWN
+			// A pending order only reserves the goods, they are deducted once the order leaves PENDING
+			var errCode string
+			if orderEntity.DeliveryStatus == entity.OrderDeliveryStatus.PENDING {
+				errCode = reserveInventory(ctx, s.inventoryRepo, inv, quantityToExport, tx)
+			} else {
+				errCode = adjustInventory(ctx, s.inventoryRepo, s.inventoryHistoryRepo, inv, -quantityToExport, entity.InventoryMovementType.SALE, user.Username, "Hàng trừ cho hoá đơn ID: "+strconv.Itoa(orderEntity.ID), &orderEntity.ID, tx)
 			}
-
-			// Create inventory history record
-			inventoryHistory := &entity.InventoryHistory{
-				ProductID:     inv.ProductID,
-				Quantity:      -quantityToExport,
-				FinalQuantity: inv.Quantity - quantityToExport,
-				ImporterName:  user.Username,
-				ImportedAt:    time.Now(),
-				Note:          "Hàng trừ cho hoá đơn ID: " + strconv.Itoa(orderEntity.ID),
-				ReferenceID:   &orderEntity.ID,
-			}
-			err = s.inventoryHistoryRepo.CreateCommand(ctx, inventoryHistory, tx)
-			if err != nil {
-				log.Error("OrderService.Create Error when create inventory history: " + err.Error())
-				return error_utils.ErrorCode.DB_DOWN
+			if errCode != "" {
+				return errCode
 			}
-
-			// Update local inventory state
-			inv.Quantity -= quantityToExport
-			inv.Version = newVersion
 		} else if item.ExportFrom != entity.OrderExportFrom.EXTERNAL {
 			// Invalid export source
 			log.Error("OrderService.Create Error: invalid export_from value for productID ", item.ProductID)
@@ -500,6 +699,12 @@ func (s *OrderService) Create(ctx *gin.Context, req model.CreateOrderRequest) st
 			log.Error("OrderService.Create Error when create order item: " + err.Error())
 			return error_utils.ErrorCode.DB_DOWN
 		}
+		createdItems = append(createdItems, itemEntity)
+	}
+
+	after := orderAuditSnapshot{Order: orderEntity, OrderItems: createdItems}
+	if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.CREATE, entity.AuditEntityType.ORDER, orderEntity.ID, nil, after, tx); errCode != "" {
+		return errCode
 	}
 
 	err = s.unitOfWork.Commit(tx)
@@ -511,8 +716,19 @@ func (s *OrderService) Create(ctx *gin.Context, req model.CreateOrderRequest) st
 	return ""
 }
 
-func (s *OrderService) Update(ctx context.Context, req model.UpdateOrderRequest) string {
-	existing, err := s.orderRepo.GetOneByIDQuery(ctx, req.ID, nil)
+func (s *OrderService) Update(ctx *gin.Context, req model.UpdateOrderRequest) string {
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("OrderService.Update Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("OrderService.Update Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	existing, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, req.ID, tx)
 	if err != nil {
 		log.Error("OrderService.Update Error: " + err.Error())
 		return error_utils.ErrorCode.DB_DOWN
@@ -520,21 +736,20 @@ func (s *OrderService) Update(ctx context.Context, req model.UpdateOrderRequest)
 	if existing == nil {
 		return error_utils.ErrorCode.NOT_FOUND
 	}
+	if existing.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+		return error_utils.ErrorCode.ORDER_CANCELLED
+	}
+	before := orderAuditSnapshot{Order: *existing}
 
-	if req.CustomerID != 0 {
+	if req.CustomerID != 0 && req.CustomerID != existing.CustomerID {
+		if _, errCode := getActiveCustomer(ctx, s.customerRepo, req.CustomerID, tx); errCode != "" {
+			return errCode
+		}
 		existing.CustomerID = req.CustomerID
 	}
 	if !req.OrderDate.IsZero() {
 		existing.OrderDate = req.OrderDate
 	}
-	if req.DeliveryStatus != "" {
-		existing.DeliveryStatus = req.DeliveryStatus
-		now := time.Now()
-		existing.StatusTransitionedAt = &now
-	}
-	if req.DebtStatus != nil {
-		existing.DebtStatus = req.DebtStatus
-	}
 	if req.AdditionalCost != nil {
 		existing.AdditionalCost = *req.AdditionalCost
 	}
@@ -545,157 +760,728 @@ func (s *OrderService) Update(ctx context.Context, req model.UpdateOrderRequest)
 		existing.TaxPercent = *req.TaxPercent
 	}
 
-	err = s.orderRepo.UpdateCommand(ctx, existing, nil)
+	// Additional cost and tax change the total amount, so the debt status has to be derived again
+	orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, existing.ID, tx)
+	if err != nil {
+		log.Error("OrderService.Update Error when get order items: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	paidAmounts, err := s.paymentRepo.GetTotalPaidByOrderIDsQuery(ctx, []int{existing.ID}, tx)
+	if err != nil {
+		log.Error("OrderService.Update Error when get payments: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	itemsAmount, _ := calculateOrderAmountsAndProductCount(orderItems)
+	debtStatus := deriveOrderDebtStatus(calculateOrderTotalAmount(existing, itemsAmount), paidAmounts[existing.ID])
+	existing.DebtStatus = &debtStatus
+
+	err = s.orderRepo.UpdateCommand(ctx, existing, tx)
 	if err != nil {
 		log.Error("OrderService.Update Error when update order: " + err.Error())
 		return error_utils.ErrorCode.DB_DOWN
 	}
 
+	after := orderAuditSnapshot{Order: *existing}
+	if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.UPDATE, entity.AuditEntityType.ORDER, existing.ID, before, after, tx); errCode != "" {
+		return errCode
+	}
+
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("OrderService.Update Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
 	return ""
 }
 
-func (s *OrderService) Delete(ctx *gin.Context, id int) string {
+func (s *OrderService) UpdateItems(ctx *gin.Context, orderID int, req model.UpdateOrderItemsRequest) string {
 	// Get user ID from context
 	userID := middleware.GetUserIdHelper(ctx)
 	if userID == 0 {
-		log.Error("OrderService.Delete Error: user ID not found in context")
+		log.Error("OrderService.UpdateItems Error: user ID not found in context")
 		return error_utils.ErrorCode.UNAUTHORIZED
 	}
 
 	// Get user details to get username
 	user, err := s.userRepo.FindByIDQuery(ctx, int(userID), nil)
 	if err != nil {
-		log.Error("OrderService.Delete Error when get user: " + err.Error())
+		log.Error("OrderService.UpdateItems Error when get user: " + err.Error())
 		return error_utils.ErrorCode.DB_DOWN
 	}
 
 	if user == nil {
-		log.Error("OrderService.Delete Error: user not found")
+		log.Error("OrderService.UpdateItems Error: user not found")
 		return error_utils.ErrorCode.UNAUTHORIZED
 	}
 
-	// Check if order exists
-	order, err := s.orderRepo.GetOneByIDQuery(ctx, id, nil)
+	if len(req.OrderItems) == 0 {
+		log.Error("OrderService.UpdateItems Error: order must have at least one item")
+		return error_utils.ErrorCode.BAD_REQUEST
+	}
+
+	if errCode := s.resolveOrderItemWarehouses(ctx, req.OrderItems); errCode != "" {
+		return errCode
+	}
+
+	// Validate the requested items: valid export source and at most 1 item per product per warehouse or external
+	requestedKeys := make(map[orderItemKey]struct{})
+	for _, item := range req.OrderItems {
+		if item.ExportFrom != entity.OrderExportFrom.INVENTORY && item.ExportFrom != entity.OrderExportFrom.EXTERNAL {
+			log.Error("OrderService.UpdateItems Error: invalid export_from value for productID ", item.ProductID)
+			return error_utils.ErrorCode.BAD_REQUEST
+		}
+
+		key := newOrderItemKey(item.ProductID, item.ExportFrom, item.WarehouseID)
+		if _, exists := requestedKeys[key]; exists {
+			log.Error("OrderService.UpdateItems Error: product ", item.ProductID, " has more than 1 order item from ", item.ExportFrom)
+			return error_utils.ErrorCode.DUPLICATE_ORDER_ITEMS
+		}
+		requestedKeys[key] = struct{}{}
+	}
+
+	tx, err := s.unitOfWork.Begin(ctx)
 	if err != nil {
-		log.Error("OrderService.Delete Error when get order: " + err.Error())
+		log.Error("OrderService.UpdateItems Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("OrderService.UpdateItems Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	order, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when get order: " + err.Error())
 		return error_utils.ErrorCode.DB_DOWN
 	}
 	if order == nil {
 		return error_utils.ErrorCode.NOT_FOUND
 	}
+	if order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+		return error_utils.ErrorCode.ORDER_CANCELLED
+	}
 
-	// Get order items
-	orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, id, nil)
+	existingItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, orderID, tx)
 	if err != nil {
-		log.Error("OrderService.Delete Error when get order items: " + err.Error())
+		log.Error("OrderService.UpdateItems Error when get order items: " + err.Error())
 		return error_utils.ErrorCode.DB_DOWN
 	}
+	before := orderAuditSnapshot{Order: *order, OrderItems: existingItems}
+	existingItemMap := make(map[orderItemKey]entity.OrderItem)
+	for _, item := range existingItems {
+		existingItemMap[newOrderItemKey(item.ProductID, item.ExportFrom, item.WarehouseID)] = item
+	}
 
-	// Filter items that were exported from inventory
-	inventoryItems := make([]entity.OrderItem, 0)
-	for _, item := range orderItems {
+	// Items the customer already sent back cannot be removed or reduced below the returned quantity
+	returnedQuantities, err := s.orderReturnItemRepo.GetReturnedQuantitiesByOrderIDQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when get returned quantities: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	for _, item := range existingItems {
+		returnedQuantity := returnedQuantities[item.ID]
+		if returnedQuantity == 0 {
+			continue
+		}
+		key := newOrderItemKey(item.ProductID, item.ExportFrom, item.WarehouseID)
+		if _, ok := requestedKeys[key]; !ok {
+			log.Error("OrderService.UpdateItems Error: cannot remove order item ", item.ID, " that has returned goods")
+			return error_utils.ErrorCode.RETURN_QUANTITY_EXCEEDED
+		}
+	}
+	for _, item := range req.OrderItems {
+		existing, ok := existingItemMap[newOrderItemKey(item.ProductID, item.ExportFrom, item.WarehouseID)]
+		if ok && item.Quantity < returnedQuantities[existing.ID] {
+			log.Error("OrderService.UpdateItems Error: quantity of order item ", existing.ID, " is less than its returned quantity")
+			return error_utils.ErrorCode.RETURN_QUANTITY_EXCEEDED
+		}
+	}
+
+	// Calculate the inventory delta per warehouse and product, only INVENTORY items touch the stock.
+	// A positive delta means goods go back to inventory, a negative one means more goods are exported.
+	inventoryDelta := make(map[inventoryKey]int)
+	requestedInventoryItems := make(map[inventoryKey]model.OrderItemRequest)
+	for _, item := range existingItems {
+		if item.ExportFrom == entity.OrderExportFrom.INVENTORY && item.WarehouseID != nil {
+			inventoryDelta[inventoryKey{warehouseID: *item.WarehouseID, productID: item.ProductID}] += item.Quantity
+		}
+	}
+	for _, item := range req.OrderItems {
 		if item.ExportFrom == entity.OrderExportFrom.INVENTORY {
-			inventoryItems = append(inventoryItems, item)
+			key := inventoryKey{warehouseID: *item.WarehouseID, productID: item.ProductID}
+			inventoryDelta[key] -= item.Quantity
+			requestedInventoryItems[key] = item
+		}
+	}
+
+	changedDelta := make(map[inventoryKey]int)
+	productIDSet := make(map[int]struct{})
+	for key, delta := range inventoryDelta {
+		if delta != 0 {
+			changedDelta[key] = delta
+			productIDSet[key.productID] = struct{}{}
 		}
 	}
+	productIDs := make([]int, 0, len(productIDSet))
+	for productID := range productIDSet {
+		productIDs = append(productIDs, productID)
+	}
+
+	inventoryMap, err := lockInventories(ctx, s.inventoryRepo, productIDs, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when lock inventories: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
 
-	// If there are inventory items, we need to restore them
-	if len(inventoryItems) > 0 {
-		tx, err := s.unitOfWork.Begin(ctx)
+	for _, key := range sortedInventoryKeys(changedDelta) {
+		delta := changedDelta[key]
+		inv := inventoryMap[key]
+		if inv == nil {
+			log.Error("OrderService.UpdateItems Error: inventory not found for productID ", key.productID, " in warehouse ", key.warehouseID)
+			return error_utils.ErrorCode.NOT_FOUND
+		}
+
+		// Products still exported from inventory must be edited against the latest inventory version
+		if item, ok := requestedInventoryItems[key]; ok && inv.Version != item.Version {
+			log.Error("OrderService.UpdateItems Error: inventory version mismatch for productID ", key.productID)
+			return error_utils.ErrorCode.INVENTORY_VERSION_MISMATCH
+		}
+
+		if delta < 0 && availableQuantity(inv) < -delta {
+			log.Error("OrderService.UpdateItems Error: inventory quantity exceeded for productID ", key.productID)
+			return error_utils.ErrorCode.INVENTORY_QUANTITY_EXCEEDED
+		}
+
+		// A pending order holds reservations, a later one already had its goods deducted
+		var errCode string
+		if order.DeliveryStatus == entity.OrderDeliveryStatus.PENDING {
+			errCode = reserveInventory(ctx, s.inventoryRepo, inv, -delta, tx)
+		} else {
+			errCode = adjustInventory(ctx, s.inventoryRepo, s.inventoryHistoryRepo, inv, delta, entity.InventoryMovementType.SALE, user.Username, "Điều chỉnh hàng cho hoá đơn ID: "+strconv.Itoa(orderID), &orderID, tx)
+		}
+		if errCode != "" {
+			return errCode
+		}
+	}
+
+	// Remove items that are no longer in the order
+	for _, item := range existingItems {
+		if _, ok := requestedKeys[newOrderItemKey(item.ProductID, item.ExportFrom, item.WarehouseID)]; ok {
+			continue
+		}
+		err = s.orderItemRepo.DeleteByIDCommand(ctx, item.ID, tx)
 		if err != nil {
-			log.Error("OrderService.Delete Error when begin transaction: " + err.Error())
+			log.Error("OrderService.UpdateItems Error when delete order item: " + err.Error())
 			return error_utils.ErrorCode.DB_DOWN
 		}
-		defer func() {
-			if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
-				log.Error("OrderService.Delete Error when rollback transaction: " + rollbackErr.Error())
-			}
-		}()
+	}
 
-		// Get product IDs for inventory items
-		productIDs := make([]int, 0, len(inventoryItems))
-		for _, item := range inventoryItems {
-			productIDs = append(productIDs, item.ProductID)
-		}
+	// Update changed items and add the new ones
+	updatedItems := make([]entity.OrderItem, 0, len(req.OrderItems))
+	for _, item := range req.OrderItems {
+		itemTotal := item.Quantity * item.SellingPrice
+		discountAmount := (itemTotal * item.Discount) / 100
+		finalAmount := itemTotal - discountAmount
 
-		// Get inventory IDs and lock inventories
-		inventoryIDs, err := s.inventoryRepo.GetInventoryIDsByProductIDsQuery(ctx, productIDs, tx)
+		suggestion, err := suggestPrice(ctx, s.productPriceRepo, s.customerDiscountRepo, order.CustomerID, item.ProductID, order.OrderDate, tx)
 		if err != nil {
-			log.Error("OrderService.Delete Error when get inventory IDs: " + err.Error())
+			log.Error("OrderService.UpdateItems Error when suggest price: " + err.Error())
 			return error_utils.ErrorCode.DB_DOWN
 		}
+		priceSource := priceSourceOf(suggestion, item.SellingPrice, item.Discount)
+
+		if existing, ok := existingItemMap[newOrderItemKey(item.ProductID, item.ExportFrom, item.WarehouseID)]; ok {
+			// Keep the original price snapshot taken when the item was first sold
+			existing.NumberOfBoxes = item.NumberOfBoxes
+			existing.Spec = item.Spec
+			existing.Quantity = item.Quantity
+			existing.SellingPrice = item.SellingPrice
+			existing.Discount = item.Discount
+			existing.FinalAmount = &finalAmount
+			existing.PriceSource = priceSource
+
+			err = s.orderItemRepo.UpdateCommand(ctx, &existing, tx)
+			if err != nil {
+				log.Error("OrderService.UpdateItems Error when update order item: " + err.Error())
+				return error_utils.ErrorCode.DB_DOWN
+			}
+			updatedItems = append(updatedItems, existing)
+			continue
+		}
 
-		lockedInventories, err := s.inventoryRepo.SelectManyForUpdate(ctx, inventoryIDs, tx)
+		product, err := s.productRepo.GetOneByIDQuery(ctx, item.ProductID, tx)
 		if err != nil {
-			log.Error("OrderService.Delete Error when lock inventories: " + err.Error())
+			log.Error("OrderService.UpdateItems Error fetching product: " + err.Error())
 			return error_utils.ErrorCode.DB_DOWN
 		}
+		if product == nil {
+			log.Error("OrderService.UpdateItems Error: product not found for ID: ", item.ProductID)
+			return error_utils.ErrorCode.NOT_FOUND
+		}
 
-		// Create inventory map for easy lookup
-		inventoryMap := make(map[int]*entity.Inventory)
-		for i := range lockedInventories {
-			inv := &lockedInventories[i]
-			inventoryMap[inv.ProductID] = inv
+		itemEntity := entity.OrderItem{
+			ProductID:     item.ProductID,
+			NumberOfBoxes: item.NumberOfBoxes,
+			Spec:          item.Spec,
+			Quantity:      item.Quantity,
+			SellingPrice:  item.SellingPrice,
+			OriginalPrice: product.AverageCost, // Snapshot the weighted-average cost at the time of sale
+			Discount:      item.Discount,
+			FinalAmount:   &finalAmount,
+			OrderID:       orderID,
+			ExportFrom:    item.ExportFrom,
+			WarehouseID:   item.WarehouseID,
+			PriceSource:   priceSource,
+		}
+		err = s.orderItemRepo.CreateCommand(ctx, &itemEntity, tx)
+		if err != nil {
+			log.Error("OrderService.UpdateItems Error when create order item: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
 		}
+		updatedItems = append(updatedItems, itemEntity)
+	}
 
-		// Restore inventory quantities
-		for _, item := range inventoryItems {
-			inv := inventoryMap[item.ProductID]
-			if inv == nil {
-				log.Error("OrderService.Delete Error: inventory not found for productID ", item.ProductID)
-				return error_utils.ErrorCode.DB_DOWN
-			}
+	// Recalculate stored cost and revenue of the order, and the debt status since the total amount changed
+	order.TotalOriginalCost, order.TotalSalesRevenue = calculateOrderItemsCostAndRevenue(updatedItems)
+	paidAmounts, err := s.paymentRepo.GetTotalPaidByOrderIDsQuery(ctx, []int{orderID}, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when get payments: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	itemsAmount, _ := calculateOrderAmountsAndProductCount(updatedItems)
+	debtStatus := deriveOrderDebtStatus(calculateOrderTotalAmount(order, itemsAmount), paidAmounts[orderID])
+	order.DebtStatus = &debtStatus
+	err = s.orderRepo.UpdateCommand(ctx, order, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when update order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
 
-			quantityToRestore := item.Quantity
-			newVersion := uuid.New().String()
+	after := orderAuditSnapshot{Order: *order, OrderItems: updatedItems}
+	if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.UPDATE, entity.AuditEntityType.ORDER, orderID, before, after, tx); errCode != "" {
+		return errCode
+	}
 
-			// Update inventory quantity
-			err = s.inventoryRepo.UpdateQuantityWithVersionCommand(ctx, inv.ProductID, quantityToRestore, inv.Version, newVersion, tx)
-			if err != nil {
-				log.Error("OrderService.Delete Error when update inventory: " + err.Error())
-				return error_utils.ErrorCode.DB_DOWN
-			}
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
 
-			// Create inventory history record for restoration
-			inventoryHistory := &entity.InventoryHistory{
-				ProductID:     inv.ProductID,
-				Quantity:      quantityToRestore,
-				FinalQuantity: inv.Quantity + quantityToRestore,
-				ImporterName:  user.Username,
-				ImportedAt:    time.Now(),
-				Note:          "Hồi hàng về từ đơn xoá số " + strconv.Itoa(id),
-			}
-			err = s.inventoryHistoryRepo.CreateCommand(ctx, inventoryHistory, tx)
-			if err != nil {
-				log.Error("OrderService.Delete Error when create inventory history: " + err.Error())
-				return error_utils.ErrorCode.DB_DOWN
-			}
+	return ""
+}
+
+func (s *OrderService) UpdateStatus(ctx *gin.Context, orderID int, req model.UpdateOrderStatusRequest) string {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("OrderService.UpdateStatus Error: user ID not found in context")
+		return error_utils.ErrorCode.UNAUTHORIZED
+	}
 
-			// Update local inventory state
-			inv.Quantity += quantityToRestore
-			inv.Version = newVersion
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("OrderService.UpdateStatus Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("OrderService.UpdateStatus Error when rollback transaction: " + rollbackErr.Error())
 		}
+	}()
+
+	// Lock the order so two concurrent transitions cannot both pass the check
+	order, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateStatus Error when get order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	if !entity.CanTransitionOrderDeliveryStatus(order.DeliveryStatus, req.DeliveryStatus) {
+		log.Error("OrderService.UpdateStatus Error: cannot transition order ", orderID, " from ", order.DeliveryStatus, " to ", req.DeliveryStatus)
+		return error_utils.ErrorCode.ORDER_STATUS_TRANSITION_INVALID
+	}
 
-		// Delete the order
-		err = s.orderRepo.DeleteByIDCommand(ctx, id, tx)
+	// Goods reserved while the order was pending leave the inventory once it moves on
+	if order.DeliveryStatus == entity.OrderDeliveryStatus.PENDING {
+		user, err := s.userRepo.FindByIDQuery(ctx, int(userID), tx)
 		if err != nil {
-			log.Error("OrderService.Delete Error when delete order: " + err.Error())
+			log.Error("OrderService.UpdateStatus Error when get user: " + err.Error())
 			return error_utils.ErrorCode.DB_DOWN
 		}
+		if user == nil {
+			log.Error("OrderService.UpdateStatus Error: user not found")
+			return error_utils.ErrorCode.UNAUTHORIZED
+		}
 
-		// Commit transaction
-		err = s.unitOfWork.Commit(tx)
+		orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, orderID, tx)
 		if err != nil {
-			log.Error("OrderService.Delete Error when commit transaction: " + err.Error())
+			log.Error("OrderService.UpdateStatus Error when get order items: " + err.Error())
 			return error_utils.ErrorCode.DB_DOWN
 		}
-	} else {
-		// Delete the order
-		err = s.orderRepo.DeleteByIDCommand(ctx, id, nil)
-		if err != nil {
-			log.Error("OrderService.Delete Error when delete order: " + err.Error())
+
+		if errCode := s.settleOrderReservations(ctx, orderID, orderItems, user.Username, true, tx); errCode != "" {
+			return errCode
+		}
+	}
+
+	before := orderAuditSnapshot{Order: *order}
+	fromStatus := order.DeliveryStatus
+	now := time.Now()
+	order.DeliveryStatus = req.DeliveryStatus
+	order.StatusTransitionedAt = &now
+
+	err = s.orderRepo.UpdateCommand(ctx, order, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateStatus Error when update order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	err = s.statusHistoryRepo.CreateCommand(ctx, &entity.OrderStatusHistory{
+		OrderID:    orderID,
+		FromStatus: &fromStatus,
+		ToStatus:   req.DeliveryStatus,
+		ChangedBy:  int(userID),
+		ChangedAt:  now,
+		Note:       req.Note,
+	}, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateStatus Error when create order status history: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	after := orderAuditSnapshot{Order: *order}
+	if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.UPDATE, entity.AuditEntityType.ORDER, orderID, before, after, tx); errCode != "" {
+		return errCode
+	}
+
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("OrderService.UpdateStatus Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+func (s *OrderService) GetStatusHistory(ctx context.Context, orderID int) (model.GetAllOrderStatusHistoriesResponse, string) {
+	order, err := s.orderRepo.GetOneByIDQuery(ctx, orderID, nil)
+	if err != nil {
+		log.Error("OrderService.GetStatusHistory Error when get order: " + err.Error())
+		return model.GetAllOrderStatusHistoriesResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return model.GetAllOrderStatusHistoriesResponse{}, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	histories, err := s.statusHistoryRepo.GetAllByOrderIDQuery(ctx, orderID, nil)
+	if err != nil {
+		log.Error("OrderService.GetStatusHistory Error when get status histories: " + err.Error())
+		return model.GetAllOrderStatusHistoriesResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+
+	resp := model.GetAllOrderStatusHistoriesResponse{StatusHistories: make([]model.OrderStatusHistoryResponse, len(histories))}
+	for i, history := range histories {
+		resp.StatusHistories[i] = model.OrderStatusHistoryResponse{
+			ID:            history.ID,
+			OrderID:       history.OrderID,
+			FromStatus:    history.FromStatus,
+			ToStatus:      history.ToStatus,
+			ChangedBy:     history.ChangedBy,
+			ChangedByName: history.ChangedByName,
+			ChangedAt:     history.ChangedAt,
+			Note:          history.Note,
+		}
+	}
+
+	return resp, ""
+}
+
+func (s *OrderService) Cancel(ctx *gin.Context, orderID int, req model.CancelOrderRequest) string {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("OrderService.Cancel Error: user ID not found in context")
+		return error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Get user details to get username
+	user, err := s.userRepo.FindByIDQuery(ctx, int(userID), nil)
+	if err != nil {
+		log.Error("OrderService.Cancel Error when get user: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	if user == nil {
+		log.Error("OrderService.Cancel Error: user not found")
+		return error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("OrderService.Cancel Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("OrderService.Cancel Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock the order so it cannot be cancelled twice concurrently
+	order, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("OrderService.Cancel Error when get order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+	if order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+		return error_utils.ErrorCode.ORDER_CANCELLED
+	}
+	if !entity.CanCancelOrder(order.DeliveryStatus) {
+		log.Error("OrderService.Cancel Error: cannot cancel order ", orderID, " in status ", order.DeliveryStatus)
+		return error_utils.ErrorCode.ORDER_STATUS_TRANSITION_INVALID
+	}
+
+	orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("OrderService.Cancel Error when get order items: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	errCode := s.restoreOrderInventory(ctx, order, orderItems, user.Username, "Hồi hàng về từ đơn huỷ số "+strconv.Itoa(orderID), tx)
+	if errCode != "" {
+		return errCode
+	}
+
+	before := orderAuditSnapshot{Order: *order}
+	fromStatus := order.DeliveryStatus
+	now := time.Now()
+	order.DeliveryStatus = entity.OrderDeliveryStatus.CANCELLED
+	order.StatusTransitionedAt = &now
+	order.CancelledAt = &now
+	order.CancellationReason = &req.Reason
+
+	err = s.orderRepo.UpdateCommand(ctx, order, tx)
+	if err != nil {
+		log.Error("OrderService.Cancel Error when update order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	err = s.statusHistoryRepo.CreateCommand(ctx, &entity.OrderStatusHistory{
+		OrderID:    orderID,
+		FromStatus: &fromStatus,
+		ToStatus:   entity.OrderDeliveryStatus.CANCELLED,
+		ChangedBy:  user.ID,
+		ChangedAt:  now,
+		Note:       &req.Reason,
+	}, tx)
+	if err != nil {
+		log.Error("OrderService.Cancel Error when create order status history: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	after := orderAuditSnapshot{Order: *order}
+	if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.UPDATE, entity.AuditEntityType.ORDER, orderID, before, after, tx); errCode != "" {
+		return errCode
+	}
+
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("OrderService.Cancel Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+func (s *OrderService) Delete(ctx *gin.Context, id int) string {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("OrderService.Delete Error: user ID not found in context")
+		return error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Get user details to get username
+	user, err := s.userRepo.FindByIDQuery(ctx, int(userID), nil)
+	if err != nil {
+		log.Error("OrderService.Delete Error when get user: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	if user == nil {
+		log.Error("OrderService.Delete Error: user not found")
+		return error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("OrderService.Delete Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("OrderService.Delete Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Check if order exists
+	order, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("OrderService.Delete Error when get order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("OrderService.Delete Error when get order items: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// A cancelled order already gave its goods back to inventory
+	if order.DeliveryStatus != entity.OrderDeliveryStatus.CANCELLED {
+		errCode := s.restoreOrderInventory(ctx, order, orderItems, user.Username, "Hồi hàng về từ đơn xoá số "+strconv.Itoa(id), tx)
+		if errCode != "" {
+			return errCode
+		}
+	}
+
+	// Delete the order
+	err = s.orderRepo.DeleteByIDCommand(ctx, id, tx)
+	if err != nil {
+		log.Error("OrderService.Delete Error when delete order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	before := orderAuditSnapshot{Order: *order, OrderItems: orderItems}
+	if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.DELETE, entity.AuditEntityType.ORDER, id, before, nil, tx); errCode != "" {
+		return errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("OrderService.Delete Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+// Helper to give back the goods of an order that is cancelled or deleted: a pending order releases its reservations,
+// a later one puts the items exported from inventory back, writing an inventory history row that references the order
+func (s *OrderService) restoreOrderInventory(ctx context.Context, order *entity.Order, orderItems []entity.OrderItem, username string, note string, tx *sqlx.Tx) string {
+	if order.DeliveryStatus == entity.OrderDeliveryStatus.PENDING {
+		return s.settleOrderReservations(ctx, order.ID, orderItems, username, false, tx)
+	}
+
+	// Goods the customer already returned were restocked at return time
+	returnedQuantities, err := s.orderReturnItemRepo.GetReturnedQuantitiesByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.restoreOrderInventory Error when get returned quantities: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	quantityToRestore := orderInventoryQuantities(orderItems, returnedQuantities)
+	if len(quantityToRestore) == 0 {
+		return ""
+	}
+
+	return restockProducts(ctx, s.inventoryRepo, s.inventoryHistoryRepo, quantityToRestore, entity.InventoryMovementType.SALE, username, note, order.ID, tx)
+}
+
+// Helper to end the reservations of a pending order: when the order leaves PENDING the reserved goods are deducted
+// from inventory with a history row, when it is cancelled or deleted they are simply released
+func (s *OrderService) settleOrderReservations(ctx context.Context, orderID int, orderItems []entity.OrderItem, username string, deduct bool, tx *sqlx.Tx) string {
+	reservedQuantities := orderInventoryQuantities(orderItems, nil)
+	if len(reservedQuantities) == 0 {
+		return ""
+	}
+
+	productIDs := make([]int, 0, len(reservedQuantities))
+	for key := range reservedQuantities {
+		productIDs = append(productIDs, key.productID)
+	}
+	inventoryMap, err := lockInventories(ctx, s.inventoryRepo, productIDs, tx)
+	if err != nil {
+		log.Error("OrderService.settleOrderReservations Error when lock inventories: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	for _, key := range sortedInventoryKeys(reservedQuantities) {
+		inv := inventoryMap[key]
+		if inv == nil {
+			log.Error("OrderService.settleOrderReservations Error: inventory not found for productID ", key.productID, " in warehouse ", key.warehouseID)
+			return error_utils.ErrorCode.DB_DOWN
+		}
+
+		quantity := reservedQuantities[key]
+		if errCode := reserveInventory(ctx, s.inventoryRepo, inv, -quantity, tx); errCode != "" {
+			return errCode
+		}
+		if deduct {
+			errCode := adjustInventory(ctx, s.inventoryRepo, s.inventoryHistoryRepo, inv, -quantity, entity.InventoryMovementType.SALE, username, "Hàng trừ cho hoá đơn ID: "+strconv.Itoa(orderID), &orderID, tx)
+			if errCode != "" {
+				return errCode
+			}
+		}
+	}
+
+	return ""
+}
+
+// Helper to sum the quantities of the items exported from inventory per warehouse and product, less what was returned
+func orderInventoryQuantities(orderItems []entity.OrderItem, returnedQuantities map[int]int) map[inventoryKey]int {
+	quantities := make(map[inventoryKey]int)
+	for _, item := range orderItems {
+		quantity := item.Quantity - returnedQuantities[item.ID]
+		if item.ExportFrom == entity.OrderExportFrom.INVENTORY && item.WarehouseID != nil && quantity > 0 {
+			quantities[inventoryKey{warehouseID: *item.WarehouseID, productID: item.ProductID}] += quantity
+		}
+	}
+	return quantities
+}
+
+// Helper to put goods back into inventory: locks the inventories of the products in inventory ID order, bumps
+// their version and writes an inventory history row referencing the given document for each warehouse and product
+func restockProducts(ctx context.Context, inventoryRepo repository.InventoryRepository, inventoryHistoryRepo repository.InventoryHistoryRepository, quantityToRestore map[inventoryKey]int, movementType string, username string, note string, referenceID int, tx *sqlx.Tx) string {
+	productIDSet := make(map[int]struct{})
+	for key := range quantityToRestore {
+		productIDSet[key.productID] = struct{}{}
+	}
+	productIDs := make([]int, 0, len(productIDSet))
+	for productID := range productIDSet {
+		productIDs = append(productIDs, productID)
+	}
+
+	inventoryMap, err := lockInventories(ctx, inventoryRepo, productIDs, tx)
+	if err != nil {
+		log.Error("restockProducts Error when lock inventories: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	for _, key := range sortedInventoryKeys(quantityToRestore) {
+		inv := inventoryMap[key]
+		if inv == nil {
+			log.Error("restockProducts Error: inventory not found for productID ", key.productID, " in warehouse ", key.warehouseID)
 			return error_utils.ErrorCode.DB_DOWN
 		}
+
+		errCode := adjustInventory(ctx, inventoryRepo, inventoryHistoryRepo, inv, quantityToRestore[key], movementType, username, note, &referenceID, tx)
+		if errCode != "" {
+			return errCode
+		}
 	}
 
 	return ""
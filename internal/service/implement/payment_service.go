@@ -0,0 +1,308 @@
+package serviceimplement
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type PaymentService struct {
+	paymentRepo   repository.PaymentRepository
+	orderRepo     repository.OrderRepository
+	orderItemRepo repository.OrderItemRepository
+	customerRepo  repository.CustomerRepository
+	unitOfWork    repository.UnitOfWork
+}
+
+func NewPaymentService(
+	paymentRepo repository.PaymentRepository,
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	customerRepo repository.CustomerRepository,
+	unitOfWork repository.UnitOfWork,
+) service.PaymentService {
+	return &PaymentService{
+		paymentRepo:   paymentRepo,
+		orderRepo:     orderRepo,
+		orderItemRepo: orderItemRepo,
+		customerRepo:  customerRepo,
+		unitOfWork:    unitOfWork,
+	}
+}
+
+func (s *PaymentService) Create(ctx *gin.Context, orderID int, request model.CreatePaymentRequest) (*model.PaymentResponse, string) {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("PaymentService.Create Error: user ID not found in context")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	if request.Amount <= 0 {
+		log.Error("PaymentService.Create Error: payment amount must be positive")
+		return nil, error_utils.ErrorCode.BAD_REQUEST
+	}
+	if request.Method != entity.PaymentMethod.CASH && request.Method != entity.PaymentMethod.BANK_TRANSFER && request.Method != entity.PaymentMethod.OTHER {
+		log.Error("PaymentService.Create Error: invalid payment method " + request.Method)
+		return nil, error_utils.ErrorCode.BAD_REQUEST
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PaymentService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock the order so concurrent payments cannot overpay it
+	order, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when get order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+		return nil, error_utils.ErrorCode.ORDER_CANCELLED
+	}
+
+	totalAmount, paidAmount, errCode := s.getOrderTotalAndPaidAmount(ctx, order, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	if request.Amount > totalAmount-paidAmount {
+		log.Error("PaymentService.Create Error: payment amount exceeds outstanding amount of order ", orderID)
+		return nil, error_utils.ErrorCode.PAYMENT_AMOUNT_EXCEEDED
+	}
+
+	payment := &entity.Payment{
+		OrderID:    orderID,
+		Amount:     request.Amount,
+		Method:     request.Method,
+		PaidAt:     request.PaidAt,
+		Note:       request.Note,
+		RecordedBy: int(userID),
+	}
+	err = s.paymentRepo.CreateCommand(ctx, payment, tx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when create payment: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	err = s.orderRepo.UpdateDebtStatusCommand(ctx, orderID, deriveOrderDebtStatus(totalAmount, paidAmount+request.Amount), tx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when update debt status: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	createdPayment, err := s.paymentRepo.GetOneByIDQuery(ctx, payment.ID, nil)
+	if err != nil || createdPayment == nil {
+		log.Error("PaymentService.Create Error when get created payment")
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toPaymentResponse(*createdPayment)
+	return &response, ""
+}
+
+func (s *PaymentService) GetAllByOrderID(ctx *gin.Context, orderID int) (*model.GetAllPaymentsResponse, string) {
+	order, err := s.orderRepo.GetOneByIDQuery(ctx, orderID, nil)
+	if err != nil {
+		log.Error("PaymentService.GetAllByOrderID Error when get order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	payments, err := s.paymentRepo.GetAllByOrderIDQuery(ctx, orderID, nil)
+	if err != nil {
+		log.Error("PaymentService.GetAllByOrderID Error when get payments: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	totalAmount, paidAmount, errCode := s.getOrderTotalAndPaidAmount(ctx, order, nil)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	paymentResponses := make([]model.PaymentResponse, len(payments))
+	for i, payment := range payments {
+		paymentResponses[i] = toPaymentResponse(payment)
+	}
+
+	return &model.GetAllPaymentsResponse{
+		TotalAmount:       totalAmount,
+		PaidAmount:        paidAmount,
+		OutstandingAmount: totalAmount - paidAmount,
+		DebtStatus:        deriveOrderDebtStatus(totalAmount, paidAmount),
+		Payments:          paymentResponses,
+	}, ""
+}
+
+func (s *PaymentService) Delete(ctx *gin.Context, orderID int, paymentID int) string {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PaymentService.Delete Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PaymentService.Delete Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	order, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("PaymentService.Delete Error when get order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	payment, err := s.paymentRepo.GetOneByIDQuery(ctx, paymentID, tx)
+	if err != nil {
+		log.Error("PaymentService.Delete Error when get payment: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if payment == nil || payment.OrderID != orderID {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	err = s.paymentRepo.DeleteByIDCommand(ctx, paymentID, tx)
+	if err != nil {
+		log.Error("PaymentService.Delete Error when delete payment: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	totalAmount, paidAmount, errCode := s.getOrderTotalAndPaidAmount(ctx, order, tx)
+	if errCode != "" {
+		return errCode
+	}
+
+	err = s.orderRepo.UpdateDebtStatusCommand(ctx, orderID, deriveOrderDebtStatus(totalAmount, paidAmount), tx)
+	if err != nil {
+		log.Error("PaymentService.Delete Error when update debt status: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PaymentService.Delete Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+func (s *PaymentService) GetCustomerBalance(ctx *gin.Context, customerID int) (*model.CustomerBalanceResponse, string) {
+	customer, err := s.customerRepo.GetOneByIDQuery(ctx, customerID, nil)
+	if err != nil {
+		log.Error("PaymentService.GetCustomerBalance Error when get customer: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if customer == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	orders, err := s.orderRepo.GetAllWithFiltersQuery(ctx, repository.OrderFilter{CustomerID: customerID, SortBy: "order_date_asc"}, nil)
+	if err != nil {
+		log.Error("PaymentService.GetCustomerBalance Error when get orders: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := &model.CustomerBalanceResponse{
+		Customer: model.CustomerResponse{
+			ID:      customer.ID,
+			Name:    customer.Name,
+			Phone:   customer.Phone,
+			Address: customer.Address,
+		},
+		OpenOrders: make([]model.OrderBalanceResponse, 0),
+	}
+	for _, order := range orders {
+		// Cancelled orders are no longer owed by the customer
+		if order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+			continue
+		}
+
+		totalAmount := order.TotalAmount
+		paidAmount := order.PaidAmount
+
+		response.TotalAmount += totalAmount
+		response.PaidAmount += paidAmount
+		response.OutstandingAmount += totalAmount - paidAmount
+
+		if paidAmount < totalAmount {
+			response.OpenOrders = append(response.OpenOrders, model.OrderBalanceResponse{
+				OrderID:           order.ID,
+				OrderDate:         order.OrderDate,
+				DeliveryStatus:    order.DeliveryStatus,
+				DebtStatus:        deriveOrderDebtStatus(totalAmount, paidAmount),
+				TotalAmount:       totalAmount,
+				PaidAmount:        paidAmount,
+				OutstandingAmount: totalAmount - paidAmount,
+			})
+		}
+	}
+
+	return response, ""
+}
+
+// Helper to get the total amount of an order and how much of it has been paid
+func (s *PaymentService) getOrderTotalAndPaidAmount(ctx *gin.Context, order *entity.Order, tx *sqlx.Tx) (totalAmount int, paidAmount int, errCode string) {
+	orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("PaymentService.getOrderTotalAndPaidAmount Error when get order items: " + err.Error())
+		return 0, 0, error_utils.ErrorCode.DB_DOWN
+	}
+	paidAmounts, err := s.paymentRepo.GetTotalPaidByOrderIDsQuery(ctx, []int{order.ID}, tx)
+	if err != nil {
+		log.Error("PaymentService.getOrderTotalAndPaidAmount Error when get payments: " + err.Error())
+		return 0, 0, error_utils.ErrorCode.DB_DOWN
+	}
+
+	itemsAmount, _ := calculateOrderAmountsAndProductCount(orderItems)
+	return calculateOrderTotalAmount(order, itemsAmount), paidAmounts[order.ID], ""
+}
+
+func toPaymentResponse(payment entity.Payment) model.PaymentResponse {
+	return model.PaymentResponse{
+		ID:             payment.ID,
+		OrderID:        payment.OrderID,
+		Amount:         payment.Amount,
+		Method:         payment.Method,
+		PaidAt:         payment.PaidAt,
+		Note:           payment.Note,
+		RecordedBy:     payment.RecordedBy,
+		RecordedByName: payment.RecordedByName,
+		CreatedAt:      payment.CreatedAt,
+	}
+}
@@ -0,0 +1,463 @@
+package serviceimplement
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type PriceListService struct {
+	productPriceRepo     repository.ProductPriceRepository
+	customerDiscountRepo repository.CustomerDiscountRepository
+	productRepo          repository.ProductRepository
+	customerRepo         repository.CustomerRepository
+	auditLogRepo         repository.AuditLogRepository
+	unitOfWork           repository.UnitOfWork
+}
+
+func NewPriceListService(
+	productPriceRepo repository.ProductPriceRepository,
+	customerDiscountRepo repository.CustomerDiscountRepository,
+	productRepo repository.ProductRepository,
+	customerRepo repository.CustomerRepository,
+	auditLogRepo repository.AuditLogRepository,
+	unitOfWork repository.UnitOfWork,
+) service.PriceListService {
+	return &PriceListService{
+		productPriceRepo:     productPriceRepo,
+		customerDiscountRepo: customerDiscountRepo,
+		productRepo:          productRepo,
+		customerRepo:         customerRepo,
+		auditLogRepo:         auditLogRepo,
+		unitOfWork:           unitOfWork,
+	}
+}
+
+func (s *PriceListService) CreateProductPrice(ctx *gin.Context, request model.CreateProductPriceRequest) (*model.ProductPriceResponse, string) {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("PriceListService.CreateProductPrice Error: user ID not found in context")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	product, err := s.productRepo.GetOneByIDQuery(ctx, request.ProductID, nil)
+	if err != nil {
+		log.Error("PriceListService.CreateProductPrice Error when get product: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if product == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	if request.CustomerID != nil {
+		if _, errCode := getActiveCustomer(ctx, s.customerRepo, *request.CustomerID, nil); errCode != "" {
+			return nil, errCode
+		}
+	}
+
+	effectiveFrom := priceListEffectiveDate(request.EffectiveFrom)
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PriceListService.CreateProductPrice Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PriceListService.CreateProductPrice Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// A product has at most one price per customer starting on the same day
+	existingPrice, err := s.productPriceRepo.GetOneByEffectiveFromQuery(ctx, request.ProductID, request.CustomerID, effectiveFrom, tx)
+	if err != nil {
+		log.Error("PriceListService.CreateProductPrice Error when get existing product price: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if existingPrice != nil {
+		return nil, error_utils.ErrorCode.PRICE_LIST_ENTRY_EXISTS
+	}
+
+	productPrice := &entity.ProductPrice{
+		ProductID:     request.ProductID,
+		CustomerID:    request.CustomerID,
+		SellingPrice:  *request.SellingPrice,
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     int(userID),
+	}
+	err = s.productPriceRepo.CreateCommand(ctx, productPrice, tx)
+	if err != nil {
+		// Another request added an entry for the same day since the check above
+		var constraintViolationError *error_utils.ConstraintViolationError
+		if errors.As(err, &constraintViolationError) {
+			return nil, error_utils.ErrorCode.PRICE_LIST_ENTRY_EXISTS
+		}
+		log.Error("PriceListService.CreateProductPrice Error when create product price: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.CREATE, entity.AuditEntityType.PRODUCT_PRICE, productPrice.ID, nil, productPrice, tx); errCode != "" {
+		return nil, errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PriceListService.CreateProductPrice Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	createdPrice, err := s.productPriceRepo.GetOneByIDQuery(ctx, productPrice.ID, nil)
+	if err != nil {
+		log.Error("PriceListService.CreateProductPrice Error when get product price: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if createdPrice == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	response := toProductPriceResponse(*createdPrice)
+	return &response, ""
+}
+
+func (s *PriceListService) GetAllProductPrices(ctx *gin.Context, productID int, customerID int) (*model.GetAllProductPricesResponse, string) {
+	productPrices, err := s.productPriceRepo.GetAllWithFiltersQuery(ctx, productID, customerID, nil)
+	if err != nil {
+		log.Error("PriceListService.GetAllProductPrices Error when get product prices: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	productPriceResponses := make([]model.ProductPriceResponse, len(productPrices))
+	for i, productPrice := range productPrices {
+		productPriceResponses[i] = toProductPriceResponse(productPrice)
+	}
+
+	return &model.GetAllProductPricesResponse{
+		ProductPrices: productPriceResponses,
+	}, ""
+}
+
+func (s *PriceListService) DeleteProductPrice(ctx *gin.Context, id int) string {
+	productPrice, err := s.productPriceRepo.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("PriceListService.DeleteProductPrice Error when get product price: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if productPrice == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PriceListService.DeleteProductPrice Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PriceListService.DeleteProductPrice Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	err = s.productPriceRepo.DeleteByIDCommand(ctx, id, tx)
+	if err != nil {
+		log.Error("PriceListService.DeleteProductPrice Error when delete product price: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.DELETE, entity.AuditEntityType.PRODUCT_PRICE, id, productPrice, nil, tx); errCode != "" {
+		return errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PriceListService.DeleteProductPrice Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+func (s *PriceListService) CreateCustomerDiscount(ctx *gin.Context, request model.CreateCustomerDiscountRequest) (*model.CustomerDiscountResponse, string) {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("PriceListService.CreateCustomerDiscount Error: user ID not found in context")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	if _, errCode := getActiveCustomer(ctx, s.customerRepo, request.CustomerID, nil); errCode != "" {
+		return nil, errCode
+	}
+
+	effectiveFrom := priceListEffectiveDate(request.EffectiveFrom)
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PriceListService.CreateCustomerDiscount Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PriceListService.CreateCustomerDiscount Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	existingDiscount, err := s.customerDiscountRepo.GetOneByEffectiveFromQuery(ctx, request.CustomerID, effectiveFrom, tx)
+	if err != nil {
+		log.Error("PriceListService.CreateCustomerDiscount Error when get existing customer discount: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if existingDiscount != nil {
+		return nil, error_utils.ErrorCode.PRICE_LIST_ENTRY_EXISTS
+	}
+
+	customerDiscount := &entity.CustomerDiscount{
+		CustomerID:      request.CustomerID,
+		DiscountPercent: *request.DiscountPercent,
+		EffectiveFrom:   effectiveFrom,
+		CreatedBy:       int(userID),
+	}
+	err = s.customerDiscountRepo.CreateCommand(ctx, customerDiscount, tx)
+	if err != nil {
+		// Another request added an entry for the same day since the check above
+		var constraintViolationError *error_utils.ConstraintViolationError
+		if errors.As(err, &constraintViolationError) {
+			return nil, error_utils.ErrorCode.PRICE_LIST_ENTRY_EXISTS
+		}
+		log.Error("PriceListService.CreateCustomerDiscount Error when create customer discount: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.CREATE, entity.AuditEntityType.CUSTOMER_DISCOUNT, customerDiscount.ID, nil, customerDiscount, tx); errCode != "" {
+		return nil, errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PriceListService.CreateCustomerDiscount Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	createdDiscount, err := s.customerDiscountRepo.GetOneByIDQuery(ctx, customerDiscount.ID, nil)
+	if err != nil {
+		log.Error("PriceListService.CreateCustomerDiscount Error when get customer discount: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if createdDiscount == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	response := toCustomerDiscountResponse(*createdDiscount)
+	return &response, ""
+}
+
+func (s *PriceListService) GetAllCustomerDiscounts(ctx *gin.Context, customerID int) (*model.GetAllCustomerDiscountsResponse, string) {
+	customerDiscounts, err := s.customerDiscountRepo.GetAllByCustomerIDQuery(ctx, customerID, nil)
+	if err != nil {
+		log.Error("PriceListService.GetAllCustomerDiscounts Error when get customer discounts: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	customerDiscountResponses := make([]model.CustomerDiscountResponse, len(customerDiscounts))
+	for i, customerDiscount := range customerDiscounts {
+		customerDiscountResponses[i] = toCustomerDiscountResponse(customerDiscount)
+	}
+
+	return &model.GetAllCustomerDiscountsResponse{
+		CustomerDiscounts: customerDiscountResponses,
+	}, ""
+}
+
+func (s *PriceListService) DeleteCustomerDiscount(ctx *gin.Context, id int) string {
+	customerDiscount, err := s.customerDiscountRepo.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("PriceListService.DeleteCustomerDiscount Error when get customer discount: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if customerDiscount == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PriceListService.DeleteCustomerDiscount Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PriceListService.DeleteCustomerDiscount Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	err = s.customerDiscountRepo.DeleteByIDCommand(ctx, id, tx)
+	if err != nil {
+		log.Error("PriceListService.DeleteCustomerDiscount Error when delete customer discount: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	if errCode := recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.DELETE, entity.AuditEntityType.CUSTOMER_DISCOUNT, id, customerDiscount, nil, tx); errCode != "" {
+		return errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PriceListService.DeleteCustomerDiscount Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+func (s *PriceListService) GetSuggestedPrice(ctx *gin.Context, customerID int, productID int, date time.Time) (*model.SuggestedPriceResponse, string) {
+	product, err := s.productRepo.GetOneByIDQuery(ctx, productID, nil)
+	if err != nil {
+		log.Error("PriceListService.GetSuggestedPrice Error when get product: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if product == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	if _, errCode := getActiveCustomer(ctx, s.customerRepo, customerID, nil); errCode != "" {
+		return nil, errCode
+	}
+
+	suggestion, err := suggestPrice(ctx, s.productPriceRepo, s.customerDiscountRepo, customerID, productID, date, nil)
+	if err != nil {
+		log.Error("PriceListService.GetSuggestedPrice Error when suggest price: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := &model.SuggestedPriceResponse{
+		CustomerID: customerID,
+		ProductID:  productID,
+		Date:       date,
+		Discount:   suggestion.discount,
+		PriceType:  suggestion.priceType,
+	}
+	if suggestion.productPrice != nil {
+		response.SellingPrice = &suggestion.productPrice.SellingPrice
+		response.EffectiveFrom = &suggestion.productPrice.EffectiveFrom
+	}
+	return response, ""
+}
+
+// priceSuggestion is the list price and discount a customer gets for a product on a given day
+type priceSuggestion struct {
+	productPrice *entity.ProductPrice // nil when the product has no price yet
+	discount     int
+	priceType    string
+}
+
+// suggestPrice looks up the price list for a (customer, product) pair. A customer override is the
+// negotiated price and is suggested as is; otherwise the default price goes with the customer's default discount
+func suggestPrice(
+	ctx context.Context,
+	productPriceRepo repository.ProductPriceRepository,
+	customerDiscountRepo repository.CustomerDiscountRepository,
+	customerID int,
+	productID int,
+	date time.Time,
+	tx *sqlx.Tx,
+) (*priceSuggestion, error) {
+	customerPrice, err := productPriceRepo.GetEffectiveQuery(ctx, productID, &customerID, date, tx)
+	if err != nil {
+		return nil, err
+	}
+	if customerPrice != nil {
+		return &priceSuggestion{
+			productPrice: customerPrice,
+			priceType:    entity.ProductPriceType.CUSTOMER_PRICE,
+		}, nil
+	}
+
+	defaultPrice, err := productPriceRepo.GetEffectiveQuery(ctx, productID, nil, date, tx)
+	if err != nil {
+		return nil, err
+	}
+	if defaultPrice == nil {
+		return &priceSuggestion{priceType: entity.ProductPriceType.NONE}, nil
+	}
+
+	suggestion := &priceSuggestion{
+		productPrice: defaultPrice,
+		priceType:    entity.ProductPriceType.DEFAULT_PRICE,
+	}
+	customerDiscount, err := customerDiscountRepo.GetEffectiveQuery(ctx, customerID, date, tx)
+	if err != nil {
+		return nil, err
+	}
+	if customerDiscount != nil {
+		suggestion.discount = customerDiscount.DiscountPercent
+	}
+	return suggestion, nil
+}
+
+// priceSourceOf tells whether an order item was sold at the suggested list price or typed in by hand
+func priceSourceOf(suggestion *priceSuggestion, sellingPrice int, discount int) string {
+	if suggestion.productPrice != nil && suggestion.productPrice.SellingPrice == sellingPrice && suggestion.discount == discount {
+		return entity.OrderItemPriceSource.LIST
+	}
+	return entity.OrderItemPriceSource.MANUAL
+}
+
+// priceListEffectiveDate drops the time of day, price list entries take effect from the start of a day
+func priceListEffectiveDate(effectiveFrom *time.Time) time.Time {
+	date := time.Now()
+	if effectiveFrom != nil {
+		date = *effectiveFrom
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func toProductPriceResponse(productPrice entity.ProductPrice) model.ProductPriceResponse {
+	return model.ProductPriceResponse{
+		ID:            productPrice.ID,
+		ProductID:     productPrice.ProductID,
+		ProductName:   productPrice.ProductName,
+		CustomerID:    productPrice.CustomerID,
+		CustomerName:  productPrice.CustomerName,
+		SellingPrice:  productPrice.SellingPrice,
+		EffectiveFrom: productPrice.EffectiveFrom,
+		CreatedByName: productPrice.CreatedByName,
+		CreatedAt:     productPrice.CreatedAt,
+	}
+}
+
+func toCustomerDiscountResponse(customerDiscount entity.CustomerDiscount) model.CustomerDiscountResponse {
+	return model.CustomerDiscountResponse{
+		ID:              customerDiscount.ID,
+		CustomerID:      customerDiscount.CustomerID,
+		CustomerName:    customerDiscount.CustomerName,
+		DiscountPercent: customerDiscount.DiscountPercent,
+		EffectiveFrom:   customerDiscount.EffectiveFrom,
+		CreatedByName:   customerDiscount.CreatedByName,
+		CreatedAt:       customerDiscount.CreatedAt,
+	}
+}
@@ -1,8 +1,12 @@
 package serviceimplement
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
 	"github.com/pna/order-app-backend/internal/domain/entity"
 	"github.com/pna/order-app-backend/internal/domain/model"
 	"github.com/pna/order-app-backend/internal/repository"
@@ -12,16 +16,22 @@ import (
 )
 
 type ProductService struct {
-	productRepository   repository.ProductRepository
-	inventoryRepository repository.InventoryRepository
-	unitOfWork          repository.UnitOfWork
+	productRepository            repository.ProductRepository
+	inventoryRepository          repository.InventoryRepository
+	productCostHistoryRepository repository.ProductCostHistoryRepository
+	warehouseRepository          repository.WarehouseRepository
+	auditLogRepository           repository.AuditLogRepository
+	unitOfWork                   repository.UnitOfWork
 }
 
-func NewProductService(productRepository repository.ProductRepository, inventoryRepository repository.InventoryRepository, unitOfWork repository.UnitOfWork) service.ProductService {
+func NewProductService(productRepository repository.ProductRepository, inventoryRepository repository.InventoryRepository, productCostHistoryRepository repository.ProductCostHistoryRepository, warehouseRepository repository.WarehouseRepository, auditLogRepository repository.AuditLogRepository, unitOfWork repository.UnitOfWork) service.ProductService {
 	return &ProductService{
-		productRepository:   productRepository,
-		inventoryRepository: inventoryRepository,
-		unitOfWork:          unitOfWork,
+		productRepository:            productRepository,
+		inventoryRepository:          inventoryRepository,
+		productCostHistoryRepository: productCostHistoryRepository,
+		warehouseRepository:          warehouseRepository,
+		auditLogRepository:           auditLogRepository,
+		unitOfWork:                   unitOfWork,
 	}
 }
 
@@ -40,11 +50,17 @@ func (s *ProductService) Create(ctx *gin.Context, request model.CreateProductReq
 		}
 	}()
 
-	// Create product entity
+	// Create product entity, its average cost starts from the original price until stock is received
 	product := &entity.Product{
-		Name:          request.Name,
-		Spec:          request.Spec,
-		OriginalPrice: request.OriginalPrice,
+		Name:            request.Name,
+		Spec:            request.Spec,
+		OriginalPrice:   request.OriginalPrice,
+		AverageCost:     request.OriginalPrice,
+		MinStock:        entity.DefaultProductMinStock,
+		ReorderQuantity: request.ReorderQuantity,
+	}
+	if request.MinStock != nil {
+		product.MinStock = *request.MinStock
 	}
 
 	// Save product to database
@@ -54,19 +70,40 @@ func (s *ProductService) Create(ctx *gin.Context, request model.CreateProductReq
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
-	// Create inventory for the product
-	inventory := &entity.Inventory{
-		ProductID: product.ID,
-		Quantity:  0, // Start with 0 quantity
-		Version:   uuid.New().String(),
-	}
-
-	err = s.inventoryRepository.CreateCommand(ctx, inventory, tx)
+	// Create an empty inventory for the product in every warehouse
+	warehouses, err := s.warehouseRepository.GetAllQuery(ctx, tx)
 	if err != nil {
-		log.Error("ProductService.Create Error when create inventory: " + err.Error())
+		log.Error("ProductService.Create Error when get warehouses: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
+	defaultWarehouseID := 0
+	inventories := make([]entity.Inventory, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		inventory := entity.Inventory{
+			WarehouseID:   warehouse.ID,
+			WarehouseName: warehouse.Name,
+			ProductID:     product.ID,
+			Quantity:      0, // Start with 0 quantity
+			Version:       uuid.New().String(),
+		}
+
+		err = s.inventoryRepository.CreateCommand(ctx, &inventory, tx)
+		if err != nil {
+			log.Error("ProductService.Create Error when create inventory: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		inventories = append(inventories, inventory)
+
+		if warehouse.IsDefault && defaultWarehouseID == 0 {
+			defaultWarehouseID = warehouse.ID
+		}
+	}
+
+	if errCode := recordAuditLog(ctx, s.auditLogRepository, entity.AuditAction.CREATE, entity.AuditEntityType.PRODUCT, product.ID, nil, product, tx); errCode != "" {
+		return nil, errCode
+	}
+
 	// Commit transaction
 	err = s.unitOfWork.Commit(tx)
 	if err != nil {
@@ -75,16 +112,8 @@ func (s *ProductService) Create(ctx *gin.Context, request model.CreateProductReq
 	}
 
 	// Return response with inventory info
-	return &model.ProductResponse{
-		ID:            product.ID,
-		Name:          product.Name,
-		Spec:          product.Spec,
-		OriginalPrice: product.OriginalPrice,
-		Inventory: &model.InventoryInfo{
-			Quantity: inventory.Quantity,
-			Version:  inventory.Version,
-		},
-	}, ""
+	response := toProductResponse(*product, inventories, defaultWarehouseID)
+	return &response, ""
 }
 
 func (s *ProductService) Update(ctx *gin.Context, request model.UpdateProductRequest) (*model.ProductResponse, string) {
@@ -99,45 +128,74 @@ func (s *ProductService) Update(ctx *gin.Context, request model.UpdateProductReq
 		return nil, error_utils.ErrorCode.NOT_FOUND
 	}
 
-	// Update product entity
+	// Update product entity, the average cost is only moved by receiving stock
 	product := &entity.Product{
-		ID:            request.ID,
-		Name:          request.Name,
-		Spec:          request.Spec,
-		OriginalPrice: request.OriginalPrice,
+		ID:              request.ID,
+		Name:            request.Name,
+		Spec:            request.Spec,
+		OriginalPrice:   request.OriginalPrice,
+		AverageCost:     existingProduct.AverageCost,
+		MinStock:        existingProduct.MinStock,
+		ReorderQuantity: existingProduct.ReorderQuantity,
+	}
+	if request.MinStock != nil {
+		product.MinStock = *request.MinStock
+	}
+	if request.ReorderQuantity != nil {
+		product.ReorderQuantity = *request.ReorderQuantity
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("ProductService.Update Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("ProductService.Update Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
 	// Save to database
-	err = s.productRepository.UpdateCommand(ctx, product, nil)
+	err = s.productRepository.UpdateCommand(ctx, product, tx)
 	if err != nil {
 		log.Error("ProductService.Update Error when update product: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
+	if errCode := recordAuditLog(ctx, s.auditLogRepository, entity.AuditAction.UPDATE, entity.AuditEntityType.PRODUCT, product.ID, existingProduct, product, tx); errCode != "" {
+		return nil, errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("ProductService.Update Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
 	// Get inventory info for response
-	inventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, product.ID, nil)
+	defaultWarehouseID, err := s.getDefaultWarehouseID(ctx)
+	if err != nil {
+		log.Error("ProductService.Update Error when get default warehouse: " + err.Error())
+		// Don't fail the update, just return without inventory info
+		response := toProductResponse(*product, nil, 0)
+		return &response, ""
+	}
+	inventories, err := s.inventoryRepository.GetAllByProductIDQuery(ctx, product.ID, nil)
 	if err != nil {
 		log.Error("ProductService.Update Error when get inventory: " + err.Error())
 		// Don't fail the update, just return without inventory info
-		return &model.ProductResponse{
-			ID:            product.ID,
-			Name:          product.Name,
-			Spec:          product.Spec,
-			OriginalPrice: product.OriginalPrice,
-		}, ""
+		response := toProductResponse(*product, nil, 0)
+		return &response, ""
 	}
 
 	// Return response with inventory info
-	return &model.ProductResponse{
-		ID:            product.ID,
-		Name:          product.Name,
-		Spec:          product.Spec,
-		OriginalPrice: product.OriginalPrice,
-		Inventory: &model.InventoryInfo{
-			Quantity: inventory.Quantity,
-			Version:  inventory.Version,
-		},
-	}, ""
+	response := toProductResponse(*product, inventories, defaultWarehouseID)
+	return &response, ""
 }
 
 func (s *ProductService) GetAll(ctx *gin.Context) (*model.GetAllProductsResponse, string) {
@@ -148,35 +206,29 @@ func (s *ProductService) GetAll(ctx *gin.Context) (*model.GetAllProductsResponse
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
-	// Convert to response models with inventory info
-	productResponses := make([]model.ProductResponse, len(products))
-	for i, product := range products {
-		// Get inventory for this product
-		inventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, product.ID, nil)
+	// Get the inventories of all products in all warehouses, grouped by product
+	inventoriesByProduct := make(map[int][]entity.Inventory)
+	defaultWarehouseID, err := s.getDefaultWarehouseID(ctx)
+	if err != nil {
+		log.Error("ProductService.GetAll Error when get default warehouse: " + err.Error())
+		// Continue without inventory info
+	} else {
+		inventories, err := s.inventoryRepository.GetAllQuery(ctx, 0, nil)
 		if err != nil {
-			log.Error("ProductService.GetAll Error when get inventory for product " + string(rune(product.ID)) + ": " + err.Error())
-			// Continue without inventory info for this product
-			productResponses[i] = model.ProductResponse{
-				ID:            product.ID,
-				Name:          product.Name,
-				Spec:          product.Spec,
-				OriginalPrice: product.OriginalPrice,
-			}
-			continue
+			log.Error("ProductService.GetAll Error when get inventories: " + err.Error())
+			// Continue without inventory info
 		}
-
-		productResponses[i] = model.ProductResponse{
-			ID:            product.ID,
-			Name:          product.Name,
-			Spec:          product.Spec,
-			OriginalPrice: product.OriginalPrice,
-			Inventory: &model.InventoryInfo{
-				Quantity: inventory.Quantity,
-				Version:  inventory.Version,
-			},
+		for _, inventory := range inventories {
+			inventoriesByProduct[inventory.ProductID] = append(inventoriesByProduct[inventory.ProductID], inventory)
 		}
 	}
 
+	// Convert to response models with inventory info
+	productResponses := make([]model.ProductResponse, len(products))
+	for i, product := range products {
+		productResponses[i] = toProductResponse(product, inventoriesByProduct[product.ID], defaultWarehouseID)
+	}
+
 	return &model.GetAllProductsResponse{
 		Products: productResponses,
 	}, ""
@@ -194,32 +246,183 @@ func (s *ProductService) GetOne(ctx *gin.Context, id int) (*model.GetOneProductR
 		return nil, error_utils.ErrorCode.NOT_FOUND
 	}
 
-	// Get inventory for this product
-	inventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, product.ID, nil)
+	// Get inventories for this product
+	defaultWarehouseID, err := s.getDefaultWarehouseID(ctx)
+	if err != nil {
+		log.Error("ProductService.GetOne Error when get default warehouse: " + err.Error())
+		// Return product without inventory info
+		return &model.GetOneProductResponse{Product: toProductResponse(*product, nil, 0)}, ""
+	}
+	inventories, err := s.inventoryRepository.GetAllByProductIDQuery(ctx, product.ID, nil)
 	if err != nil {
 		log.Error("ProductService.GetOne Error when get inventory: " + err.Error())
 		// Return product without inventory info
-		return &model.GetOneProductResponse{
-			Product: model.ProductResponse{
-				ID:            product.ID,
-				Name:          product.Name,
-				Spec:          product.Spec,
-				OriginalPrice: product.OriginalPrice,
-			},
-		}, ""
+		return &model.GetOneProductResponse{Product: toProductResponse(*product, nil, 0)}, ""
 	}
 
 	// Return response with inventory info
 	return &model.GetOneProductResponse{
-		Product: model.ProductResponse{
-			ID:            product.ID,
-			Name:          product.Name,
-			Spec:          product.Spec,
-			OriginalPrice: product.OriginalPrice,
-			Inventory: &model.InventoryInfo{
-				Quantity: inventory.Quantity,
-				Version:  inventory.Version,
-			},
-		},
+		Product: toProductResponse(*product, inventories, defaultWarehouseID),
 	}, ""
 }
+
+// Helper to get the ID of the default warehouse, whose stock is reported as the product's inventory
+func (s *ProductService) getDefaultWarehouseID(ctx *gin.Context) (int, error) {
+	warehouse, err := s.warehouseRepository.GetDefaultQuery(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	if warehouse == nil {
+		return 0, nil
+	}
+	return warehouse.ID, nil
+}
+
+// Helper to build a product response: Inventory holds the stock of the default warehouse and Inventories the stock of every warehouse
+func toProductResponse(product entity.Product, inventories []entity.Inventory, defaultWarehouseID int) model.ProductResponse {
+	response := model.ProductResponse{
+		ID:              product.ID,
+		Name:            product.Name,
+		Spec:            product.Spec,
+		OriginalPrice:   &product.OriginalPrice,
+		AverageCost:     &product.AverageCost,
+		MinStock:        product.MinStock,
+		ReorderQuantity: product.ReorderQuantity,
+	}
+	if len(inventories) == 0 {
+		return response
+	}
+
+	response.Inventories = make([]model.WarehouseInventoryInfo, len(inventories))
+	for i, inventory := range inventories {
+		response.Inventories[i] = model.WarehouseInventoryInfo{
+			WarehouseID:       inventory.WarehouseID,
+			WarehouseName:     inventory.WarehouseName,
+			Quantity:          inventory.Quantity,
+			ReservedQuantity:  inventory.ReservedQuantity,
+			AvailableQuantity: availableQuantity(&inventory),
+			Version:           inventory.Version,
+		}
+		if inventory.WarehouseID == defaultWarehouseID {
+			response.Inventory = &model.InventoryInfo{
+				Quantity:          inventory.Quantity,
+				ReservedQuantity:  inventory.ReservedQuantity,
+				AvailableQuantity: availableQuantity(&inventory),
+				Version:           inventory.Version,
+			}
+		}
+	}
+	return response
+}
+
+func (s *ProductService) GetCostHistories(ctx *gin.Context, productID int) (*model.GetProductCostHistoriesResponse, string) {
+	product, err := s.productRepository.GetOneByIDQuery(ctx, productID, nil)
+	if err != nil {
+		log.Error("ProductService.GetCostHistories Error when get product: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	if product == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	histories, err := s.productCostHistoryRepository.GetAllByProductIDQuery(ctx, productID, nil)
+	if err != nil {
+		log.Error("ProductService.GetCostHistories Error when get cost histories: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	historyResponses := make([]model.ProductCostHistoryResponse, len(histories))
+	for i, history := range histories {
+		historyResponses[i] = model.ProductCostHistoryResponse{
+			ID:                  history.ID,
+			Source:              history.Source,
+			ReferenceID:         history.ReferenceID,
+			QuantityBefore:      history.QuantityBefore,
+			ReceivedQuantity:    history.ReceivedQuantity,
+			UnitCost:            history.UnitCost,
+			PreviousAverageCost: history.PreviousAverageCost,
+			AverageCost:         history.AverageCost,
+			CreatedBy:           history.CreatedBy,
+			CreatedByName:       history.CreatedByName,
+			CreatedAt:           history.CreatedAt,
+		}
+	}
+
+	return &model.GetProductCostHistoriesResponse{
+		ProductID:   product.ID,
+		AverageCost: product.AverageCost,
+		Histories:   historyResponses,
+	}, ""
+}
+
+// Helper to fold stock received at unitCost into the product's moving weighted-average cost and record the change.
+// It must run in the receiving transaction after the inventory has been increased: the quantity read back already
+// includes the receipt, and the inventory row lock taken by the caller keeps concurrent receipts in order.
+func applyReceivedCost(ctx *gin.Context, productRepository repository.ProductRepository, inventoryRepository repository.InventoryRepository, productCostHistoryRepository repository.ProductCostHistoryRepository, productID int, receivedQuantity int, unitCost int, source string, referenceID *int, tx *sqlx.Tx) string {
+	product, err := productRepository.GetOneByIDForUpdateQuery(ctx, productID, tx)
+	if err != nil {
+		log.Error("applyReceivedCost Error when get product: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if product == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	// The average cost is shared by all warehouses, so it weighs the stock held in all of them
+	inventories, err := inventoryRepository.GetAllByProductIDQuery(ctx, productID, tx)
+	if err != nil {
+		log.Error("applyReceivedCost Error when get inventories: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if len(inventories) == 0 {
+		log.Error("applyReceivedCost Error: inventory not found for productID ", productID)
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	totalQuantity := 0
+	for _, inventory := range inventories {
+		totalQuantity += inventory.Quantity
+	}
+
+	quantityBefore := totalQuantity - receivedQuantity
+	averageCost := weightedAverageCost(quantityBefore, product.AverageCost, receivedQuantity, unitCost)
+	if averageCost != product.AverageCost {
+		err = productRepository.UpdateAverageCostCommand(ctx, productID, averageCost, tx)
+		if err != nil {
+			log.Error("applyReceivedCost Error when update average cost: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	history := &entity.ProductCostHistory{
+		ProductID:           productID,
+		Source:              source,
+		ReferenceID:         referenceID,
+		QuantityBefore:      quantityBefore,
+		ReceivedQuantity:    receivedQuantity,
+		UnitCost:            unitCost,
+		PreviousAverageCost: product.AverageCost,
+		AverageCost:         averageCost,
+		CreatedAt:           time.Now(),
+	}
+	if userID := int(middleware.GetUserIdHelper(ctx)); userID != 0 {
+		history.CreatedBy = &userID
+	}
+	err = productCostHistoryRepository.CreateCommand(ctx, history, tx)
+	if err != nil {
+		log.Error("applyReceivedCost Error when create cost history: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	return ""
+}
+
+// Helper to blend the cost of stock on hand with newly received stock, rounded to the nearest dong.
+// Stock that was empty carries no cost, so the new average is simply the unit cost of the receipt.
+func weightedAverageCost(quantityBefore int, averageCost int, receivedQuantity int, unitCost int) int {
+	if quantityBefore <= 0 {
+		return unitCost
+	}
+	totalQuantity := quantityBefore + receivedQuantity
+	totalCost := quantityBefore*averageCost + receivedQuantity*unitCost
+	return (totalCost + totalQuantity/2) / totalQuantity
+}
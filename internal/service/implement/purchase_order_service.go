@@ -0,0 +1,449 @@
+package serviceimplement
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type PurchaseOrderService struct {
+	purchaseOrderRepo      repository.PurchaseOrderRepository
+	purchaseOrderItemRepo  repository.PurchaseOrderItemRepository
+	supplierRepo           repository.SupplierRepository
+	productRepo            repository.ProductRepository
+	inventoryRepo          repository.InventoryRepository
+	inventoryHistoryRepo   repository.InventoryHistoryRepository
+	productCostHistoryRepo repository.ProductCostHistoryRepository
+	userRepo               repository.UserRepository
+	warehouseRepo          repository.WarehouseRepository
+	auditLogRepo           repository.AuditLogRepository
+	unitOfWork             repository.UnitOfWork
+}
+
+func NewPurchaseOrderService(
+	purchaseOrderRepo repository.PurchaseOrderRepository,
+	purchaseOrderItemRepo repository.PurchaseOrderItemRepository,
+	supplierRepo repository.SupplierRepository,
+	productRepo repository.ProductRepository,
+	inventoryRepo repository.InventoryRepository,
+	inventoryHistoryRepo repository.InventoryHistoryRepository,
+	productCostHistoryRepo repository.ProductCostHistoryRepository,
+	userRepo repository.UserRepository,
+	warehouseRepo repository.WarehouseRepository,
+	auditLogRepo repository.AuditLogRepository,
+	unitOfWork repository.UnitOfWork,
+) service.PurchaseOrderService {
+	return &PurchaseOrderService{
+		purchaseOrderRepo:      purchaseOrderRepo,
+		purchaseOrderItemRepo:  purchaseOrderItemRepo,
+		supplierRepo:           supplierRepo,
+		productRepo:            productRepo,
+		inventoryRepo:          inventoryRepo,
+		inventoryHistoryRepo:   inventoryHistoryRepo,
+		productCostHistoryRepo: productCostHistoryRepo,
+		userRepo:               userRepo,
+		warehouseRepo:          warehouseRepo,
+		auditLogRepo:           auditLogRepo,
+		unitOfWork:             unitOfWork,
+	}
+}
+
+func (s *PurchaseOrderService) Create(ctx *gin.Context, request model.CreatePurchaseOrderRequest) (*model.PurchaseOrderResponse, string) {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("PurchaseOrderService.Create Error: user ID not found in context")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Each product may appear at most once in a purchase order
+	productIDs := make(map[int]struct{})
+	for _, item := range request.Items {
+		if _, exists := productIDs[item.ProductID]; exists {
+			log.Error("PurchaseOrderService.Create Error: product ", item.ProductID, " appears more than once")
+			return nil, error_utils.ErrorCode.BAD_REQUEST
+		}
+		productIDs[item.ProductID] = struct{}{}
+	}
+
+	supplier, err := s.supplierRepo.GetOneByIDQuery(ctx, request.SupplierID, nil)
+	if err != nil {
+		log.Error("PurchaseOrderService.Create Error when get supplier: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if supplier == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	warehouseID, errCode := resolveWarehouseID(ctx, s.warehouseRepo, request.WarehouseID, nil)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PurchaseOrderService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	purchaseOrder := &entity.PurchaseOrder{
+		SupplierID:   supplier.ID,
+		WarehouseID:  warehouseID,
+		Status:       entity.PurchaseOrderStatus.PENDING,
+		ExpectedDate: request.ExpectedDate,
+		Note:         request.Note,
+		CreatedBy:    int(userID),
+	}
+	err = s.purchaseOrderRepo.CreateCommand(ctx, purchaseOrder, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Create Error when create purchase order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	for _, item := range request.Items {
+		product, err := s.productRepo.GetOneByIDQuery(ctx, item.ProductID, tx)
+		if err != nil {
+			log.Error("PurchaseOrderService.Create Error when get product: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if product == nil {
+			log.Error("PurchaseOrderService.Create Error: product not found for ID: ", item.ProductID)
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+
+		err = s.purchaseOrderItemRepo.CreateCommand(ctx, &entity.PurchaseOrderItem{
+			PurchaseOrderID: purchaseOrder.ID,
+			ProductID:       item.ProductID,
+			Quantity:        item.Quantity,
+			PurchasePrice:   item.PurchasePrice,
+		}, tx)
+		if err != nil {
+			log.Error("PurchaseOrderService.Create Error when create purchase order item: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return s.GetOne(ctx, purchaseOrder.ID)
+}
+
+func (s *PurchaseOrderService) GetAll(ctx *gin.Context, supplierID int, statuses []string) (*model.GetAllPurchaseOrdersResponse, string) {
+	purchaseOrders, err := s.purchaseOrderRepo.GetAllWithFiltersQuery(ctx, supplierID, statuses, nil)
+	if err != nil {
+		log.Error("PurchaseOrderService.GetAll Error when get purchase orders: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	purchaseOrderResponses := make([]model.PurchaseOrderResponse, len(purchaseOrders))
+	for i, purchaseOrder := range purchaseOrders {
+		purchaseOrderResponses[i] = toPurchaseOrderResponse(purchaseOrder, nil)
+	}
+
+	return &model.GetAllPurchaseOrdersResponse{
+		PurchaseOrders: purchaseOrderResponses,
+	}, ""
+}
+
+func (s *PurchaseOrderService) GetOne(ctx *gin.Context, id int) (*model.PurchaseOrderResponse, string) {
+	purchaseOrder, err := s.purchaseOrderRepo.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("PurchaseOrderService.GetOne Error when get purchase order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if purchaseOrder == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	items, err := s.purchaseOrderItemRepo.GetAllByPurchaseOrderIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("PurchaseOrderService.GetOne Error when get purchase order items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toPurchaseOrderResponse(*purchaseOrder, items)
+	return &response, ""
+}
+
+func (s *PurchaseOrderService) Receive(ctx *gin.Context, id int, request model.ReceivePurchaseOrderRequest) (*model.PurchaseOrderResponse, string) {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("PurchaseOrderService.Receive Error: user ID not found in context")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Get user details to get username
+	user, err := s.userRepo.FindByIDQuery(ctx, int(userID), nil)
+	if err != nil {
+		log.Error("PurchaseOrderService.Receive Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		log.Error("PurchaseOrderService.Receive Error: user not found")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Original prices are cost figures, so only roles that can see them may overwrite them
+	if request.UpdateOriginalPrice && !middleware.CanViewCostHelper(ctx) {
+		return nil, error_utils.ErrorCode.FORBIDDEN
+	}
+
+	// Each purchase order item may appear at most once in a receipt
+	requestedQuantities := make(map[int]int)
+	for _, item := range request.Items {
+		if _, exists := requestedQuantities[item.PurchaseOrderItemID]; exists {
+			log.Error("PurchaseOrderService.Receive Error: purchase order item ", item.PurchaseOrderItemID, " appears more than once")
+			return nil, error_utils.ErrorCode.BAD_REQUEST
+		}
+		requestedQuantities[item.PurchaseOrderItemID] = item.Quantity
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Receive Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PurchaseOrderService.Receive Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock the purchase order so concurrent receipts cannot exceed what was ordered
+	purchaseOrder, err := s.purchaseOrderRepo.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Receive Error when get purchase order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if purchaseOrder == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if !entity.CanReceivePurchaseOrder(purchaseOrder.Status) {
+		return nil, error_utils.ErrorCode.PURCHASE_ORDER_CLOSED
+	}
+
+	items, err := s.purchaseOrderItemRepo.GetAllByPurchaseOrderIDQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Receive Error when get purchase order items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	itemMap := make(map[int]*entity.PurchaseOrderItem)
+	for i := range items {
+		itemMap[items[i].ID] = &items[i]
+	}
+
+	quantityToRestock := make(map[int]int)
+	receivedPrices := make(map[int]int)
+	for _, requestedItem := range request.Items {
+		item, ok := itemMap[requestedItem.PurchaseOrderItemID]
+		if !ok {
+			log.Error("PurchaseOrderService.Receive Error: purchase order item ", requestedItem.PurchaseOrderItemID, " does not belong to purchase order ", id)
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+		if item.ReceivedQuantity+requestedItem.Quantity > item.Quantity {
+			log.Error("PurchaseOrderService.Receive Error: received quantity exceeds ordered quantity for purchase order item ", item.ID)
+			return nil, error_utils.ErrorCode.RECEIVE_QUANTITY_EXCEEDED
+		}
+
+		err = s.purchaseOrderItemRepo.AddReceivedQuantityCommand(ctx, item.ID, requestedItem.Quantity, tx)
+		if err != nil {
+			log.Error("PurchaseOrderService.Receive Error when update received quantity: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		item.ReceivedQuantity += requestedItem.Quantity
+		quantityToRestock[item.ProductID] += requestedItem.Quantity
+		receivedPrices[item.ProductID] = item.PurchasePrice
+	}
+
+	note := "Nhập hàng từ phiếu nhập số " + strconv.Itoa(id)
+	if request.Note != nil && *request.Note != "" {
+		note += ": " + *request.Note
+	}
+	warehouseQuantityToRestock := make(map[inventoryKey]int, len(quantityToRestock))
+	for productID, quantity := range quantityToRestock {
+		warehouseQuantityToRestock[inventoryKey{warehouseID: purchaseOrder.WarehouseID, productID: productID}] = quantity
+	}
+	errCode := restockProducts(ctx, s.inventoryRepo, s.inventoryHistoryRepo, warehouseQuantityToRestock, entity.InventoryMovementType.PURCHASE, user.Username, note, id, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	// Fold the received goods into each product's average cost, in product order like the inventory locks above
+	productIDs := make([]int, 0, len(quantityToRestock))
+	for productID := range quantityToRestock {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Ints(productIDs)
+	for _, productID := range productIDs {
+		errCode = applyReceivedCost(ctx, s.productRepo, s.inventoryRepo, s.productCostHistoryRepo, productID, quantityToRestock[productID], receivedPrices[productID], entity.ProductCostSource.PURCHASE_ORDER, &id, tx)
+		if errCode != "" {
+			return nil, errCode
+		}
+	}
+
+	if request.UpdateOriginalPrice {
+		for productID, purchasePrice := range receivedPrices {
+			if errCode := s.updateOriginalPrice(ctx, productID, purchasePrice, tx); errCode != "" {
+				return nil, errCode
+			}
+		}
+	}
+
+	// The purchase order is closed once every line has been received in full
+	purchaseOrder.Status = entity.PurchaseOrderStatus.RECEIVED
+	for _, item := range items {
+		if item.ReceivedQuantity < item.Quantity {
+			purchaseOrder.Status = entity.PurchaseOrderStatus.PARTIALLY_RECEIVED
+			break
+		}
+	}
+	if purchaseOrder.Status == entity.PurchaseOrderStatus.RECEIVED {
+		now := time.Now()
+		purchaseOrder.ReceivedAt = &now
+	}
+	err = s.purchaseOrderRepo.UpdateStatusCommand(ctx, purchaseOrder, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Receive Error when update purchase order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Receive Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return s.GetOne(ctx, id)
+}
+
+func (s *PurchaseOrderService) Cancel(ctx *gin.Context, id int) (*model.PurchaseOrderResponse, string) {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Cancel Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PurchaseOrderService.Cancel Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	purchaseOrder, err := s.purchaseOrderRepo.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Cancel Error when get purchase order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if purchaseOrder == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if !entity.CanReceivePurchaseOrder(purchaseOrder.Status) {
+		return nil, error_utils.ErrorCode.PURCHASE_ORDER_CLOSED
+	}
+
+	// Goods already received stay in inventory, cancelling only stops waiting for the rest
+	now := time.Now()
+	purchaseOrder.Status = entity.PurchaseOrderStatus.CANCELLED
+	purchaseOrder.CancelledAt = &now
+	err = s.purchaseOrderRepo.UpdateStatusCommand(ctx, purchaseOrder, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Cancel Error when update purchase order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Cancel Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return s.GetOne(ctx, id)
+}
+
+// Helper to set a product's original price to the price it was just bought at, recording the change in the audit log
+func (s *PurchaseOrderService) updateOriginalPrice(ctx *gin.Context, productID int, purchasePrice int, tx *sqlx.Tx) string {
+	product, err := s.productRepo.GetOneByIDQuery(ctx, productID, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.updateOriginalPrice Error when get product: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if product == nil || product.OriginalPrice == purchasePrice {
+		return ""
+	}
+
+	updatedProduct := *product
+	updatedProduct.OriginalPrice = purchasePrice
+	err = s.productRepo.UpdateCommand(ctx, &updatedProduct, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.updateOriginalPrice Error when update product: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return recordAuditLog(ctx, s.auditLogRepo, entity.AuditAction.UPDATE, entity.AuditEntityType.PRODUCT, productID, product, updatedProduct, tx)
+}
+
+func toPurchaseOrderResponse(purchaseOrder entity.PurchaseOrder, items []entity.PurchaseOrderItem) model.PurchaseOrderResponse {
+	response := model.PurchaseOrderResponse{
+		ID:            purchaseOrder.ID,
+		SupplierID:    purchaseOrder.SupplierID,
+		SupplierName:  purchaseOrder.SupplierName,
+		WarehouseID:   purchaseOrder.WarehouseID,
+		WarehouseName: purchaseOrder.WarehouseName,
+		Status:        purchaseOrder.Status,
+		ExpectedDate:  purchaseOrder.ExpectedDate,
+		Note:          purchaseOrder.Note,
+		TotalAmount:   &purchaseOrder.TotalAmount,
+		CreatedBy:     purchaseOrder.CreatedBy,
+		CreatedByName: purchaseOrder.CreatedByName,
+		CreatedAt:     purchaseOrder.CreatedAt,
+		ReceivedAt:    purchaseOrder.ReceivedAt,
+		CancelledAt:   purchaseOrder.CancelledAt,
+	}
+
+	if items != nil {
+		response.Items = make([]model.PurchaseOrderItemResponse, len(items))
+		for i, item := range items {
+			response.Items[i] = model.PurchaseOrderItemResponse{
+				ID:                item.ID,
+				ProductID:         item.ProductID,
+				ProductName:       item.ProductName,
+				Quantity:          item.Quantity,
+				ReceivedQuantity:  item.ReceivedQuantity,
+				RemainingQuantity: item.Quantity - item.ReceivedQuantity,
+				PurchasePrice:     &item.PurchasePrice,
+			}
+		}
+	}
+	return response
+}
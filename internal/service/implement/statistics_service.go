@@ -2,7 +2,9 @@ package serviceimplement
 
 import (
 	"context"
+	"time"
 
+	"github.com/pna/order-app-backend/internal/domain/entity"
 	"github.com/pna/order-app-backend/internal/domain/model"
 	"github.com/pna/order-app-backend/internal/repository"
 	"github.com/pna/order-app-backend/internal/service"
@@ -15,6 +17,7 @@ type StatisticsService struct {
 	customerRepo  repository.CustomerRepository
 	inventoryRepo repository.InventoryRepository
 	orderRepo     repository.OrderRepository
+	reportRepo    repository.ReportRepository
 }
 
 func NewStatisticsService(
@@ -22,12 +25,14 @@ func NewStatisticsService(
 	customerRepo repository.CustomerRepository,
 	inventoryRepo repository.InventoryRepository,
 	orderRepo repository.OrderRepository,
+	reportRepo repository.ReportRepository,
 ) service.StatisticsService {
 	return &StatisticsService{
 		productRepo:   productRepo,
 		customerRepo:  customerRepo,
 		inventoryRepo: inventoryRepo,
 		orderRepo:     orderRepo,
+		reportRepo:    reportRepo,
 	}
 }
 
@@ -57,24 +62,32 @@ func (s *StatisticsService) GetDashboardStats(ctx context.Context) (model.Dashbo
 	totalInventoryItems := 0
 	lowStockProducts := 0
 
+	inventories, err := s.inventoryRepo.GetAllQuery(ctx, 0, nil)
+	if err != nil {
+		log.Error("StatisticsService.GetDashboardStats Error fetching inventories: " + err.Error())
+		return model.DashboardStatsResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// A product's stock is the sum over all warehouses, compared with the product's own minimum stock
+	productStocks := sumInventoriesByProduct(inventories)
+	for _, inventory := range inventories {
+		totalInventoryItems += inventory.Quantity
+	}
 	for _, product := range products {
-		inventory, err := s.inventoryRepo.GetOneByProductIDQuery(ctx, product.ID, nil)
-		if err != nil {
-			log.Error("StatisticsService.GetDashboardStats Error fetching inventory for product " + string(rune(product.ID)) + ": " + err.Error())
-			continue
-		}
-		if inventory != nil {
-			totalInventoryItems += inventory.Quantity
-			if inventory.Quantity < 10 { // Consider low stock if less than 10
-				lowStockProducts++
-			}
+		if stock, ok := productStocks[product.ID]; ok && isLowStock(product, stock) {
+			lowStockProducts++
 		}
 	}
 
-	// Calculate pending orders
+	// Calculate pending and cancelled orders
 	pendingOrders := 0
+	cancelledOrders := 0
 	for _, order := range orders {
-		if order.DeliveryStatus != "COMPLETED" {
+		switch order.DeliveryStatus {
+		case entity.OrderDeliveryStatus.COMPLETED:
+		case entity.OrderDeliveryStatus.CANCELLED:
+			cancelledOrders++
+		default:
 			pendingOrders++
 		}
 	}
@@ -86,5 +99,133 @@ func (s *StatisticsService) GetDashboardStats(ctx context.Context) (model.Dashbo
 		LowStockProducts:    lowStockProducts,
 		TotalOrders:         len(orders),
 		PendingOrders:       pendingOrders,
+		CancelledOrders:     cancelledOrders,
 	}, ""
 }
+
+func (s *StatisticsService) GetReportByPeriod(ctx context.Context, groupBy string, fromDate time.Time, toDate time.Time) (model.GetPeriodReportResponse, string) {
+	if groupBy != entity.ReportPeriod.DAY && groupBy != entity.ReportPeriod.WEEK && groupBy != entity.ReportPeriod.MONTH {
+		log.Error("StatisticsService.GetReportByPeriod Error: invalid group by " + groupBy)
+		return model.GetPeriodReportResponse{}, error_utils.ErrorCode.BAD_REQUEST
+	}
+	if toDate.Before(fromDate) {
+		return model.GetPeriodReportResponse{}, error_utils.ErrorCode.BAD_REQUEST
+	}
+
+	rows, err := s.reportRepo.GetFiguresByPeriodQuery(ctx, groupBy, fromDate, toDate, nil)
+	if err != nil {
+		log.Error("StatisticsService.GetReportByPeriod Error fetching report: " + err.Error())
+		return model.GetPeriodReportResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+
+	resp := model.GetPeriodReportResponse{
+		FromDate: fromDate,
+		ToDate:   toDate,
+		GroupBy:  groupBy,
+		Rows:     make([]model.PeriodReportRow, 0, len(rows)),
+	}
+	total := entity.ReportFigures{}
+	for _, row := range rows {
+		resp.Rows = append(resp.Rows, model.PeriodReportRow{
+			Period:        row.Period,
+			ReportFigures: toReportFigures(row.ReportFigures),
+		})
+		addReportFigures(&total, row.ReportFigures)
+	}
+	resp.Total = toReportFigures(total)
+
+	return resp, ""
+}
+
+func (s *StatisticsService) GetReportByCustomer(ctx context.Context, fromDate time.Time, toDate time.Time) (model.GetCustomerReportResponse, string) {
+	if toDate.Before(fromDate) {
+		return model.GetCustomerReportResponse{}, error_utils.ErrorCode.BAD_REQUEST
+	}
+
+	rows, err := s.reportRepo.GetFiguresByCustomerQuery(ctx, fromDate, toDate, nil)
+	if err != nil {
+		log.Error("StatisticsService.GetReportByCustomer Error fetching report: " + err.Error())
+		return model.GetCustomerReportResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+
+	resp := model.GetCustomerReportResponse{
+		FromDate: fromDate,
+		ToDate:   toDate,
+		Rows:     make([]model.CustomerReportRow, 0, len(rows)),
+	}
+	total := entity.ReportFigures{}
+	for _, row := range rows {
+		resp.Rows = append(resp.Rows, model.CustomerReportRow{
+			CustomerID:    row.CustomerID,
+			CustomerName:  row.CustomerName,
+			ReportFigures: toReportFigures(row.ReportFigures),
+		})
+		addReportFigures(&total, row.ReportFigures)
+	}
+	resp.Total = toReportFigures(total)
+
+	return resp, ""
+}
+
+func (s *StatisticsService) GetReportByProduct(ctx context.Context, fromDate time.Time, toDate time.Time) (model.GetProductReportResponse, string) {
+	if toDate.Before(fromDate) {
+		return model.GetProductReportResponse{}, error_utils.ErrorCode.BAD_REQUEST
+	}
+
+	rows, err := s.reportRepo.GetFiguresByProductQuery(ctx, fromDate, toDate, nil)
+	if err != nil {
+		log.Error("StatisticsService.GetReportByProduct Error fetching report: " + err.Error())
+		return model.GetProductReportResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+
+	resp := model.GetProductReportResponse{
+		FromDate: fromDate,
+		ToDate:   toDate,
+		Rows:     make([]model.ProductReportRow, 0, len(rows)),
+	}
+	for _, row := range rows {
+		resp.Rows = append(resp.Rows, model.ProductReportRow{
+			ProductID:            row.ProductID,
+			ProductName:          row.ProductName,
+			OrderCount:           row.OrderCount,
+			QuantitySold:         row.QuantitySold,
+			QuantityReturned:     row.QuantityReturned,
+			Revenue:              row.Revenue,
+			OriginalCost:         row.OriginalCost,
+			ProfitLoss:           row.ProfitLoss,
+			ProfitLossPercentage: calculateProfitLossPercentage(row.ProfitLoss, row.OriginalCost),
+		})
+	}
+
+	return resp, ""
+}
+
+// Helper to add the figures of a report row to a running total
+func addReportFigures(total *entity.ReportFigures, figures entity.ReportFigures) {
+	total.OrderCount += figures.OrderCount
+	total.Revenue += figures.Revenue
+	total.OriginalCost += figures.OriginalCost
+	total.AdditionalCost += figures.AdditionalCost
+	total.Tax += figures.Tax
+	total.ProfitLoss += figures.ProfitLoss
+}
+
+func toReportFigures(figures entity.ReportFigures) model.ReportFigures {
+	return model.ReportFigures{
+		OrderCount:           figures.OrderCount,
+		Revenue:              figures.Revenue,
+		OriginalCost:         figures.OriginalCost,
+		AdditionalCost:       figures.AdditionalCost,
+		Tax:                  figures.Tax,
+		ProfitLoss:           figures.ProfitLoss,
+		ProfitLossPercentage: calculateProfitLossPercentage(figures.ProfitLoss, figures.OriginalCost),
+	}
+}
+
+// Helper to express a profit/loss as a percentage of the original cost
+func calculateProfitLossPercentage(profitLoss int, originalCost int) float64 {
+	if originalCost <= 0 {
+		return 0
+	}
+	return float64(profitLoss) / float64(originalCost) * 100
+}
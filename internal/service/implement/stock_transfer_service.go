@@ -0,0 +1,188 @@
+package serviceimplement
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type StockTransferService struct {
+	stockTransferRepo    repository.StockTransferRepository
+	warehouseRepo        repository.WarehouseRepository
+	inventoryRepo        repository.InventoryRepository
+	inventoryHistoryRepo repository.InventoryHistoryRepository
+	userRepo             repository.UserRepository
+	unitOfWork           repository.UnitOfWork
+}
+
+func NewStockTransferService(
+	stockTransferRepo repository.StockTransferRepository,
+	warehouseRepo repository.WarehouseRepository,
+	inventoryRepo repository.InventoryRepository,
+	inventoryHistoryRepo repository.InventoryHistoryRepository,
+	userRepo repository.UserRepository,
+	unitOfWork repository.UnitOfWork,
+) service.StockTransferService {
+	return &StockTransferService{
+		stockTransferRepo:    stockTransferRepo,
+		warehouseRepo:        warehouseRepo,
+		inventoryRepo:        inventoryRepo,
+		inventoryHistoryRepo: inventoryHistoryRepo,
+		userRepo:             userRepo,
+		unitOfWork:           unitOfWork,
+	}
+}
+
+func (s *StockTransferService) Create(ctx *gin.Context, request model.CreateStockTransferRequest) (*model.StockTransferResponse, string) {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("StockTransferService.Create Error: user ID not found in context")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Get user details to get username
+	user, err := s.userRepo.FindByIDQuery(ctx, int(userID), nil)
+	if err != nil {
+		log.Error("StockTransferService.Create Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		log.Error("StockTransferService.Create Error: user not found")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	if request.FromWarehouseID == request.ToWarehouseID {
+		log.Error("StockTransferService.Create Error: source and destination warehouse are the same")
+		return nil, error_utils.ErrorCode.BAD_REQUEST
+	}
+
+	fromWarehouse, err := s.warehouseRepo.GetOneByIDQuery(ctx, request.FromWarehouseID, nil)
+	if err != nil {
+		log.Error("StockTransferService.Create Error when get source warehouse: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	toWarehouse, err := s.warehouseRepo.GetOneByIDQuery(ctx, request.ToWarehouseID, nil)
+	if err != nil {
+		log.Error("StockTransferService.Create Error when get destination warehouse: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if fromWarehouse == nil || toWarehouse == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("StockTransferService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("StockTransferService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	inventoryMap, err := lockInventories(ctx, s.inventoryRepo, []int{request.ProductID}, tx)
+	if err != nil {
+		log.Error("StockTransferService.Create Error when lock inventories: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	fromInventory := inventoryMap[inventoryKey{warehouseID: request.FromWarehouseID, productID: request.ProductID}]
+	toInventory := inventoryMap[inventoryKey{warehouseID: request.ToWarehouseID, productID: request.ProductID}]
+	if fromInventory == nil || toInventory == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	// Goods reserved for pending orders must stay in their warehouse
+	if availableQuantity(fromInventory) < request.Quantity {
+		log.Error("StockTransferService.Create Error: inventory quantity exceeded for productID ", request.ProductID, " in warehouse ", request.FromWarehouseID)
+		return nil, error_utils.ErrorCode.INVENTORY_QUANTITY_EXCEEDED
+	}
+
+	stockTransfer := &entity.StockTransfer{
+		ProductID:       request.ProductID,
+		FromWarehouseID: request.FromWarehouseID,
+		ToWarehouseID:   request.ToWarehouseID,
+		Quantity:        request.Quantity,
+		Note:            request.Note,
+		CreatedBy:       user.ID,
+	}
+	err = s.stockTransferRepo.CreateCommand(ctx, stockTransfer, tx)
+	if err != nil {
+		log.Error("StockTransferService.Create Error when create stock transfer: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// The transfer is recorded as a pair of history rows, one per warehouse, both referencing the transfer
+	transferNumber := " (phiếu chuyển kho số " + strconv.Itoa(stockTransfer.ID) + ")"
+	errCode := adjustInventory(ctx, s.inventoryRepo, s.inventoryHistoryRepo, fromInventory, -request.Quantity, entity.InventoryMovementType.TRANSFER, user.Username, "Chuyển sang "+toWarehouse.Name+transferNumber, &stockTransfer.ID, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+	errCode = adjustInventory(ctx, s.inventoryRepo, s.inventoryHistoryRepo, toInventory, request.Quantity, entity.InventoryMovementType.TRANSFER, user.Username, "Nhận từ "+fromWarehouse.Name+transferNumber, &stockTransfer.ID, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("StockTransferService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	createdTransfer, err := s.stockTransferRepo.GetOneByIDQuery(ctx, stockTransfer.ID, nil)
+	if err != nil {
+		log.Error("StockTransferService.Create Error when get stock transfer: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if createdTransfer == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	response := toStockTransferResponse(*createdTransfer)
+	return &response, ""
+}
+
+func (s *StockTransferService) GetAll(ctx *gin.Context, productID int, warehouseID int) (*model.GetAllStockTransfersResponse, string) {
+	stockTransfers, err := s.stockTransferRepo.GetAllWithFiltersQuery(ctx, productID, warehouseID, nil)
+	if err != nil {
+		log.Error("StockTransferService.GetAll Error when get stock transfers: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	stockTransferResponses := make([]model.StockTransferResponse, len(stockTransfers))
+	for i, stockTransfer := range stockTransfers {
+		stockTransferResponses[i] = toStockTransferResponse(stockTransfer)
+	}
+
+	return &model.GetAllStockTransfersResponse{
+		StockTransfers: stockTransferResponses,
+	}, ""
+}
+
+func toStockTransferResponse(stockTransfer entity.StockTransfer) model.StockTransferResponse {
+	return model.StockTransferResponse{
+		ID:                stockTransfer.ID,
+		ProductID:         stockTransfer.ProductID,
+		ProductName:       stockTransfer.ProductName,
+		FromWarehouseID:   stockTransfer.FromWarehouseID,
+		FromWarehouseName: stockTransfer.FromWarehouseName,
+		ToWarehouseID:     stockTransfer.ToWarehouseID,
+		ToWarehouseName:   stockTransfer.ToWarehouseName,
+		Quantity:          stockTransfer.Quantity,
+		Note:              stockTransfer.Note,
+		CreatedBy:         stockTransfer.CreatedBy,
+		CreatedByName:     stockTransfer.CreatedByName,
+		CreatedAt:         stockTransfer.CreatedAt,
+	}
+}
@@ -0,0 +1,493 @@
+package serviceimplement
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type StocktakeService struct {
+	stocktakeRepo        repository.StocktakeRepository
+	stocktakeItemRepo    repository.StocktakeItemRepository
+	warehouseRepo        repository.WarehouseRepository
+	inventoryRepo        repository.InventoryRepository
+	inventoryHistoryRepo repository.InventoryHistoryRepository
+	userRepo             repository.UserRepository
+	unitOfWork           repository.UnitOfWork
+}
+
+func NewStocktakeService(
+	stocktakeRepo repository.StocktakeRepository,
+	stocktakeItemRepo repository.StocktakeItemRepository,
+	warehouseRepo repository.WarehouseRepository,
+	inventoryRepo repository.InventoryRepository,
+	inventoryHistoryRepo repository.InventoryHistoryRepository,
+	userRepo repository.UserRepository,
+	unitOfWork repository.UnitOfWork,
+) service.StocktakeService {
+	return &StocktakeService{
+		stocktakeRepo:        stocktakeRepo,
+		stocktakeItemRepo:    stocktakeItemRepo,
+		warehouseRepo:        warehouseRepo,
+		inventoryRepo:        inventoryRepo,
+		inventoryHistoryRepo: inventoryHistoryRepo,
+		userRepo:             userRepo,
+		unitOfWork:           unitOfWork,
+	}
+}
+
+func (s *StocktakeService) Open(ctx *gin.Context, request model.OpenStocktakeRequest) (*model.StocktakeResponse, string) {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("StocktakeService.Open Error: user ID not found in context")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	warehouseID, errCode := resolveWarehouseID(ctx, s.warehouseRepo, request.WarehouseID, nil)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("StocktakeService.Open Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("StocktakeService.Open Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Two counts of the same warehouse would post the same variances twice
+	openStocktake, err := s.stocktakeRepo.GetOpenByWarehouseIDQuery(ctx, warehouseID, tx)
+	if err != nil {
+		log.Error("StocktakeService.Open Error when get open stocktake: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if openStocktake != nil {
+		return nil, error_utils.ErrorCode.STOCKTAKE_ALREADY_OPEN
+	}
+
+	stocktake := &entity.Stocktake{
+		WarehouseID: warehouseID,
+		Status:      entity.StocktakeStatus.OPEN,
+		Note:        request.Note,
+		CreatedBy:   int(userID),
+	}
+	err = s.stocktakeRepo.CreateCommand(ctx, stocktake, tx)
+	if err != nil {
+		log.Error("StocktakeService.Open Error when create stocktake: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	err = s.stocktakeItemRepo.CreateSnapshotCommand(ctx, stocktake.ID, warehouseID, tx)
+	if err != nil {
+		log.Error("StocktakeService.Open Error when create stocktake items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("StocktakeService.Open Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return s.GetOne(ctx, stocktake.ID)
+}
+
+func (s *StocktakeService) GetAll(ctx *gin.Context, warehouseID int, statuses []string) (*model.GetAllStocktakesResponse, string) {
+	stocktakes, err := s.stocktakeRepo.GetAllWithFiltersQuery(ctx, warehouseID, statuses, nil)
+	if err != nil {
+		log.Error("StocktakeService.GetAll Error when get stocktakes: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	stocktakeResponses := make([]model.StocktakeResponse, len(stocktakes))
+	for i, stocktake := range stocktakes {
+		stocktakeResponses[i] = toStocktakeResponse(stocktake, nil, nil)
+	}
+
+	return &model.GetAllStocktakesResponse{
+		Stocktakes: stocktakeResponses,
+	}, ""
+}
+
+func (s *StocktakeService) GetOne(ctx *gin.Context, id int) (*model.StocktakeResponse, string) {
+	stocktake, err := s.stocktakeRepo.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("StocktakeService.GetOne Error when get stocktake: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if stocktake == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	items, err := s.stocktakeItemRepo.GetAllByStocktakeIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("StocktakeService.GetOne Error when get stocktake items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// While the count is open, flag the products whose stock has moved since they were counted
+	var currentVersions map[int]string
+	if stocktake.Status == entity.StocktakeStatus.OPEN {
+		inventories, err := s.inventoryRepo.GetAllQuery(ctx, stocktake.WarehouseID, nil)
+		if err != nil {
+			log.Error("StocktakeService.GetOne Error when get inventories: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		currentVersions = make(map[int]string, len(inventories))
+		for _, inv := range inventories {
+			currentVersions[inv.ProductID] = inv.Version
+		}
+	}
+
+	response := toStocktakeResponse(*stocktake, items, currentVersions)
+	return &response, ""
+}
+
+func (s *StocktakeService) UpdateCounts(ctx *gin.Context, id int, request model.UpdateStocktakeCountsRequest) (*model.StocktakeResponse, string) {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("StocktakeService.UpdateCounts Error: user ID not found in context")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Each product may appear at most once in a count
+	countedQuantities := make(map[int]int)
+	for _, item := range request.Items {
+		if _, exists := countedQuantities[item.ProductID]; exists {
+			log.Error("StocktakeService.UpdateCounts Error: product ", item.ProductID, " appears more than once")
+			return nil, error_utils.ErrorCode.BAD_REQUEST
+		}
+		countedQuantities[item.ProductID] = *item.CountedQuantity
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("StocktakeService.UpdateCounts Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("StocktakeService.UpdateCounts Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock the stocktake so a count cannot land after it has been posted
+	stocktake, err := s.stocktakeRepo.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("StocktakeService.UpdateCounts Error when get stocktake: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if stocktake == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if stocktake.Status != entity.StocktakeStatus.OPEN {
+		return nil, error_utils.ErrorCode.STOCKTAKE_CLOSED
+	}
+
+	items, err := s.stocktakeItemRepo.GetAllByStocktakeIDQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("StocktakeService.UpdateCounts Error when get stocktake items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	itemMap := make(map[int]*entity.StocktakeItem, len(items))
+	for i := range items {
+		itemMap[items[i].ProductID] = &items[i]
+	}
+
+	now := time.Now()
+	countedBy := int(userID)
+	for _, requestedItem := range request.Items {
+		item, ok := itemMap[requestedItem.ProductID]
+		if !ok {
+			log.Error("StocktakeService.UpdateCounts Error: product ", requestedItem.ProductID, " is not part of stocktake ", id)
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+
+		inv, err := s.inventoryRepo.GetOneByWarehouseAndProductIDQuery(ctx, stocktake.WarehouseID, requestedItem.ProductID, tx)
+		if err != nil {
+			log.Error("StocktakeService.UpdateCounts Error when get inventory: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if inv == nil {
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+
+		// A count is compared against the book quantity at the time it is entered, so counting a product
+		// again after its stock moved takes a fresh snapshot
+		countedQuantity := countedQuantities[requestedItem.ProductID]
+		item.SnapshotQuantity = inv.Quantity
+		item.SnapshotVersion = inv.Version
+		item.CountedQuantity = &countedQuantity
+		item.CountedBy = &countedBy
+		item.CountedAt = &now
+		err = s.stocktakeItemRepo.UpdateCountCommand(ctx, item, tx)
+		if err != nil {
+			log.Error("StocktakeService.UpdateCounts Error when update stocktake item: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("StocktakeService.UpdateCounts Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return s.GetOne(ctx, id)
+}
+
+// Post adjusts the stock of every counted product by its variance. When the stock of a counted product moved
+// after it was counted nothing is adjusted, and the returned stocktake flags the products to count again
+func (s *StocktakeService) Post(ctx *gin.Context, id int) (*model.StocktakeResponse, string) {
+	// Get user ID from context
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("StocktakeService.Post Error: user ID not found in context")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Get user details to get username
+	user, err := s.userRepo.FindByIDQuery(ctx, int(userID), nil)
+	if err != nil {
+		log.Error("StocktakeService.Post Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		log.Error("StocktakeService.Post Error: user not found")
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("StocktakeService.Post Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("StocktakeService.Post Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	stocktake, err := s.stocktakeRepo.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("StocktakeService.Post Error when get stocktake: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if stocktake == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if stocktake.Status != entity.StocktakeStatus.OPEN {
+		return nil, error_utils.ErrorCode.STOCKTAKE_CLOSED
+	}
+
+	items, err := s.stocktakeItemRepo.GetAllByStocktakeIDQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("StocktakeService.Post Error when get stocktake items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Products that were never counted are left as they are
+	productIDs := make([]int, 0, len(items))
+	for _, item := range items {
+		if item.CountedQuantity != nil {
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+
+	// Lock the counted products' inventories, in the same order as orders and transfers do
+	inventoryMap, err := lockInventories(ctx, s.inventoryRepo, productIDs, tx)
+	if err != nil {
+		log.Error("StocktakeService.Post Error when lock inventories: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	currentVersions := make(map[int]string, len(productIDs))
+	needsRecount := false
+	for _, item := range items {
+		if item.CountedQuantity == nil {
+			continue
+		}
+		inv := inventoryMap[inventoryKey{warehouseID: stocktake.WarehouseID, productID: item.ProductID}]
+		if inv == nil {
+			log.Error("StocktakeService.Post Error: inventory not found for productID ", item.ProductID, " in warehouse ", stocktake.WarehouseID)
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+		currentVersions[item.ProductID] = inv.Version
+		if inv.Version != item.SnapshotVersion {
+			needsRecount = true
+		}
+	}
+	if needsRecount {
+		response := toStocktakeResponse(*stocktake, items, currentVersions)
+		return &response, error_utils.ErrorCode.STOCKTAKE_RECOUNT_REQUIRED
+	}
+
+	// Goods reserved for pending orders cannot be counted away, those orders have to be delivered or cancelled first
+	belowReserved := make(map[int]int)
+	for _, item := range items {
+		if item.CountedQuantity == nil {
+			continue
+		}
+		inv := inventoryMap[inventoryKey{warehouseID: stocktake.WarehouseID, productID: item.ProductID}]
+		if *item.CountedQuantity < inv.ReservedQuantity {
+			belowReserved[item.ProductID] = inv.ReservedQuantity
+		}
+	}
+	if len(belowReserved) > 0 {
+		response := toStocktakeResponse(*stocktake, items, currentVersions)
+		for i := range response.Items {
+			if reservedQuantity, ok := belowReserved[response.Items[i].ProductID]; ok {
+				response.Items[i].BelowReserved = true
+				response.Items[i].ReservedQuantity = &reservedQuantity
+			}
+		}
+		return &response, error_utils.ErrorCode.STOCKTAKE_BELOW_RESERVED
+	}
+
+	variances := make(map[inventoryKey]int)
+	for _, item := range items {
+		if item.CountedQuantity != nil && *item.CountedQuantity != item.SnapshotQuantity {
+			variances[inventoryKey{warehouseID: stocktake.WarehouseID, productID: item.ProductID}] = *item.CountedQuantity - item.SnapshotQuantity
+		}
+	}
+	note := "Kiểm kê phiếu số " + strconv.Itoa(id)
+	for _, key := range sortedInventoryKeys(variances) {
+		errCode := adjustInventory(ctx, s.inventoryRepo, s.inventoryHistoryRepo, inventoryMap[key], variances[key], entity.InventoryMovementType.ADJUSTMENT, user.Username, note, &id, tx)
+		if errCode != "" {
+			return nil, errCode
+		}
+	}
+
+	now := time.Now()
+	postedBy := int(userID)
+	stocktake.Status = entity.StocktakeStatus.POSTED
+	stocktake.PostedBy = &postedBy
+	stocktake.PostedAt = &now
+	err = s.stocktakeRepo.UpdateStatusCommand(ctx, stocktake, tx)
+	if err != nil {
+		log.Error("StocktakeService.Post Error when update stocktake: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("StocktakeService.Post Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return s.GetOne(ctx, id)
+}
+
+func (s *StocktakeService) Cancel(ctx *gin.Context, id int) (*model.StocktakeResponse, string) {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("StocktakeService.Cancel Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("StocktakeService.Cancel Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	stocktake, err := s.stocktakeRepo.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("StocktakeService.Cancel Error when get stocktake: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if stocktake == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if stocktake.Status != entity.StocktakeStatus.OPEN {
+		return nil, error_utils.ErrorCode.STOCKTAKE_CLOSED
+	}
+
+	// The counts are kept for reference, the stock is not touched
+	now := time.Now()
+	stocktake.Status = entity.StocktakeStatus.CANCELLED
+	stocktake.CancelledAt = &now
+	err = s.stocktakeRepo.UpdateStatusCommand(ctx, stocktake, tx)
+	if err != nil {
+		log.Error("StocktakeService.Cancel Error when update stocktake: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("StocktakeService.Cancel Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return s.GetOne(ctx, id)
+}
+
+// Helper to build a stocktake response; counted items whose inventory version differs from currentVersions
+// are flagged for a recount, and nothing is flagged when currentVersions is nil
+func toStocktakeResponse(stocktake entity.Stocktake, items []entity.StocktakeItem, currentVersions map[int]string) model.StocktakeResponse {
+	response := model.StocktakeResponse{
+		ID:            stocktake.ID,
+		WarehouseID:   stocktake.WarehouseID,
+		WarehouseName: stocktake.WarehouseName,
+		Status:        stocktake.Status,
+		Note:          stocktake.Note,
+		CreatedBy:     stocktake.CreatedBy,
+		CreatedByName: stocktake.CreatedByName,
+		CreatedAt:     stocktake.CreatedAt,
+		PostedBy:      stocktake.PostedBy,
+		PostedAt:      stocktake.PostedAt,
+		CancelledAt:   stocktake.CancelledAt,
+	}
+
+	if items != nil {
+		response.Items = make([]model.StocktakeItemResponse, len(items))
+		for i, item := range items {
+			itemResponse := model.StocktakeItemResponse{
+				ProductID:        item.ProductID,
+				ProductName:      item.ProductName,
+				SnapshotQuantity: item.SnapshotQuantity,
+				CountedQuantity:  item.CountedQuantity,
+				CountedByName:    item.CountedByName,
+				CountedAt:        item.CountedAt,
+			}
+			if item.CountedQuantity != nil {
+				variance := *item.CountedQuantity - item.SnapshotQuantity
+				itemResponse.Variance = &variance
+				if currentVersion, ok := currentVersions[item.ProductID]; ok && currentVersion != item.SnapshotVersion {
+					itemResponse.NeedsRecount = true
+				}
+			}
+			response.Items[i] = itemResponse
+		}
+	}
+	return response
+}
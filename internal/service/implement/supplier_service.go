@@ -0,0 +1,119 @@
+package serviceimplement
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type SupplierService struct {
+	supplierRepository repository.SupplierRepository
+}
+
+func NewSupplierService(supplierRepository repository.SupplierRepository) service.SupplierService {
+	return &SupplierService{
+		supplierRepository: supplierRepository,
+	}
+}
+
+func (s *SupplierService) Create(ctx *gin.Context, request model.CreateSupplierRequest) (*model.SupplierResponse, string) {
+	supplier := &entity.Supplier{
+		Name:      request.Name,
+		Phone:     request.Phone,
+		Address:   request.Address,
+		Note:      request.Note,
+		CreatedAt: time.Now(),
+	}
+
+	err := s.supplierRepository.CreateCommand(ctx, supplier, nil)
+	if err != nil {
+		log.Error("SupplierService.Create Error when create supplier: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toSupplierResponse(*supplier)
+	return &response, ""
+}
+
+func (s *SupplierService) Update(ctx *gin.Context, supplierID int, request model.UpdateSupplierRequest) (*model.SupplierResponse, string) {
+	supplier, err := s.supplierRepository.GetOneByIDQuery(ctx, supplierID, nil)
+	if err != nil {
+		log.Error("SupplierService.Update Error when get supplier: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if supplier == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	// Only update fields that are not empty
+	if request.Name != "" {
+		supplier.Name = request.Name
+	}
+	if request.Phone != "" {
+		supplier.Phone = request.Phone
+	}
+	if request.Address != "" {
+		supplier.Address = request.Address
+	}
+	if request.Note != nil {
+		supplier.Note = request.Note
+	}
+
+	err = s.supplierRepository.UpdateCommand(ctx, supplier, nil)
+	if err != nil {
+		log.Error("SupplierService.Update Error when update supplier: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toSupplierResponse(*supplier)
+	return &response, ""
+}
+
+func (s *SupplierService) GetAll(ctx *gin.Context) (*model.GetAllSuppliersResponse, string) {
+	suppliers, err := s.supplierRepository.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("SupplierService.GetAll Error when get suppliers: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	supplierResponses := make([]model.SupplierResponse, len(suppliers))
+	for i, supplier := range suppliers {
+		supplierResponses[i] = toSupplierResponse(supplier)
+	}
+
+	return &model.GetAllSuppliersResponse{
+		Suppliers: supplierResponses,
+	}, ""
+}
+
+func (s *SupplierService) GetOne(ctx *gin.Context, id int) (*model.GetOneSupplierResponse, string) {
+	supplier, err := s.supplierRepository.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("SupplierService.GetOne Error when get supplier: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if supplier == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	return &model.GetOneSupplierResponse{
+		Supplier: toSupplierResponse(*supplier),
+	}, ""
+}
+
+func toSupplierResponse(supplier entity.Supplier) model.SupplierResponse {
+	return model.SupplierResponse{
+		ID:        supplier.ID,
+		Name:      supplier.Name,
+		Phone:     supplier.Phone,
+		Address:   supplier.Address,
+		Note:      supplier.Note,
+		CreatedAt: supplier.CreatedAt,
+	}
+}
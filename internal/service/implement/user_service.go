@@ -1,8 +1,18 @@
 package serviceimplement
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
 	"github.com/pna/order-app-backend/internal/bean"
+	"github.com/pna/order-app-backend/internal/controller/http/middleware"
+	"github.com/pna/order-app-backend/internal/domain/entity"
 	"github.com/pna/order-app-backend/internal/domain/model"
 	"github.com/pna/order-app-backend/internal/repository"
 	"github.com/pna/order-app-backend/internal/service"
@@ -10,22 +20,60 @@ import (
 	"github.com/pna/order-app-backend/internal/utils/env"
 	"github.com/pna/order-app-backend/internal/utils/error_utils"
 	"github.com/pna/order-app-backend/internal/utils/jwt"
+	stringutils "github.com/pna/order-app-backend/internal/utils/string_utils"
 	log "github.com/sirupsen/logrus"
 )
 
 type UserService struct {
-	userRepository  repository.UserRepository
-	passwordEncoder bean.PasswordEncoder
+	userRepository         repository.UserRepository
+	refreshTokenRepository repository.RefreshTokenRepository
+	loginAttemptRepository repository.LoginAttemptRepository
+	passwordEncoder        bean.PasswordEncoder
+	unitOfWork             repository.UnitOfWork
+	dummyPasswordHash      string
 }
 
-func NewUserService(userRepository repository.UserRepository, passwordEncoder bean.PasswordEncoder) service.UserService {
+func NewUserService(
+	userRepository repository.UserRepository,
+	refreshTokenRepository repository.RefreshTokenRepository,
+	loginAttemptRepository repository.LoginAttemptRepository,
+	passwordEncoder bean.PasswordEncoder,
+	unitOfWork repository.UnitOfWork,
+) service.UserService {
+	// Compared against when the username does not exist, so unknown users take as long to reject as wrong passwords
+	dummyPasswordHash, err := passwordEncoder.Encrypt("dummy-password-for-timing")
+	if err != nil {
+		log.Error("NewUserService Error when hash dummy password: " + err.Error())
+	}
+
 	return &UserService{
-		userRepository:  userRepository,
-		passwordEncoder: passwordEncoder,
+		userRepository:         userRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		loginAttemptRepository: loginAttemptRepository,
+		passwordEncoder:        passwordEncoder,
+		unitOfWork:             unitOfWork,
+		dummyPasswordHash:      dummyPasswordHash,
 	}
 }
 
 func (s *UserService) Login(ctx *gin.Context, request model.LoginRequest) (*model.LoginResponse, string) {
+	attempt := &entity.LoginAttempt{
+		Username:    stringutils.Truncate(request.Username, 50),
+		IPAddress:   ctx.ClientIP(),
+		UserAgent:   stringutils.Truncate(ctx.Request.UserAgent(), 255),
+		AttemptedAt: time.Now(),
+	}
+
+	// Refuse to check the password at all while the username or the client IP is locked out
+	locked, errCode := s.isLoginLocked(ctx, attempt)
+	if errCode != "" {
+		return nil, errCode
+	}
+	if locked {
+		s.recordLoginAttempt(ctx, attempt, entity.LoginFailureReason.LOCKED)
+		return nil, error_utils.ErrorCode.LOGIN_LOCKED
+	}
+
 	// Find user by username
 	user, err := s.userRepository.FindByUsernameQuery(ctx, request.Username, nil)
 	if err != nil {
@@ -33,34 +81,501 @@ func (s *UserService) Login(ctx *gin.Context, request model.LoginRequest) (*mode
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
+	// Unknown usernames and wrong passwords get the same error so usernames cannot be enumerated
 	if user == nil {
-		return nil, error_utils.ErrorCode.USERNAME_NOT_FOUND
+		s.passwordEncoder.Compare(s.dummyPasswordHash, request.Password)
+		s.recordLoginAttempt(ctx, attempt, entity.LoginFailureReason.UNKNOWN_USER)
+		return nil, error_utils.ErrorCode.INVALID_CREDENTIALS
 	}
+	attempt.UserID = &user.ID
 
 	// Verify password
 	isValid := s.passwordEncoder.Compare(user.Password, request.Password)
 	if !isValid {
-		return nil, error_utils.ErrorCode.UNAUTHORIZED
+		s.recordLoginAttempt(ctx, attempt, entity.LoginFailureReason.WRONG_PASSWORD)
+		return nil, error_utils.ErrorCode.INVALID_CREDENTIALS
 	}
 
-	// Generate JWT token
-	jwtSecret, err := env.GetEnv("JWT_SECRET")
+	if !user.IsActive {
+		s.recordLoginAttempt(ctx, attempt, entity.LoginFailureReason.DISABLED)
+		return nil, error_utils.ErrorCode.USER_DISABLED
+	}
+
+	response, _, errCode := s.issueTokens(ctx, user, nil)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	s.recordLoginAttempt(ctx, attempt, "")
+	return response, ""
+}
+
+func (s *UserService) Refresh(ctx *gin.Context, request model.RefreshTokenRequest) (*model.LoginResponse, string) {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("UserService.Refresh Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("UserService.Refresh Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock the token so two concurrent refreshes cannot both rotate it
+	refreshToken, err := s.refreshTokenRepository.GetOneByTokenHashForUpdateQuery(ctx, hashRefreshToken(request.RefreshToken), tx)
+	if err != nil {
+		log.Error("UserService.Refresh Error when get refresh token: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if refreshToken == nil {
+		return nil, error_utils.ErrorCode.REFRESH_TOKEN_INVALID
+	}
+
+	if refreshToken.RevokedAt != nil {
+		// A token that was already rotated is being replayed, so it has probably leaked: end every session of the user
+		if refreshToken.ReplacedByID != nil {
+			log.Warn("UserService.Refresh Reuse of rotated refresh token detected for user " + strconv.Itoa(refreshToken.UserID))
+			if errCode := s.revokeAllSessions(ctx, refreshToken.UserID, tx); errCode != "" {
+				return nil, errCode
+			}
+			if err := s.unitOfWork.Commit(tx); err != nil {
+				log.Error("UserService.Refresh Error when commit transaction: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+		}
+		return nil, error_utils.ErrorCode.REFRESH_TOKEN_INVALID
+	}
+	if time.Now().After(refreshToken.ExpiresAt) {
+		return nil, error_utils.ErrorCode.REFRESH_TOKEN_INVALID
+	}
+
+	user, err := s.userRepository.FindByIDQuery(ctx, refreshToken.UserID, tx)
+	if err != nil {
+		log.Error("UserService.Refresh Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return nil, error_utils.ErrorCode.REFRESH_TOKEN_INVALID
+	}
+	if !user.IsActive {
+		return nil, error_utils.ErrorCode.USER_DISABLED
+	}
+
+	response, newRefreshTokenID, errCode := s.issueTokens(ctx, user, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	err = s.refreshTokenRepository.RevokeCommand(ctx, refreshToken.ID, &newRefreshTokenID, tx)
+	if err != nil {
+		log.Error("UserService.Refresh Error when revoke refresh token: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("UserService.Refresh Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return response, ""
+}
+
+func (s *UserService) Logout(ctx *gin.Context, request model.LogoutRequest) string {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("UserService.Logout Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("UserService.Logout Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	refreshToken, err := s.refreshTokenRepository.GetOneByTokenHashForUpdateQuery(ctx, hashRefreshToken(request.RefreshToken), tx)
+	if err != nil {
+		log.Error("UserService.Logout Error when get refresh token: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if refreshToken == nil {
+		return error_utils.ErrorCode.REFRESH_TOKEN_INVALID
+	}
+
+	if request.AllDevices {
+		if errCode := s.revokeAllSessions(ctx, refreshToken.UserID, tx); errCode != "" {
+			return errCode
+		}
+	} else {
+		// The access token of this session stays valid until it expires a few minutes later
+		err = s.refreshTokenRepository.RevokeCommand(ctx, refreshToken.ID, nil, tx)
+		if err != nil {
+			log.Error("UserService.Logout Error when revoke refresh token: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("UserService.Logout Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	return ""
+}
+
+func (s *UserService) Create(ctx *gin.Context, request model.CreateUserRequest) (*model.UserResponse, string) {
+	existingUser, err := s.userRepository.FindByUsernameQuery(ctx, request.Username, nil)
 	if err != nil {
-		log.Error("UserService.Login Error when get JWT secret: " + err.Error())
+		log.Error("UserService.Create Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if existingUser != nil {
+		return nil, error_utils.ErrorCode.USERNAME_ALREADY_EXISTS
+	}
+
+	hashedPassword, err := s.passwordEncoder.Encrypt(request.Password)
+	if err != nil {
+		log.Error("UserService.Create Error when hash password: " + err.Error())
 		return nil, error_utils.ErrorCode.INTERNAL_SERVER_ERROR
 	}
 
-	token, err := jwt.GenerateToken(constants.ACCESS_TOKEN_DURATION, jwtSecret, map[string]interface{}{
-		"id":       user.ID,
-		"username": user.Username,
+	user := &entity.User{
+		Username:  request.Username,
+		Password:  hashedPassword,
+		Role:      request.Role,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}
+	err = s.userRepository.CreateCommand(ctx, user, nil)
+	if err != nil {
+		log.Error("UserService.Create Error when create user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toUserResponse(*user)
+	return &response, ""
+}
+
+func (s *UserService) GetAll(ctx *gin.Context) (*model.GetAllUsersResponse, string) {
+	users, err := s.userRepository.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("UserService.GetAll Error when get users: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	userResponses := make([]model.UserResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = toUserResponse(user)
+	}
+
+	return &model.GetAllUsersResponse{
+		Users: userResponses,
+	}, ""
+}
+
+func (s *UserService) UpdateStatus(ctx *gin.Context, userID int, request model.UpdateUserStatusRequest) (*model.UserResponse, string) {
+	// An owner locking their own account could leave nobody able to manage users
+	if !*request.IsActive && int64(userID) == middleware.GetUserIdHelper(ctx) {
+		return nil, error_utils.ErrorCode.CANNOT_DISABLE_SELF
+	}
+
+	user, err := s.userRepository.FindByIDQuery(ctx, userID, nil)
+	if err != nil {
+		log.Error("UserService.UpdateStatus Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("UserService.UpdateStatus Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("UserService.UpdateStatus Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	err = s.userRepository.UpdateIsActiveCommand(ctx, userID, *request.IsActive, tx)
+	if err != nil {
+		log.Error("UserService.UpdateStatus Error when update user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	// A disabled account must not be able to come back with a refresh token issued earlier
+	if !*request.IsActive {
+		if errCode := s.revokeAllSessions(ctx, userID, tx); errCode != "" {
+			return nil, errCode
+		}
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("UserService.UpdateStatus Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	user.IsActive = *request.IsActive
+
+	response := toUserResponse(*user)
+	return &response, ""
+}
+
+func (s *UserService) ResetPassword(ctx *gin.Context, userID int, request model.ResetPasswordRequest) string {
+	user, err := s.userRepository.FindByIDQuery(ctx, userID, nil)
+	if err != nil {
+		log.Error("UserService.ResetPassword Error when get user: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	return s.updatePassword(ctx, user.ID, request.NewPassword)
+}
+
+func (s *UserService) ChangePassword(ctx *gin.Context, request model.ChangePasswordRequest) string {
+	userID := middleware.GetUserIdHelper(ctx)
+	if userID == 0 {
+		log.Error("UserService.ChangePassword Error: user ID not found in context")
+		return error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	user, err := s.userRepository.FindByIDQuery(ctx, int(userID), nil)
+	if err != nil {
+		log.Error("UserService.ChangePassword Error when get user: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	if !s.passwordEncoder.Compare(user.Password, request.OldPassword) {
+		return error_utils.ErrorCode.PASSWORD_INCORRECT
+	}
+
+	return s.updatePassword(ctx, user.ID, request.NewPassword)
+}
+
+func (s *UserService) GetLoginAttempts(ctx *gin.Context, request model.GetLoginAttemptsRequest) (*model.GetLoginAttemptsResponse, string) {
+	limit := request.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	loginAttempts, err := s.loginAttemptRepository.GetAllWithFiltersQuery(ctx, repository.LoginAttemptFilter{
+		Username:  request.Username,
+		IPAddress: request.IPAddress,
+		Success:   request.Success,
+		FromDate:  request.FromDate,
+		ToDate:    request.ToDate,
+		Limit:     limit,
+	}, nil)
+	if err != nil {
+		log.Error("UserService.GetLoginAttempts Error when get login attempts: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	loginAttemptResponses := make([]model.LoginAttemptResponse, len(loginAttempts))
+	for i, loginAttempt := range loginAttempts {
+		loginAttemptResponses[i] = model.LoginAttemptResponse{
+			ID:            loginAttempt.ID,
+			Username:      loginAttempt.Username,
+			IPAddress:     loginAttempt.IPAddress,
+			UserAgent:     loginAttempt.UserAgent,
+			UserID:        loginAttempt.UserID,
+			Success:       loginAttempt.Success,
+			FailureReason: loginAttempt.FailureReason,
+			AttemptedAt:   loginAttempt.AttemptedAt,
+		}
+	}
+
+	return &model.GetLoginAttemptsResponse{
+		LoginAttempts: loginAttemptResponses,
+	}, ""
+}
+
+// Helper to store a new password and end every session of the user, since the old password may have leaked
+func (s *UserService) updatePassword(ctx *gin.Context, userID int, newPassword string) string {
+	hashedPassword, err := s.passwordEncoder.Encrypt(newPassword)
+	if err != nil {
+		log.Error("UserService.updatePassword Error when hash password: " + err.Error())
+		return error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("UserService.updatePassword Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("UserService.updatePassword Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	err = s.userRepository.UpdatePasswordCommand(ctx, userID, hashedPassword, tx)
+	if err != nil {
+		log.Error("UserService.updatePassword Error when update password: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if errCode := s.revokeAllSessions(ctx, userID, tx); errCode != "" {
+		return errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("UserService.updatePassword Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	return ""
+}
+
+// Helper to revoke every refresh token of the user and bump the token version so issued access tokens stop working
+func (s *UserService) revokeAllSessions(ctx *gin.Context, userID int, tx *sqlx.Tx) string {
+	err := s.refreshTokenRepository.RevokeAllByUserIDCommand(ctx, userID, tx)
+	if err != nil {
+		log.Error("UserService.revokeAllSessions Error when revoke refresh tokens: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	err = s.userRepository.IncrementTokenVersionCommand(ctx, userID, tx)
+	if err != nil {
+		log.Error("UserService.revokeAllSessions Error when increment token version: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	return ""
+}
+
+// Helper to sign an access token and persist a new refresh token for the user; returns the refresh token ID
+func (s *UserService) issueTokens(ctx *gin.Context, user *entity.User, tx *sqlx.Tx) (*model.LoginResponse, int, string) {
+	jwtSecret, err := env.GetEnv("JWT_SECRET")
+	if err != nil {
+		log.Error("UserService.issueTokens Error when get JWT secret: " + err.Error())
+		return nil, 0, error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+
+	now := time.Now()
+	accessToken, err := jwt.GenerateToken(constants.ACCESS_TOKEN_DURATION, jwtSecret, map[string]interface{}{
+		"id":            user.ID,
+		"username":      user.Username,
+		"role":          user.Role,
+		"token_version": user.TokenVersion,
 	})
 	if err != nil {
-		log.Error("UserService.Login Error when generate token: " + err.Error())
-		return nil, error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+		log.Error("UserService.issueTokens Error when generate token: " + err.Error())
+		return nil, 0, error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+
+	// The refresh token is an opaque random string; only its hash is stored
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		log.Error("UserService.issueTokens Error when generate refresh token: " + err.Error())
+		return nil, 0, error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+	plainRefreshToken := base64.RawURLEncoding.EncodeToString(randomBytes)
+
+	refreshToken := &entity.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: hashRefreshToken(plainRefreshToken),
+		ExpiresAt: now.Add(constants.REFRESH_TOKEN_DURATION),
+	}
+	err = s.refreshTokenRepository.CreateCommand(ctx, refreshToken, tx)
+	if err != nil {
+		log.Error("UserService.issueTokens Error when create refresh token: " + err.Error())
+		return nil, 0, error_utils.ErrorCode.DB_DOWN
 	}
 
 	return &model.LoginResponse{
-		Token:    token,
-		Username: user.Username,
-	}, ""
+		Token:                 accessToken,
+		TokenExpiresAt:        now.Add(constants.ACCESS_TOKEN_DURATION),
+		RefreshToken:          plainRefreshToken,
+		RefreshTokenExpiresAt: refreshToken.ExpiresAt,
+		Username:              user.Username,
+		Role:                  user.Role,
+	}, refreshToken.ID, ""
+}
+
+// Helper to check whether the attempt's username or client IP is still locked out by earlier failures
+func (s *UserService) isLoginLocked(ctx *gin.Context, attempt *entity.LoginAttempt) (bool, string) {
+	since := attempt.AttemptedAt.Add(-constants.LOGIN_FAILURE_WINDOW)
+
+	usernameStats, err := s.loginAttemptRepository.GetFailureStatsByUsernameQuery(ctx, attempt.Username, since, nil)
+	if err != nil {
+		log.Error("UserService.isLoginLocked Error when get failures by username: " + err.Error())
+		return false, error_utils.ErrorCode.DB_DOWN
+	}
+	if loginLockedUntil(usernameStats, constants.LOGIN_MAX_FAILURES_PER_USERNAME).After(attempt.AttemptedAt) {
+		return true, ""
+	}
+
+	ipStats, err := s.loginAttemptRepository.GetFailureStatsByIPAddressQuery(ctx, attempt.IPAddress, since, nil)
+	if err != nil {
+		log.Error("UserService.isLoginLocked Error when get failures by IP address: " + err.Error())
+		return false, error_utils.ErrorCode.DB_DOWN
+	}
+	return loginLockedUntil(ipStats, constants.LOGIN_MAX_FAILURES_PER_IP).After(attempt.AttemptedAt), ""
+}
+
+// Helper to store the audit row of a login attempt; an empty failure reason means the login succeeded.
+// A failure to store it is only logged so the audit trail never blocks a login.
+func (s *UserService) recordLoginAttempt(ctx *gin.Context, attempt *entity.LoginAttempt, failureReason string) {
+	attempt.Success = failureReason == ""
+	if !attempt.Success {
+		attempt.FailureReason = &failureReason
+	}
+
+	if err := s.loginAttemptRepository.CreateCommand(ctx, attempt, nil); err != nil {
+		log.Error("UserService.recordLoginAttempt Error when create login attempt: " + err.Error())
+	}
+}
+
+// loginLockedUntil returns the end of the lockout caused by the failures, or the zero time when there is none.
+// Every failure past the allowed number doubles the lockout, up to the maximum duration.
+func loginLockedUntil(stats *entity.LoginFailureStats, maxFailures int) time.Time {
+	if stats.FailureCount < maxFailures || stats.LastFailureAt == nil {
+		return time.Time{}
+	}
+
+	duration := constants.LOGIN_LOCKOUT_MAX_DURATION
+	if exponent := stats.FailureCount - maxFailures; exponent < 16 {
+		duration = min(constants.LOGIN_LOCKOUT_BASE_DURATION<<exponent, constants.LOGIN_LOCKOUT_MAX_DURATION)
+	}
+	return stats.LastFailureAt.Add(duration)
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func toUserResponse(user entity.User) model.UserResponse {
+	return model.UserResponse{
+		ID:        user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt,
+	}
 }
@@ -0,0 +1,147 @@
+package serviceimplement
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/order-app-backend/internal/domain/entity"
+	"github.com/pna/order-app-backend/internal/domain/model"
+	"github.com/pna/order-app-backend/internal/repository"
+	"github.com/pna/order-app-backend/internal/service"
+	"github.com/pna/order-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type WarehouseService struct {
+	warehouseRepository repository.WarehouseRepository
+	inventoryRepository repository.InventoryRepository
+	unitOfWork          repository.UnitOfWork
+}
+
+func NewWarehouseService(warehouseRepository repository.WarehouseRepository, inventoryRepository repository.InventoryRepository, unitOfWork repository.UnitOfWork) service.WarehouseService {
+	return &WarehouseService{
+		warehouseRepository: warehouseRepository,
+		inventoryRepository: inventoryRepository,
+		unitOfWork:          unitOfWork,
+	}
+}
+
+func (s *WarehouseService) Create(ctx *gin.Context, request model.CreateWarehouseRequest) (*model.WarehouseResponse, string) {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("WarehouseService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("WarehouseService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	warehouse := &entity.Warehouse{
+		Name:      request.Name,
+		Address:   request.Address,
+		CreatedAt: time.Now(),
+	}
+	err = s.warehouseRepository.CreateCommand(ctx, warehouse, tx)
+	if err != nil {
+		log.Error("WarehouseService.Create Error when create warehouse: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Every product gets an empty inventory in the new warehouse so stock can be moved into it
+	err = s.inventoryRepository.CreateForAllProductsCommand(ctx, warehouse.ID, tx)
+	if err != nil {
+		log.Error("WarehouseService.Create Error when create inventories: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("WarehouseService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toWarehouseResponse(*warehouse)
+	return &response, ""
+}
+
+func (s *WarehouseService) Update(ctx *gin.Context, warehouseID int, request model.UpdateWarehouseRequest) (*model.WarehouseResponse, string) {
+	warehouse, err := s.warehouseRepository.GetOneByIDQuery(ctx, warehouseID, nil)
+	if err != nil {
+		log.Error("WarehouseService.Update Error when get warehouse: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if warehouse == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	// Only update fields that are not empty
+	if request.Name != "" {
+		warehouse.Name = request.Name
+	}
+	if request.Address != "" {
+		warehouse.Address = request.Address
+	}
+
+	err = s.warehouseRepository.UpdateCommand(ctx, warehouse, nil)
+	if err != nil {
+		log.Error("WarehouseService.Update Error when update warehouse: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toWarehouseResponse(*warehouse)
+	return &response, ""
+}
+
+func (s *WarehouseService) GetAll(ctx *gin.Context) (*model.GetAllWarehousesResponse, string) {
+	warehouses, err := s.warehouseRepository.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("WarehouseService.GetAll Error when get warehouses: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	warehouseResponses := make([]model.WarehouseResponse, len(warehouses))
+	for i, warehouse := range warehouses {
+		warehouseResponses[i] = toWarehouseResponse(warehouse)
+	}
+
+	return &model.GetAllWarehousesResponse{
+		Warehouses: warehouseResponses,
+	}, ""
+}
+
+func toWarehouseResponse(warehouse entity.Warehouse) model.WarehouseResponse {
+	return model.WarehouseResponse{
+		ID:        warehouse.ID,
+		Name:      warehouse.Name,
+		Address:   warehouse.Address,
+		IsDefault: warehouse.IsDefault,
+		CreatedAt: warehouse.CreatedAt,
+	}
+}
+
+// Helper to resolve the warehouse a request refers to, falling back to the default warehouse when none is given
+func resolveWarehouseID(ctx context.Context, warehouseRepository repository.WarehouseRepository, warehouseID *int, tx *sqlx.Tx) (int, string) {
+	var warehouse *entity.Warehouse
+	var err error
+	if warehouseID != nil {
+		warehouse, err = warehouseRepository.GetOneByIDQuery(ctx, *warehouseID, tx)
+	} else {
+		warehouse, err = warehouseRepository.GetDefaultQuery(ctx, tx)
+	}
+	if err != nil {
+		log.Error("resolveWarehouseID Error when get warehouse: " + err.Error())
+		return 0, error_utils.ErrorCode.DB_DOWN
+	}
+	if warehouse == nil {
+		return 0, error_utils.ErrorCode.NOT_FOUND
+	}
+	return warehouse.ID, ""
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"io"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/model"
+)
+
+type ImportService interface {
+	ImportProducts(ctx *gin.Context, file io.Reader, format string, dryRun bool) (*model.ImportProductsResponse, string)
+	ImportCustomers(ctx *gin.Context, file io.Reader, format string, dryRun bool) (*model.ImportCustomersResponse, string)
+}
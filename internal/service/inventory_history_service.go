@@ -1,11 +1,14 @@
 package service
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/pna/order-app-backend/internal/domain/model"
 )
 
 type InventoryHistoryService interface {
-	GetAll(ctx *gin.Context, productID int) (*model.GetAllInventoryHistoriesResponse, string)
+	GetAll(ctx *gin.Context, productID int, warehouseID int) (*model.GetAllInventoryHistoriesResponse, string)
+	GetMovements(ctx *gin.Context, fromDate time.Time, toDate time.Time, productID int, warehouseID int) (*model.GetInventoryMovementsResponse, string)
 	Create(ctx *gin.Context, request model.CreateInventoryHistoryRequest) (*model.InventoryHistoryResponse, string)
 }
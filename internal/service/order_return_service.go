@@ -0,0 +1,11 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/model"
+)
+
+type OrderReturnService interface {
+	Create(ctx *gin.Context, orderID int, request model.CreateOrderReturnRequest) (*model.OrderReturnResponse, string)
+	GetAllByOrderID(ctx *gin.Context, orderID int) (*model.GetAllOrderReturnsResponse, string)
+}
@@ -2,16 +2,19 @@ package service
 
 import (
 	"context"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pna/order-app-backend/internal/domain/model"
 )
 
 type OrderService interface {
-	GetAll(ctx context.Context, userID int, customerID int, deliveryStatuses string, sortBy string, fromDate *time.Time, toDate *time.Time) (model.GetAllOrdersResponse, string)
+	GetAll(ctx context.Context, req model.GetAllOrdersRequest) (model.GetAllOrdersResponse, string)
 	GetOne(ctx context.Context, id int) (model.GetOneOrderResponse, string)
 	Create(ctx *gin.Context, req model.CreateOrderRequest) string
-	Update(ctx context.Context, req model.UpdateOrderRequest) string
+	Update(ctx *gin.Context, req model.UpdateOrderRequest) string
+	UpdateItems(ctx *gin.Context, orderID int, req model.UpdateOrderItemsRequest) string
+	UpdateStatus(ctx *gin.Context, orderID int, req model.UpdateOrderStatusRequest) string
+	GetStatusHistory(ctx context.Context, orderID int) (model.GetAllOrderStatusHistoriesResponse, string)
+	Cancel(ctx *gin.Context, orderID int, req model.CancelOrderRequest) string
 	Delete(ctx *gin.Context, id int) string
 }
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/model"
+)
+
+type PaymentService interface {
+	Create(ctx *gin.Context, orderID int, request model.CreatePaymentRequest) (*model.PaymentResponse, string)
+	GetAllByOrderID(ctx *gin.Context, orderID int) (*model.GetAllPaymentsResponse, string)
+	Delete(ctx *gin.Context, orderID int, paymentID int) string
+	GetCustomerBalance(ctx *gin.Context, customerID int) (*model.CustomerBalanceResponse, string)
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/model"
+)
+
+type PriceListService interface {
+	CreateProductPrice(ctx *gin.Context, request model.CreateProductPriceRequest) (*model.ProductPriceResponse, string)
+	GetAllProductPrices(ctx *gin.Context, productID int, customerID int) (*model.GetAllProductPricesResponse, string)
+	DeleteProductPrice(ctx *gin.Context, id int) string
+	CreateCustomerDiscount(ctx *gin.Context, request model.CreateCustomerDiscountRequest) (*model.CustomerDiscountResponse, string)
+	GetAllCustomerDiscounts(ctx *gin.Context, customerID int) (*model.GetAllCustomerDiscountsResponse, string)
+	DeleteCustomerDiscount(ctx *gin.Context, id int) string
+	GetSuggestedPrice(ctx *gin.Context, customerID int, productID int, date time.Time) (*model.SuggestedPriceResponse, string)
+}
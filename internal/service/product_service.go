@@ -10,4 +10,5 @@ type ProductService interface {
 	Update(ctx *gin.Context, request model.UpdateProductRequest) (*model.ProductResponse, string)
 	GetAll(ctx *gin.Context) (*model.GetAllProductsResponse, string)
 	GetOne(ctx *gin.Context, id int) (*model.GetOneProductResponse, string)
+	GetCostHistories(ctx *gin.Context, productID int) (*model.GetProductCostHistoriesResponse, string)
 }
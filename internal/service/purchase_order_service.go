@@ -0,0 +1,14 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/model"
+)
+
+type PurchaseOrderService interface {
+	Create(ctx *gin.Context, request model.CreatePurchaseOrderRequest) (*model.PurchaseOrderResponse, string)
+	GetAll(ctx *gin.Context, supplierID int, statuses []string) (*model.GetAllPurchaseOrdersResponse, string)
+	GetOne(ctx *gin.Context, id int) (*model.PurchaseOrderResponse, string)
+	Receive(ctx *gin.Context, id int, request model.ReceivePurchaseOrderRequest) (*model.PurchaseOrderResponse, string)
+	Cancel(ctx *gin.Context, id int) (*model.PurchaseOrderResponse, string)
+}
@@ -2,10 +2,14 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/pna/order-app-backend/internal/domain/model"
 )
 
 type StatisticsService interface {
 	GetDashboardStats(ctx context.Context) (model.DashboardStatsResponse, string)
+	GetReportByPeriod(ctx context.Context, groupBy string, fromDate time.Time, toDate time.Time) (model.GetPeriodReportResponse, string)
+	GetReportByCustomer(ctx context.Context, fromDate time.Time, toDate time.Time) (model.GetCustomerReportResponse, string)
+	GetReportByProduct(ctx context.Context, fromDate time.Time, toDate time.Time) (model.GetProductReportResponse, string)
 }
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/model"
+)
+
+type StockTransferService interface {
+	Create(ctx *gin.Context, request model.CreateStockTransferRequest) (*model.StockTransferResponse, string)
+	GetAll(ctx *gin.Context, productID int, warehouseID int) (*model.GetAllStockTransfersResponse, string)
+}
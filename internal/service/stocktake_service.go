@@ -0,0 +1,15 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/model"
+)
+
+type StocktakeService interface {
+	Open(ctx *gin.Context, request model.OpenStocktakeRequest) (*model.StocktakeResponse, string)
+	GetAll(ctx *gin.Context, warehouseID int, statuses []string) (*model.GetAllStocktakesResponse, string)
+	GetOne(ctx *gin.Context, id int) (*model.StocktakeResponse, string)
+	UpdateCounts(ctx *gin.Context, id int, request model.UpdateStocktakeCountsRequest) (*model.StocktakeResponse, string)
+	Post(ctx *gin.Context, id int) (*model.StocktakeResponse, string)
+	Cancel(ctx *gin.Context, id int) (*model.StocktakeResponse, string)
+}
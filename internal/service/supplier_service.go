@@ -0,0 +1,13 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/model"
+)
+
+type SupplierService interface {
+	Create(ctx *gin.Context, request model.CreateSupplierRequest) (*model.SupplierResponse, string)
+	Update(ctx *gin.Context, supplierID int, request model.UpdateSupplierRequest) (*model.SupplierResponse, string)
+	GetAll(ctx *gin.Context) (*model.GetAllSuppliersResponse, string)
+	GetOne(ctx *gin.Context, id int) (*model.GetOneSupplierResponse, string)
+}
@@ -7,4 +7,12 @@ import (
 
 type UserService interface {
 	Login(ctx *gin.Context, request model.LoginRequest) (*model.LoginResponse, string)
+	Refresh(ctx *gin.Context, request model.RefreshTokenRequest) (*model.LoginResponse, string)
+	Logout(ctx *gin.Context, request model.LogoutRequest) string
+	Create(ctx *gin.Context, request model.CreateUserRequest) (*model.UserResponse, string)
+	GetAll(ctx *gin.Context) (*model.GetAllUsersResponse, string)
+	UpdateStatus(ctx *gin.Context, userID int, request model.UpdateUserStatusRequest) (*model.UserResponse, string)
+	ResetPassword(ctx *gin.Context, userID int, request model.ResetPasswordRequest) string
+	ChangePassword(ctx *gin.Context, request model.ChangePasswordRequest) string
+	GetLoginAttempts(ctx *gin.Context, request model.GetLoginAttemptsRequest) (*model.GetLoginAttemptsResponse, string)
 }
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/order-app-backend/internal/domain/model"
+)
+
+type WarehouseService interface {
+	Create(ctx *gin.Context, request model.CreateWarehouseRequest) (*model.WarehouseResponse, string)
+	Update(ctx *gin.Context, warehouseID int, request model.UpdateWarehouseRequest) (*model.WarehouseResponse, string)
+	GetAll(ctx *gin.Context) (*model.GetAllWarehousesResponse, string)
+}
@@ -2,7 +2,16 @@ package constants
 
 import "time"
 
-const ACCESS_TOKEN_DURATION = 30 * 24 * time.Hour
+// Access tokens are short-lived; clients renew them with the refresh token, which rotates on every use
+const ACCESS_TOKEN_DURATION = 15 * time.Minute
 const REFRESH_TOKEN_DURATION = 30 * 24 * time.Hour // 30 days
 // const ACCESS_TOKEN_DURATION = 20 * time.Second
 // const REFRESH_TOKEN_DURATION = 60 * time.Second
+
+// Login lockout: after the allowed number of failures, each further failure doubles the wait, up to the maximum.
+// The per-IP limit is higher because staff in one shop usually share an IP address.
+const LOGIN_MAX_FAILURES_PER_USERNAME = 5
+const LOGIN_MAX_FAILURES_PER_IP = 20
+const LOGIN_LOCKOUT_BASE_DURATION = 30 * time.Second
+const LOGIN_LOCKOUT_MAX_DURATION = 30 * time.Minute
+const LOGIN_FAILURE_WINDOW = 24 * time.Hour // failures older than this are forgotten
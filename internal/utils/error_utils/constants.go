@@ -15,6 +15,15 @@ func (e *ConstraintViolationError) Error() string {
 	return e.Message
 }
 
+// ReservedQuantityViolationError represents a change that would leave less stock on hand than is reserved for pending orders
+type ReservedQuantityViolationError struct {
+	Message string
+}
+
+func (e *ReservedQuantityViolationError) Error() string {
+	return e.Message
+}
+
 // VersionMismatchError represents an optimistic locking failure
 type VersionMismatchError struct {
 	Message string
@@ -39,32 +48,76 @@ type errorCode struct {
 	DB_DOWN string
 
 	// auth related
-	FORBIDDEN                   string
-	INTERNAL_SERVER_ERROR       string
-	BAD_REQUEST                 string
-	ACCESS_TOKEN_INVALID        string
-	USERNAME_NOT_FOUND          string
-	UNAUTHORIZED                string
-	INVENTORY_VERSION_MISMATCH  string
-	INVENTORY_QUANTITY_NEGATIVE string
-	INVENTORY_QUANTITY_EXCEEDED string
-	DUPLICATE_ORDER_ITEMS       string
+	FORBIDDEN                       string
+	INTERNAL_SERVER_ERROR           string
+	BAD_REQUEST                     string
+	ACCESS_TOKEN_INVALID            string
+	UNAUTHORIZED                    string
+	INVENTORY_VERSION_MISMATCH      string
+	INVENTORY_QUANTITY_NEGATIVE     string
+	INVENTORY_QUANTITY_EXCEEDED     string
+	DUPLICATE_ORDER_ITEMS           string
+	PAYMENT_AMOUNT_EXCEEDED         string
+	ORDER_STATUS_TRANSITION_INVALID string
+	ORDER_CANCELLED                 string
+	ORDER_NOT_DELIVERED             string
+	RETURN_QUANTITY_EXCEEDED        string
+	IMPORT_FILE_INVALID             string
+	IMPORT_ROWS_INVALID             string
+	USER_DISABLED                   string
+	USERNAME_ALREADY_EXISTS         string
+	PASSWORD_INCORRECT              string
+	CANNOT_DISABLE_SELF             string
+	REFRESH_TOKEN_INVALID           string
+	INVALID_CREDENTIALS             string
+	LOGIN_LOCKED                    string
+	PURCHASE_ORDER_CLOSED           string
+	RECEIVE_QUANTITY_EXCEEDED       string
+	STOCKTAKE_ALREADY_OPEN          string
+	STOCKTAKE_CLOSED                string
+	STOCKTAKE_RECOUNT_REQUIRED      string
+	PRICE_LIST_ENTRY_EXISTS         string
+	CUSTOMER_DELETED                string
+	CUSTOMER_MERGE_INVALID          string
+	STOCKTAKE_BELOW_RESERVED        string
 
 	// generic
 	NOT_FOUND string
 }
 
 var ErrorCode = errorCode{
-	DB_DOWN:                     "DB_DOWN",
-	FORBIDDEN:                   "FORBIDDEN",
-	BAD_REQUEST:                 "BAD_REQUEST",
-	INTERNAL_SERVER_ERROR:       "INTERNAL_SERVER_ERROR",
-	ACCESS_TOKEN_INVALID:        "ACCESS_TOKEN_INVALID",
-	USERNAME_NOT_FOUND:          "USER_NOT_FOUND",
-	UNAUTHORIZED:                "UNAUTHORIZED",
-	NOT_FOUND:                   "NOT_FOUND",
-	INVENTORY_VERSION_MISMATCH:  "INVENTORY_VERSION_MISMATCH",
-	INVENTORY_QUANTITY_NEGATIVE: "INVENTORY_QUANTITY_NEGATIVE",
-	INVENTORY_QUANTITY_EXCEEDED: "INVENTORY_QUANTITY_EXCEEDED",
-	DUPLICATE_ORDER_ITEMS:       "DUPLICATE_ORDER_ITEMS",
+	DB_DOWN:                         "DB_DOWN",
+	FORBIDDEN:                       "FORBIDDEN",
+	BAD_REQUEST:                     "BAD_REQUEST",
+	INTERNAL_SERVER_ERROR:           "INTERNAL_SERVER_ERROR",
+	ACCESS_TOKEN_INVALID:            "ACCESS_TOKEN_INVALID",
+	UNAUTHORIZED:                    "UNAUTHORIZED",
+	NOT_FOUND:                       "NOT_FOUND",
+	INVENTORY_VERSION_MISMATCH:      "INVENTORY_VERSION_MISMATCH",
+	INVENTORY_QUANTITY_NEGATIVE:     "INVENTORY_QUANTITY_NEGATIVE",
+	INVENTORY_QUANTITY_EXCEEDED:     "INVENTORY_QUANTITY_EXCEEDED",
+	DUPLICATE_ORDER_ITEMS:           "DUPLICATE_ORDER_ITEMS",
+	PAYMENT_AMOUNT_EXCEEDED:         "PAYMENT_AMOUNT_EXCEEDED",
+	ORDER_STATUS_TRANSITION_INVALID: "ORDER_STATUS_TRANSITION_INVALID",
+	ORDER_CANCELLED:                 "ORDER_CANCELLED",
+	ORDER_NOT_DELIVERED:             "ORDER_NOT_DELIVERED",
+	RETURN_QUANTITY_EXCEEDED:        "RETURN_QUANTITY_EXCEEDED",
+	IMPORT_FILE_INVALID:             "IMPORT_FILE_INVALID",
+	IMPORT_ROWS_INVALID:             "IMPORT_ROWS_INVALID",
+	USER_DISABLED:                   "USER_DISABLED",
+	USERNAME_ALREADY_EXISTS:         "USERNAME_ALREADY_EXISTS",
+	PASSWORD_INCORRECT:              "PASSWORD_INCORRECT",
+	CANNOT_DISABLE_SELF:             "CANNOT_DISABLE_SELF",
+	REFRESH_TOKEN_INVALID:           "REFRESH_TOKEN_INVALID",
+	INVALID_CREDENTIALS:             "INVALID_CREDENTIALS",
+	LOGIN_LOCKED:                    "LOGIN_LOCKED",
+	PURCHASE_ORDER_CLOSED:           "PURCHASE_ORDER_CLOSED",
+	RECEIVE_QUANTITY_EXCEEDED:       "RECEIVE_QUANTITY_EXCEEDED",
+	STOCKTAKE_ALREADY_OPEN:          "STOCKTAKE_ALREADY_OPEN",
+	STOCKTAKE_CLOSED:                "STOCKTAKE_CLOSED",
+	STOCKTAKE_RECOUNT_REQUIRED:      "STOCKTAKE_RECOUNT_REQUIRED",
+	PRICE_LIST_ENTRY_EXISTS:         "PRICE_LIST_ENTRY_EXISTS",
+	CUSTOMER_DELETED:                "CUSTOMER_DELETED",
+	CUSTOMER_MERGE_INVALID:          "CUSTOMER_MERGE_INVALID",
+	STOCKTAKE_BELOW_RESERVED:        "STOCKTAKE_BELOW_RESERVED",
 }
@@ -46,10 +46,171 @@ func ErrorCodeToHttpResponse(errCode string, field string) (statusCode int, http
 	case ErrorCode.DUPLICATE_ORDER_ITEMS:
 		statusCode = http.StatusBadRequest
 		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
-			Message: "Each product can have at most one order item from inventory and one from external",
+			Message: "Each product can have at most one order item per warehouse and one from external",
 			Field:   field,
 			Code:    ErrorCode.DUPLICATE_ORDER_ITEMS,
 		})
+	case ErrorCode.PAYMENT_AMOUNT_EXCEEDED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Payment amount exceeds the outstanding amount of the order",
+			Field:   field,
+			Code:    ErrorCode.PAYMENT_AMOUNT_EXCEEDED,
+		})
+	case ErrorCode.ORDER_STATUS_TRANSITION_INVALID:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "The order cannot move from its current delivery status to the requested one",
+			Field:   field,
+			Code:    ErrorCode.ORDER_STATUS_TRANSITION_INVALID,
+		})
+	case ErrorCode.ORDER_CANCELLED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "The order has been cancelled and can no longer be changed",
+			Field:   field,
+			Code:    ErrorCode.ORDER_CANCELLED,
+		})
+	case ErrorCode.ORDER_NOT_DELIVERED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Goods of the order have not been delivered yet",
+			Field:   field,
+			Code:    ErrorCode.ORDER_NOT_DELIVERED,
+		})
+	case ErrorCode.RETURN_QUANTITY_EXCEEDED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Returned quantity exceeds the quantity sold",
+			Field:   field,
+			Code:    ErrorCode.RETURN_QUANTITY_EXCEEDED,
+		})
+	case ErrorCode.IMPORT_FILE_INVALID:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "The file cannot be read or has no header row with the required columns",
+			Field:   field,
+			Code:    ErrorCode.IMPORT_FILE_INVALID,
+		})
+	case ErrorCode.IMPORT_ROWS_INVALID:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Some rows are invalid, nothing was imported",
+			Field:   field,
+			Code:    ErrorCode.IMPORT_ROWS_INVALID,
+		})
+	case ErrorCode.USER_DISABLED:
+		statusCode = http.StatusForbidden
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "This account has been disabled",
+			Field:   field,
+			Code:    ErrorCode.USER_DISABLED,
+		})
+	case ErrorCode.USERNAME_ALREADY_EXISTS:
+		statusCode = http.StatusConflict
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Username already exists",
+			Field:   field,
+			Code:    ErrorCode.USERNAME_ALREADY_EXISTS,
+		})
+	case ErrorCode.PASSWORD_INCORRECT:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Current password is incorrect",
+			Field:   field,
+			Code:    ErrorCode.PASSWORD_INCORRECT,
+		})
+	case ErrorCode.CANNOT_DISABLE_SELF:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "You cannot disable your own account",
+			Field:   field,
+			Code:    ErrorCode.CANNOT_DISABLE_SELF,
+		})
+	case ErrorCode.REFRESH_TOKEN_INVALID:
+		statusCode = http.StatusUnauthorized
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Invalid or expired refresh token",
+			Field:   field,
+			Code:    ErrorCode.REFRESH_TOKEN_INVALID,
+		})
+	case ErrorCode.INVALID_CREDENTIALS:
+		statusCode = http.StatusUnauthorized
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Invalid username or password",
+			Field:   field,
+			Code:    ErrorCode.INVALID_CREDENTIALS,
+		})
+	case ErrorCode.LOGIN_LOCKED:
+		statusCode = http.StatusTooManyRequests
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Too many failed login attempts, please try again later",
+			Field:   field,
+			Code:    ErrorCode.LOGIN_LOCKED,
+		})
+	case ErrorCode.PURCHASE_ORDER_CLOSED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Purchase order is already fully received or cancelled",
+			Field:   field,
+			Code:    ErrorCode.PURCHASE_ORDER_CLOSED,
+		})
+	case ErrorCode.RECEIVE_QUANTITY_EXCEEDED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Received quantity exceeds the quantity still expected",
+			Field:   field,
+			Code:    ErrorCode.RECEIVE_QUANTITY_EXCEEDED,
+		})
+	case ErrorCode.STOCKTAKE_ALREADY_OPEN:
+		statusCode = http.StatusConflict
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "The warehouse already has an open stocktake",
+			Field:   field,
+			Code:    ErrorCode.STOCKTAKE_ALREADY_OPEN,
+		})
+	case ErrorCode.STOCKTAKE_CLOSED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Stocktake is already posted or cancelled",
+			Field:   field,
+			Code:    ErrorCode.STOCKTAKE_CLOSED,
+		})
+	case ErrorCode.STOCKTAKE_RECOUNT_REQUIRED:
+		statusCode = http.StatusConflict
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Inventory changed since these products were counted, please count them again",
+			Field:   field,
+			Code:    ErrorCode.STOCKTAKE_RECOUNT_REQUIRED,
+		})
+	case ErrorCode.PRICE_LIST_ENTRY_EXISTS:
+		statusCode = http.StatusConflict
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "A price list entry already starts on this date",
+			Field:   field,
+			Code:    ErrorCode.PRICE_LIST_ENTRY_EXISTS,
+		})
+	case ErrorCode.CUSTOMER_DELETED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Customer has been deleted",
+			Field:   field,
+			Code:    ErrorCode.CUSTOMER_DELETED,
+		})
+	case ErrorCode.CUSTOMER_MERGE_INVALID:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "A customer cannot be merged into itself or into a deleted customer",
+			Field:   field,
+			Code:    ErrorCode.CUSTOMER_MERGE_INVALID,
+		})
+	case ErrorCode.STOCKTAKE_BELOW_RESERVED:
+		statusCode = http.StatusConflict
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Counted quantity is below the quantity reserved for pending orders",
+			Field:   field,
+			Code:    ErrorCode.STOCKTAKE_BELOW_RESERVED,
+		})
 	case ErrorCode.FORBIDDEN:
 		statusCode = http.StatusForbidden
 		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
@@ -64,13 +225,6 @@ func ErrorCodeToHttpResponse(errCode string, field string) (statusCode int, http
 			Field:   field,
 			Code:    ErrorCode.ACCESS_TOKEN_INVALID,
 		})
-	case ErrorCode.USERNAME_NOT_FOUND:
-		statusCode = http.StatusNotFound
-		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
-			Message: "Username not found",
-			Field:   field,
-			Code:    ErrorCode.USERNAME_NOT_FOUND,
-		})
 	case ErrorCode.NOT_FOUND:
 		statusCode = http.StatusNotFound
 		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
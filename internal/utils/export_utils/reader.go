@@ -0,0 +1,96 @@
+package exportutils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ReadRows reads every row of a CSV file, or of the first sheet of an xlsx workbook, as trimmed strings.
+// Trailing empty rows are dropped; rows keep their position so callers can report spreadsheet row numbers.
+func ReadRows(r io.Reader, format string) ([][]string, error) {
+	var rows [][]string
+
+	switch format {
+	case FormatXLSX:
+		workbook, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer workbook.Close()
+
+		sheets := workbook.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("workbook has no sheet")
+		}
+		rows, err = workbook.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, err
+		}
+	case FormatCSV:
+		reader := bufio.NewReader(r)
+		if prefix, err := reader.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+			reader.Discard(len(utf8BOM))
+		}
+
+		csvReader := csv.NewReader(reader)
+		csvReader.FieldsPerRecord = -1
+		var err error
+		rows, err = csvReader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("unsupported format: " + format)
+	}
+
+	for _, row := range rows {
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+	}
+	for len(rows) > 0 && isEmptyRow(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	return rows, nil
+}
+
+// ColumnIndexes maps each field to the position of the first header matching one of its aliases, case-insensitively.
+// Fields without a matching header are left out.
+func ColumnIndexes(header []string, aliases map[string][]string) map[string]int {
+	indexes := make(map[string]int)
+	for field, names := range aliases {
+		for i, title := range header {
+			if matchesAny(title, names) {
+				indexes[field] = i
+				break
+			}
+		}
+	}
+	return indexes
+}
+
+func matchesAny(title string, names []string) bool {
+	for _, name := range names {
+		if strings.EqualFold(strings.TrimSpace(title), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if cell != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,244 @@
+package exportutils
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatXLSX = "xlsx"
+	FormatCSV  = "csv"
+
+	ContentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	ContentTypeCSV  = "text/csv; charset=utf-8"
+)
+
+// vndNumberFormat shows whole VND amounts with thousand separators, e.g. 1,250,000 ₫
+const vndNumberFormat = `#,##0 "₫";-#,##0 "₫"`
+
+type ColumnType int
+
+const (
+	ColumnText ColumnType = iota
+	ColumnNumber
+	ColumnMoney
+	ColumnDate
+	ColumnPercent
+)
+
+type Column struct {
+	Header string
+	Type   ColumnType
+	Width  float64
+}
+
+// Table is one sheet of an export. Rows hold one value per column; a nil value leaves the cell empty.
+// Summary rows (subtotals, totals) are written in bold.
+type Table struct {
+	SheetName   string
+	Columns     []Column
+	Rows        [][]interface{}
+	SummaryRows map[int]bool
+}
+
+func (t *Table) AddRow(values ...interface{}) {
+	t.Rows = append(t.Rows, values)
+}
+
+func (t *Table) AddSummaryRow(values ...interface{}) {
+	if t.SummaryRows == nil {
+		t.SummaryRows = make(map[int]bool)
+	}
+	t.SummaryRows[len(t.Rows)] = true
+	t.Rows = append(t.Rows, values)
+}
+
+// WriteXLSX writes every table as its own sheet, in order, to w
+func WriteXLSX(w io.Writer, tables ...Table) error {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	styles, err := newStyles(file)
+	if err != nil {
+		return err
+	}
+
+	for i, table := range tables {
+		if i == 0 {
+			if err := file.SetSheetName(file.GetSheetName(0), table.SheetName); err != nil {
+				return err
+			}
+		} else if _, err := file.NewSheet(table.SheetName); err != nil {
+			return err
+		}
+		if err := writeSheet(file, styles, table); err != nil {
+			return err
+		}
+	}
+	file.SetActiveSheet(0)
+
+	return file.Write(w)
+}
+
+// WriteCSV writes a single table as UTF-8 CSV with a BOM so that Excel shows Vietnamese headers correctly.
+// Money is written as plain integers to keep the file machine-readable.
+func WriteCSV(w io.Writer, table Table) error {
+	if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	headers := make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		headers[i] = column.Header
+	}
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+
+	for _, row := range table.Rows {
+		record := make([]string, len(table.Columns))
+		for i := range table.Columns {
+			if i < len(row) {
+				record[i] = formatCSVValue(row[i])
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+type sheetStyles struct {
+	header       int
+	byColumnType map[ColumnType]int
+	bold         map[ColumnType]int
+}
+
+func newStyles(file *excelize.File) (*sheetStyles, error) {
+	vndFormat := vndNumberFormat
+	dateFormat := "dd/mm/yyyy"
+	percentFormat := `0.00"%"`
+	numberFormat := "#,##0"
+
+	header, err := file.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#D9E1F2"}},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	customFormats := map[ColumnType]*string{
+		ColumnText:    nil,
+		ColumnNumber:  &numberFormat,
+		ColumnMoney:   &vndFormat,
+		ColumnDate:    &dateFormat,
+		ColumnPercent: &percentFormat,
+	}
+	styles := &sheetStyles{
+		header:       header,
+		byColumnType: make(map[ColumnType]int),
+		bold:         make(map[ColumnType]int),
+	}
+	for columnType, customFormat := range customFormats {
+		regular, err := file.NewStyle(&excelize.Style{CustomNumFmt: customFormat})
+		if err != nil {
+			return nil, err
+		}
+		bold, err := file.NewStyle(&excelize.Style{CustomNumFmt: customFormat, Font: &excelize.Font{Bold: true}})
+		if err != nil {
+			return nil, err
+		}
+		styles.byColumnType[columnType] = regular
+		styles.bold[columnType] = bold
+	}
+	return styles, nil
+}
+
+func writeSheet(file *excelize.File, styles *sheetStyles, table Table) error {
+	for i, column := range table.Columns {
+		cell, err := excelize.CoordinatesToCellName(i+1, 1)
+		if err != nil {
+			return err
+		}
+		if err := file.SetCellValue(table.SheetName, cell, column.Header); err != nil {
+			return err
+		}
+		if err := file.SetCellStyle(table.SheetName, cell, cell, styles.header); err != nil {
+			return err
+		}
+
+		width := column.Width
+		if width == 0 {
+			width = 16
+		}
+		columnName, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return err
+		}
+		if err := file.SetColWidth(table.SheetName, columnName, columnName, width); err != nil {
+			return err
+		}
+	}
+
+	for r, row := range table.Rows {
+		for i, column := range table.Columns {
+			if i >= len(row) || row[i] == nil {
+				continue
+			}
+			cell, err := excelize.CoordinatesToCellName(i+1, r+2)
+			if err != nil {
+				return err
+			}
+			if err := file.SetCellValue(table.SheetName, cell, row[i]); err != nil {
+				return err
+			}
+			style := styles.byColumnType[column.Type]
+			if table.SummaryRows[r] {
+				style = styles.bold[column.Type]
+			}
+			if err := file.SetCellStyle(table.SheetName, cell, cell, style); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Keep the header visible while scrolling
+	return file.SetPanes(table.SheetName, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+}
+
+func formatCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case time.Time:
+		return v.Format("2006-01-02")
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format("2006-01-02")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,112 @@
+package moneyutils
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var vietnameseDigits = []string{"không", "một", "hai", "ba", "bốn", "năm", "sáu", "bảy", "tám", "chín"}
+
+// FormatVND formats an amount with dot thousand separators, e.g. 1250000 -> "1.250.000"
+func FormatVND(amount int) string {
+	digits := strconv.Itoa(amount)
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		digits = digits[1:]
+	}
+
+	var builder strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			builder.WriteByte('.')
+		}
+		builder.WriteRune(digit)
+	}
+	return sign + builder.String()
+}
+
+// AmountInWords spells out an amount of VND in Vietnamese, e.g. 1250000 -> "Một triệu hai trăm năm mươi nghìn đồng"
+func AmountInWords(amount int) string {
+	words := "không"
+	if amount < 0 {
+		words = "âm " + numberToWords(-amount)
+	} else if amount > 0 {
+		words = numberToWords(amount)
+	}
+
+	runes := []rune(words + " đồng")
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func numberToWords(n int) string {
+	if n >= 1000000000 {
+		words := numberToWords(n/1000000000) + " tỷ"
+		if rest := n % 1000000000; rest > 0 {
+			words += " " + readBelowBillion(rest, true)
+		}
+		return words
+	}
+	return readBelowBillion(n, false)
+}
+
+// readBelowBillion reads n < 1 000 000 000. When full is set, n follows a larger unit and its leading group
+// is read with all its digits, e.g. "không trăm lẻ năm".
+func readBelowBillion(n int, full bool) string {
+	groups := []struct {
+		value int
+		unit  string
+	}{
+		{value: n / 1000000, unit: "triệu"},
+		{value: n / 1000 % 1000, unit: "nghìn"},
+		{value: n % 1000, unit: ""},
+	}
+
+	parts := make([]string, 0, len(groups)*2)
+	for _, group := range groups {
+		if group.value == 0 {
+			continue
+		}
+		parts = append(parts, readGroup(group.value, full))
+		if group.unit != "" {
+			parts = append(parts, group.unit)
+		}
+		full = true
+	}
+	return strings.Join(parts, " ")
+}
+
+// readGroup reads a group of 3 digits
+func readGroup(n int, full bool) string {
+	hundreds, tens, units := n/100, n/10%10, n%10
+
+	parts := make([]string, 0, 4)
+	if full || hundreds > 0 {
+		parts = append(parts, vietnameseDigits[hundreds], "trăm")
+	}
+
+	switch tens {
+	case 0:
+		if units > 0 && (full || hundreds > 0) {
+			parts = append(parts, "lẻ")
+		}
+	case 1:
+		parts = append(parts, "mười")
+	default:
+		parts = append(parts, vietnameseDigits[tens], "mươi")
+	}
+
+	switch {
+	case units == 0:
+	case units == 1 && tens >= 2:
+		parts = append(parts, "mốt")
+	case units == 5 && tens >= 1:
+		parts = append(parts, "lăm")
+	default:
+		parts = append(parts, vietnameseDigits[units])
+	}
+
+	return strings.Join(parts, " ")
+}
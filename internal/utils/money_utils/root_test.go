@@ -0,0 +1,58 @@
+package moneyutils
+
+import "testing"
+
+func TestFormatVND(t *testing.T) {
+	tests := []struct {
+		amount int
+		want   string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1.000"},
+		{1250000, "1.250.000"},
+		{-1250000, "-1.250.000"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatVND(tt.amount); got != tt.want {
+			t.Errorf("FormatVND(%d) = %q; want %q", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestAmountInWords(t *testing.T) {
+	tests := []struct {
+		amount int
+		want   string
+	}{
+		{0, "Không đồng"},
+		{5, "Năm đồng"},
+		{10, "Mười đồng"},
+		{11, "Mười một đồng"},
+		{15, "Mười lăm đồng"},
+		{21, "Hai mươi mốt đồng"},
+		{25, "Hai mươi lăm đồng"},
+		{105, "Một trăm lẻ năm đồng"},
+		{110, "Một trăm mười đồng"},
+		{115, "Một trăm mười lăm đồng"},
+		{121, "Một trăm hai mươi mốt đồng"},
+		{1001, "Một nghìn không trăm lẻ một đồng"},
+		{1015, "Một nghìn không trăm mười lăm đồng"},
+		{250000, "Hai trăm năm mươi nghìn đồng"},
+		{1000000, "Một triệu đồng"},
+		{1000005, "Một triệu không trăm lẻ năm đồng"},
+		{1005000, "Một triệu không trăm lẻ năm nghìn đồng"},
+		{1250000, "Một triệu hai trăm năm mươi nghìn đồng"},
+		{2000000000, "Hai tỷ đồng"},
+		{2000021000, "Hai tỷ không trăm hai mươi mốt nghìn đồng"},
+		{1000000000015, "Một nghìn tỷ không trăm mười lăm đồng"},
+		{-21000, "Âm hai mươi mốt nghìn đồng"},
+	}
+
+	for _, tt := range tests {
+		if got := AmountInWords(tt.amount); got != tt.want {
+			t.Errorf("AmountInWords(%d) = %q; want %q", tt.amount, got, tt.want)
+		}
+	}
+}
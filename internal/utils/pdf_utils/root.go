@@ -0,0 +1,92 @@
+package pdfutils
+
+import (
+	"bytes"
+	_ "embed"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	ContentTypePDF = "application/pdf"
+
+	// FontFamily is a Unicode font so Vietnamese diacritics render correctly
+	FontFamily = "DejaVu"
+)
+
+//go:embed fonts/DejaVuSansCondensed.ttf
+var regularFont []byte
+
+//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+var boldFont []byte
+
+// Column of a table drawn with DrawTable
+type Column struct {
+	Header string
+	Width  float64
+	Align  string // "L", "C" or "R"
+}
+
+// NewDocument creates an A4 portrait document with the Unicode font registered and a first page added
+func NewDocument() (*fpdf.Fpdf, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(FontFamily, "", regularFont)
+	pdf.AddUTF8FontFromBytes(FontFamily, "B", boldFont)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AddPage()
+	pdf.SetFont(FontFamily, "", 10)
+	return pdf, pdf.Error()
+}
+
+// DrawTable draws a header row and the given rows, repeating the header when a row breaks onto a new page
+func DrawTable(pdf *fpdf.Fpdf, columns []Column, rows [][]string) {
+	const lineHeight = 5.0
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottomMargin := pdf.GetMargins()
+
+	drawHeader := func() {
+		pdf.SetFont(FontFamily, "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for _, column := range columns {
+			pdf.CellFormat(column.Width, 7, column.Header, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont(FontFamily, "", 9)
+	}
+
+	drawHeader()
+	for _, row := range rows {
+		// Row height follows the cell that wraps onto the most lines
+		lines := 1
+		for i, column := range columns {
+			if n := len(pdf.SplitText(row[i], column.Width-2)); n > lines {
+				lines = n
+			}
+		}
+		rowHeight := float64(lines) * lineHeight
+
+		if pdf.GetY()+rowHeight > pageHeight-bottomMargin {
+			pdf.AddPage()
+			drawHeader()
+		}
+
+		x, y := pdf.GetXY()
+		for i, column := range columns {
+			pdf.Rect(x, y, column.Width, rowHeight, "D")
+			pdf.SetXY(x, y)
+			pdf.MultiCell(column.Width, lineHeight, row[i], "", column.Align, false)
+			x += column.Width
+		}
+		pdf.SetY(y + rowHeight)
+	}
+}
+
+// Render renders the document into memory
+func Render(pdf *fpdf.Fpdf) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -1,6 +1,7 @@
 package stringutils
 
 import (
+	"strings"
 	"unicode"
 )
 
@@ -15,3 +16,41 @@ func FirstLetterToLower(s string) string {
 
 	return string(r)
 }
+
+// Truncate cuts s to at most maxLength characters, counting runes so multi-byte characters are never split
+func Truncate(s string, maxLength int) string {
+	r := []rune(s)
+	if len(r) <= maxLength {
+		return s
+	}
+
+	return string(r[:maxLength])
+}
+
+// NormalizePhone keeps only the digits of a phone number and turns the 0084 or 84 country code into the leading 0,
+// so the different ways a number gets typed in compare equal.
+// It has to agree with the normalized_phone backfill of migration 20250703270000.
+func NormalizePhone(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+
+	if strings.HasPrefix(digits, "0084") && len(digits) >= 13 {
+		digits = digits[2:]
+	}
+	if strings.HasPrefix(digits, "84") && len(digits) >= 11 {
+		return "0" + digits[2:]
+	}
+	return digits
+}
+
+// likeEscaper escapes the LIKE wildcards with MySQL's default escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// ContainsPattern builds a LIKE pattern matching s anywhere, with any % or _ in s matched literally
+func ContainsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
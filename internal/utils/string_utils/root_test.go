@@ -0,0 +1,53 @@
+package stringutils
+
+import "testing"
+
+// The expectations are also what the normalized_phone backfill of migration 20250703270000 produces for the same input
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+	}{
+		{"", ""},
+		{"0912345678", "0912345678"},
+		{"0912 345 678", "0912345678"},
+		{"0912.345.678", "0912345678"},
+		{"0912-345-678", "0912345678"},
+		{"+84 912 345 678", "0912345678"},
+		{"+84912345678", "0912345678"},
+		{"84912345678", "0912345678"},
+		{"0084 912 345 678", "0912345678"},
+		{"0084912345678", "0912345678"},
+		{"(+84) 28 3822 1234", "02838221234"},
+		{"8412345", "8412345"},
+		{"841234567", "841234567"},
+		{"00841234", "00841234"},
+		{"không có", ""},
+		{"０９１２", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizePhone(tt.phone); got != tt.want {
+			t.Errorf("NormalizePhone(%q) = %q; want %q", tt.phone, got, tt.want)
+		}
+	}
+}
+
+func TestContainsPattern(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"", "%%"},
+		{"0912", "%0912%"},
+		{"50%", `%50\%%`},
+		{"a_b", `%a\_b%`},
+		{`a\b`, `%a\\b%`},
+	}
+
+	for _, tt := range tests {
+		if got := ContainsPattern(tt.s); got != tt.want {
+			t.Errorf("ContainsPattern(%q) = %q; want %q", tt.s, got, tt.want)
+		}
+	}
+}
@@ -36,6 +36,18 @@ var handlerSet = wire.NewSet(
 	v1.NewOrderHandler,
 	v1.NewOrderImageHandler,
 	v1.NewStatisticsHandler,
+	v1.NewPaymentHandler,
+	v1.NewOrderReturnHandler,
+	v1.NewExportHandler,
+	v1.NewDocumentHandler,
+	v1.NewImportHandler,
+	v1.NewAuditLogHandler,
+	v1.NewSupplierHandler,
+	v1.NewPurchaseOrderHandler,
+	v1.NewWarehouseHandler,
+	v1.NewStockTransferHandler,
+	v1.NewStocktakeHandler,
+	v1.NewPriceListHandler,
 )
 
 var serviceSet = wire.NewSet(
@@ -48,6 +60,18 @@ var serviceSet = wire.NewSet(
 	serviceimplement.NewOrderService,
 	serviceimplement.NewOrderImageService,
 	serviceimplement.NewStatisticsService,
+	serviceimplement.NewPaymentService,
+	serviceimplement.NewOrderReturnService,
+	serviceimplement.NewExportService,
+	serviceimplement.NewDocumentService,
+	serviceimplement.NewImportService,
+	serviceimplement.NewAuditLogService,
+	serviceimplement.NewSupplierService,
+	serviceimplement.NewPurchaseOrderService,
+	serviceimplement.NewWarehouseService,
+	serviceimplement.NewStockTransferService,
+	serviceimplement.NewStocktakeService,
+	serviceimplement.NewPriceListService,
 )
 
 var repositorySet = wire.NewSet(
@@ -61,6 +85,24 @@ var repositorySet = wire.NewSet(
 	repositoryimplement.NewOrderRepository,
 	repositoryimplement.NewOrderItemRepository,
 	repositoryimplement.NewOrderImageRepository,
+	repositoryimplement.NewPaymentRepository,
+	repositoryimplement.NewOrderStatusHistoryRepository,
+	repositoryimplement.NewOrderReturnRepository,
+	repositoryimplement.NewOrderReturnItemRepository,
+	repositoryimplement.NewReportRepository,
+	repositoryimplement.NewRefreshTokenRepository,
+	repositoryimplement.NewLoginAttemptRepository,
+	repositoryimplement.NewAuditLogRepository,
+	repositoryimplement.NewSupplierRepository,
+	repositoryimplement.NewPurchaseOrderRepository,
+	repositoryimplement.NewPurchaseOrderItemRepository,
+	repositoryimplement.NewProductCostHistoryRepository,
+	repositoryimplement.NewWarehouseRepository,
+	repositoryimplement.NewStockTransferRepository,
+	repositoryimplement.NewStocktakeRepository,
+	repositoryimplement.NewStocktakeItemRepository,
+	repositoryimplement.NewProductPriceRepository,
+	repositoryimplement.NewCustomerDiscountRepository,
 )
 
 var middlewareSet = wire.NewSet(
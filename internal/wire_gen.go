@@ -26,34 +26,76 @@ func InitializeContainer(db database.Db) *controller.ApiContainer {
 	passwordEncoder := beanimplement.NewBcryptPasswordEncoder()
 	helloWorldService := serviceimplement.NewHelloWorldService(helloWorldRepository, passwordEncoder)
 	helloWorldHandler := v1.NewHelloWorldHandler(helloWorldService)
-	authMiddleware := middleware.NewAuthMiddleware()
 	userRepository := repositoryimplement.NewUserRepository(db)
-	userService := serviceimplement.NewUserService(userRepository, passwordEncoder)
+	authMiddleware := middleware.NewAuthMiddleware(userRepository)
+	refreshTokenRepository := repositoryimplement.NewRefreshTokenRepository(db)
+	loginAttemptRepository := repositoryimplement.NewLoginAttemptRepository(db)
+	unitOfWork := repositoryimplement.NewUnitOfWork(db)
+	userService := serviceimplement.NewUserService(userRepository, refreshTokenRepository, loginAttemptRepository, passwordEncoder, unitOfWork)
 	userHandler := v1.NewUserHandler(userService)
 	productRepository := repositoryimplement.NewProductRepository(db)
 	inventoryRepository := repositoryimplement.NewInventoryRepository(db)
-	unitOfWork := repositoryimplement.NewUnitOfWork(db)
-	productService := serviceimplement.NewProductService(productRepository, inventoryRepository, unitOfWork)
+	productCostHistoryRepository := repositoryimplement.NewProductCostHistoryRepository(db)
+	warehouseRepository := repositoryimplement.NewWarehouseRepository(db)
+	auditLogRepository := repositoryimplement.NewAuditLogRepository(db)
+	productService := serviceimplement.NewProductService(productRepository, inventoryRepository, productCostHistoryRepository, warehouseRepository, auditLogRepository, unitOfWork)
 	productHandler := v1.NewProductHandler(productService)
 	inventoryHistoryRepository := repositoryimplement.NewInventoryHistoryRepository(db)
-	inventoryService := serviceimplement.NewInventoryService(inventoryRepository, inventoryHistoryRepository, userRepository, productRepository, unitOfWork)
+	orderItemRepository := repositoryimplement.NewOrderItemRepository(db)
+	inventoryService := serviceimplement.NewInventoryService(inventoryRepository, inventoryHistoryRepository, userRepository, productRepository, orderItemRepository, productCostHistoryRepository, warehouseRepository, auditLogRepository, unitOfWork)
 	inventoryHandler := v1.NewInventoryHandler(inventoryService)
-	inventoryHistoryService := serviceimplement.NewInventoryHistoryService(inventoryHistoryRepository)
+	inventoryHistoryService := serviceimplement.NewInventoryHistoryService(inventoryHistoryRepository, warehouseRepository, inventoryRepository, productRepository)
 	inventoryHistoryHandler := v1.NewInventoryHistoryHandler(inventoryHistoryService)
 	customerRepository := repositoryimplement.NewCustomerRepository(db)
-	customerService := serviceimplement.NewCustomerService(customerRepository, unitOfWork)
-	customerHandler := v1.NewCustomerHandler(customerService)
 	orderRepository := repositoryimplement.NewOrderRepository(db)
-	orderItemRepository := repositoryimplement.NewOrderItemRepository(db)
+	productPriceRepository := repositoryimplement.NewProductPriceRepository(db)
+	customerDiscountRepository := repositoryimplement.NewCustomerDiscountRepository(db)
+	customerService := serviceimplement.NewCustomerService(customerRepository, orderRepository, orderItemRepository, productPriceRepository, customerDiscountRepository, auditLogRepository, unitOfWork)
+	customerHandler := v1.NewCustomerHandler(customerService)
 	orderImageRepository := repositoryimplement.NewOrderImageRepository(db)
+	paymentRepository := repositoryimplement.NewPaymentRepository(db)
+	orderStatusHistoryRepository := repositoryimplement.NewOrderStatusHistoryRepository(db)
+	orderReturnItemRepository := repositoryimplement.NewOrderReturnItemRepository(db)
 	s3Service := beanimplement.NewS3Service()
-	orderService := serviceimplement.NewOrderService(orderRepository, orderItemRepository, inventoryRepository, inventoryHistoryRepository, userRepository, unitOfWork, productRepository, customerRepository, orderImageRepository, s3Service)
+	orderService := serviceimplement.NewOrderService(orderRepository, orderItemRepository, inventoryRepository, inventoryHistoryRepository, userRepository, unitOfWork, productRepository, customerRepository, orderImageRepository, paymentRepository, orderStatusHistoryRepository, orderReturnItemRepository, auditLogRepository, warehouseRepository, productPriceRepository, customerDiscountRepository, s3Service)
 	orderHandler := v1.NewOrderHandler(orderService)
-	orderImageService := serviceimplement.NewOrderImageService(orderImageRepository, s3Service)
+	orderImageService := serviceimplement.NewOrderImageService(orderImageRepository, auditLogRepository, unitOfWork, s3Service)
 	orderImageHandler := v1.NewOrderImageHandler(orderImageService)
-	statisticsService := serviceimplement.NewStatisticsService(productRepository, customerRepository, inventoryRepository, orderRepository)
+	reportRepository := repositoryimplement.NewReportRepository(db)
+	statisticsService := serviceimplement.NewStatisticsService(productRepository, customerRepository, inventoryRepository, orderRepository, reportRepository)
 	statisticsHandler := v1.NewStatisticsHandler(statisticsService)
-	server := http.NewServer(healthHandler, helloWorldHandler, authMiddleware, userHandler, productHandler, inventoryHandler, inventoryHistoryHandler, customerHandler, orderHandler, orderImageHandler, statisticsHandler)
+	paymentService := serviceimplement.NewPaymentService(paymentRepository, orderRepository, orderItemRepository, customerRepository, unitOfWork)
+	paymentHandler := v1.NewPaymentHandler(paymentService)
+	orderReturnRepository := repositoryimplement.NewOrderReturnRepository(db)
+	orderReturnService := serviceimplement.NewOrderReturnService(orderReturnRepository, orderReturnItemRepository, orderRepository, orderItemRepository, inventoryRepository, inventoryHistoryRepository, paymentRepository, userRepository, unitOfWork)
+	orderReturnHandler := v1.NewOrderReturnHandler(orderReturnService)
+	exportService := serviceimplement.NewExportService(orderRepository, orderItemRepository, productRepository, inventoryRepository, statisticsService)
+	exportHandler := v1.NewExportHandler(exportService)
+	documentService := serviceimplement.NewDocumentService(orderService)
+	documentHandler := v1.NewDocumentHandler(documentService)
+	importService := serviceimplement.NewImportService(productRepository, inventoryRepository, inventoryHistoryRepository, productCostHistoryRepository, customerRepository, userRepository, warehouseRepository, auditLogRepository, unitOfWork)
+	importHandler := v1.NewImportHandler(importService)
+	auditLogService := serviceimplement.NewAuditLogService(auditLogRepository)
+	auditLogHandler := v1.NewAuditLogHandler(auditLogService)
+	supplierRepository := repositoryimplement.NewSupplierRepository(db)
+	supplierService := serviceimplement.NewSupplierService(supplierRepository)
+	supplierHandler := v1.NewSupplierHandler(supplierService)
+	purchaseOrderRepository := repositoryimplement.NewPurchaseOrderRepository(db)
+	purchaseOrderItemRepository := repositoryimplement.NewPurchaseOrderItemRepository(db)
+	purchaseOrderService := serviceimplement.NewPurchaseOrderService(purchaseOrderRepository, purchaseOrderItemRepository, supplierRepository, productRepository, inventoryRepository, inventoryHistoryRepository, productCostHistoryRepository, userRepository, warehouseRepository, auditLogRepository, unitOfWork)
+	purchaseOrderHandler := v1.NewPurchaseOrderHandler(purchaseOrderService)
+	warehouseService := serviceimplement.NewWarehouseService(warehouseRepository, inventoryRepository, unitOfWork)
+	warehouseHandler := v1.NewWarehouseHandler(warehouseService)
+	stockTransferRepository := repositoryimplement.NewStockTransferRepository(db)
+	stockTransferService := serviceimplement.NewStockTransferService(stockTransferRepository, warehouseRepository, inventoryRepository, inventoryHistoryRepository, userRepository, unitOfWork)
+	stockTransferHandler := v1.NewStockTransferHandler(stockTransferService)
+	stocktakeRepository := repositoryimplement.NewStocktakeRepository(db)
+	stocktakeItemRepository := repositoryimplement.NewStocktakeItemRepository(db)
+	stocktakeService := serviceimplement.NewStocktakeService(stocktakeRepository, stocktakeItemRepository, warehouseRepository, inventoryRepository, inventoryHistoryRepository, userRepository, unitOfWork)
+	stocktakeHandler := v1.NewStocktakeHandler(stocktakeService)
+	priceListService := serviceimplement.NewPriceListService(productPriceRepository, customerDiscountRepository, productRepository, customerRepository, auditLogRepository, unitOfWork)
+	priceListHandler := v1.NewPriceListHandler(priceListService)
+	server := http.NewServer(healthHandler, helloWorldHandler, authMiddleware, userHandler, productHandler, inventoryHandler, inventoryHistoryHandler, customerHandler, orderHandler, orderImageHandler, statisticsHandler, paymentHandler, orderReturnHandler, exportHandler, documentHandler, importHandler, auditLogHandler, supplierHandler, purchaseOrderHandler, warehouseHandler, stockTransferHandler, stocktakeHandler, priceListHandler)
 	apiContainer := controller.NewApiContainer(server)
 	return apiContainer
 }
@@ -66,11 +108,11 @@ var container = wire.NewSet(controller.NewApiContainer)
 var serverSet = wire.NewSet(http.NewServer)
 
 // handler === controller | with service and repository layers to form 3 layers architecture
-var handlerSet = wire.NewSet(v1.NewHealthHandler, v1.NewHelloWorldHandler, v1.NewUserHandler, v1.NewProductHandler, v1.NewInventoryHandler, v1.NewInventoryHistoryHandler, v1.NewCustomerHandler, v1.NewOrderHandler, v1.NewOrderImageHandler, v1.NewStatisticsHandler)
+var handlerSet = wire.NewSet(v1.NewHealthHandler, v1.NewHelloWorldHandler, v1.NewUserHandler, v1.NewProductHandler, v1.NewInventoryHandler, v1.NewInventoryHistoryHandler, v1.NewCustomerHandler, v1.NewOrderHandler, v1.NewOrderImageHandler, v1.NewStatisticsHandler, v1.NewPaymentHandler, v1.NewOrderReturnHandler, v1.NewExportHandler, v1.NewDocumentHandler, v1.NewImportHandler, v1.NewAuditLogHandler, v1.NewSupplierHandler, v1.NewPurchaseOrderHandler, v1.NewWarehouseHandler, v1.NewStockTransferHandler, v1.NewStocktakeHandler, v1.NewPriceListHandler)
 
-var serviceSet = wire.NewSet(serviceimplement.NewHelloWorldService, serviceimplement.NewUserService, serviceimplement.NewProductService, serviceimplement.NewInventoryService, serviceimplement.NewInventoryHistoryService, serviceimplement.NewCustomerService, serviceimplement.NewOrderService, serviceimplement.NewOrderImageService, serviceimplement.NewStatisticsService)
+var serviceSet = wire.NewSet(serviceimplement.NewHelloWorldService, serviceimplement.NewUserService, serviceimplement.NewProductService, serviceimplement.NewInventoryService, serviceimplement.NewInventoryHistoryService, serviceimplement.NewCustomerService, serviceimplement.NewOrderService, serviceimplement.NewOrderImageService, serviceimplement.NewStatisticsService, serviceimplement.NewPaymentService, serviceimplement.NewOrderReturnService, serviceimplement.NewExportService, serviceimplement.NewDocumentService, serviceimplement.NewImportService, serviceimplement.NewAuditLogService, serviceimplement.NewSupplierService, serviceimplement.NewPurchaseOrderService, serviceimplement.NewWarehouseService, serviceimplement.NewStockTransferService, serviceimplement.NewStocktakeService, serviceimplement.NewPriceListService)
 
-var repositorySet = wire.NewSet(repositoryimplement.NewHelloWorldRepository, repositoryimplement.NewUserRepository, repositoryimplement.NewProductRepository, repositoryimplement.NewInventoryRepository, repositoryimplement.NewInventoryHistoryRepository, repositoryimplement.NewUnitOfWork, repositoryimplement.NewCustomerRepository, repositoryimplement.NewOrderRepository, repositoryimplement.NewOrderItemRepository, repositoryimplement.NewOrderImageRepository)
+var repositorySet = wire.NewSet(repositoryimplement.NewHelloWorldRepository, repositoryimplement.NewUserRepository, repositoryimplement.NewProductRepository, repositoryimplement.NewInventoryRepository, repositoryimplement.NewInventoryHistoryRepository, repositoryimplement.NewUnitOfWork, repositoryimplement.NewCustomerRepository, repositoryimplement.NewOrderRepository, repositoryimplement.NewOrderItemRepository, repositoryimplement.NewOrderImageRepository, repositoryimplement.NewPaymentRepository, repositoryimplement.NewOrderStatusHistoryRepository, repositoryimplement.NewOrderReturnRepository, repositoryimplement.NewOrderReturnItemRepository, repositoryimplement.NewReportRepository, repositoryimplement.NewRefreshTokenRepository, repositoryimplement.NewLoginAttemptRepository, repositoryimplement.NewAuditLogRepository, repositoryimplement.NewSupplierRepository, repositoryimplement.NewPurchaseOrderRepository, repositoryimplement.NewPurchaseOrderItemRepository, repositoryimplement.NewProductCostHistoryRepository, repositoryimplement.NewWarehouseRepository, repositoryimplement.NewStockTransferRepository, repositoryimplement.NewStocktakeRepository, repositoryimplement.NewStocktakeItemRepository, repositoryimplement.NewProductPriceRepository, repositoryimplement.NewCustomerDiscountRepository)
 
 var middlewareSet = wire.NewSet(middleware.NewAuthMiddleware)
 
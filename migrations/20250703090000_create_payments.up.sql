@@ -0,0 +1,16 @@
+CREATE TABLE payments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    order_id INT NOT NULL,
+    amount INT NOT NULL COMMENT 'Số tiền thanh toán (VND)',
+    method VARCHAR(32) NOT NULL COMMENT 'Phương thức thanh toán: CASH, BANK_TRANSFER, OTHER',
+    paid_at DATE NOT NULL COMMENT 'Ngày thanh toán',
+    note TEXT COMMENT 'Ghi chú thanh toán',
+    recorded_by INT NOT NULL COMMENT 'Người ghi nhận thanh toán',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (recorded_by) REFERENCES users(id),
+    CONSTRAINT check_payment_amount_positive CHECK (amount > 0)
+);
+
+-- Công nợ giờ được tính tự động từ các khoản thanh toán
+UPDATE orders SET debt_status = 'UNPAID' WHERE debt_status IS NULL OR debt_status = '';
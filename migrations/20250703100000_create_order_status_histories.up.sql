@@ -0,0 +1,11 @@
+CREATE TABLE order_status_histories (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    order_id INT NOT NULL,
+    from_status VARCHAR(20) NULL COMMENT 'Trạng thái trước khi chuyển, NULL khi tạo đơn',
+    to_status VARCHAR(20) NOT NULL COMMENT 'Trạng thái sau khi chuyển',
+    changed_by INT NOT NULL COMMENT 'Người chuyển trạng thái',
+    changed_at DATETIME DEFAULT CURRENT_TIMESTAMP COMMENT 'Thời gian chuyển trạng thái',
+    note TEXT COMMENT 'Ghi chú khi chuyển trạng thái',
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES users(id)
+);
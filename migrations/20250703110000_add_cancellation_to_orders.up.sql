@@ -0,0 +1,7 @@
+-- Thay CHECK constraint mặc định của cột delivery_status để thêm trạng thái CANCELLED
+ALTER TABLE orders DROP CHECK orders_chk_1;
+
+ALTER TABLE orders
+ADD CONSTRAINT check_order_delivery_status CHECK (delivery_status IN ('PENDING', 'DELIVERED', 'UNPAID', 'COMPLETED', 'CANCELLED')),
+ADD COLUMN cancelled_at DATETIME NULL COMMENT 'Thời gian huỷ đơn hàng',
+ADD COLUMN cancellation_reason TEXT NULL COMMENT 'Lý do huỷ đơn hàng';
@@ -0,0 +1,31 @@
+CREATE TABLE order_returns (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    order_id INT NOT NULL,
+    returned_at DATE NOT NULL COMMENT 'Ngày khách trả hàng',
+    reason TEXT COMMENT 'Lý do trả hàng',
+    total_refund_amount INT NOT NULL DEFAULT 0 COMMENT 'Tổng giá trị ghi có cho khách (VND)',
+    total_original_cost INT NOT NULL DEFAULT 0 COMMENT 'Tổng giá gốc của hàng trả (VND)',
+    created_by INT NOT NULL COMMENT 'Người ghi nhận trả hàng',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id)
+);
+
+CREATE TABLE order_return_items (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    order_return_id INT NOT NULL,
+    order_item_id INT NOT NULL,
+    product_id INT NOT NULL,
+    quantity INT NOT NULL COMMENT 'Số lượng trả lại',
+    refund_amount INT NOT NULL COMMENT 'Giá trị ghi có cho khách (VND)',
+    original_cost INT NOT NULL COMMENT 'Giá gốc của hàng trả (VND)',
+    export_from VARCHAR(32) NOT NULL COMMENT 'Nguồn xuất của dòng hàng bị trả',
+    FOREIGN KEY (order_return_id) REFERENCES order_returns(id) ON DELETE CASCADE,
+    FOREIGN KEY (order_item_id) REFERENCES order_items(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    CONSTRAINT check_order_return_item_quantity_positive CHECK (quantity > 0)
+);
+
+ALTER TABLE orders
+ADD COLUMN total_returned_revenue INT NOT NULL DEFAULT 0 COMMENT 'Tổng doanh thu bị trừ do khách trả hàng (VND)',
+ADD COLUMN total_returned_cost INT NOT NULL DEFAULT 0 COMMENT 'Tổng giá gốc của hàng khách trả (VND)';
@@ -0,0 +1,3 @@
+-- Phục vụ phân trang theo con trỏ (order_date, id) cho danh sách đơn hàng
+CREATE INDEX idx_orders_order_date_id ON orders(order_date, id);
+CREATE INDEX idx_orders_customer_id_order_date ON orders(customer_id, order_date);
@@ -0,0 +1,4 @@
+-- Vai trò người dùng; tài khoản hiện có giữ toàn quyền nên mặc định là OWNER
+ALTER TABLE users
+ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'OWNER' COMMENT 'Vai trò: OWNER, SALES, WAREHOUSE, ACCOUNTANT',
+ADD CONSTRAINT check_user_role CHECK (role IN ('OWNER', 'SALES', 'WAREHOUSE', 'ACCOUNTANT'));
@@ -0,0 +1,4 @@
+-- Khoá/mở khoá tài khoản và thời điểm tạo tài khoản
+ALTER TABLE users
+ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE COMMENT 'Tài khoản bị khoá không thể đăng nhập',
+ADD COLUMN created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,16 @@
+CREATE TABLE refresh_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL COMMENT 'SHA-256 của refresh token, không lưu token gốc',
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL COMMENT 'Thời điểm thu hồi: khi đổi token mới, đăng xuất hoặc đổi mật khẩu',
+    replaced_by_id INT NULL COMMENT 'Token được cấp thay thế khi làm mới',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_refresh_token_hash (token_hash),
+    INDEX idx_refresh_tokens_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Tăng lên để vô hiệu mọi access token đã cấp của người dùng
+ALTER TABLE users
+ADD COLUMN token_version INT NOT NULL DEFAULT 0 COMMENT 'Phiên bản token, tăng khi đăng xuất mọi thiết bị hoặc đổi mật khẩu';
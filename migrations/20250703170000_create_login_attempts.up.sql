@@ -0,0 +1,13 @@
+CREATE TABLE login_attempts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    username VARCHAR(50) NOT NULL COMMENT 'Tên đăng nhập được gửi lên, có thể không tồn tại',
+    ip_address VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    user_id INT NULL COMMENT 'Người dùng tương ứng nếu tên đăng nhập tồn tại',
+    success BOOLEAN NOT NULL,
+    failure_reason VARCHAR(20) NULL COMMENT 'UNKNOWN_USER, WRONG_PASSWORD, DISABLED, LOCKED',
+    attempted_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_login_attempts_username (username, attempted_at),
+    INDEX idx_login_attempts_ip (ip_address, attempted_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,15 @@
+CREATE TABLE audit_logs (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NULL COMMENT 'Người thực hiện thay đổi',
+    action VARCHAR(20) NOT NULL COMMENT 'CREATE, UPDATE, DELETE',
+    entity_type VARCHAR(30) NOT NULL COMMENT 'PRODUCT, CUSTOMER, ORDER, ORDER_IMAGE, INVENTORY',
+    entity_id INT NOT NULL,
+    before_data JSON NULL COMMENT 'Dữ liệu trước khi thay đổi, NULL khi tạo mới',
+    after_data JSON NULL COMMENT 'Dữ liệu sau khi thay đổi, NULL khi xoá',
+    request_id VARCHAR(64) NULL COMMENT 'Mã request để gom các thay đổi cùng một lần gọi API',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_audit_logs_entity (entity_type, entity_id, created_at),
+    INDEX idx_audit_logs_user (user_id, created_at),
+    INDEX idx_audit_logs_request (request_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
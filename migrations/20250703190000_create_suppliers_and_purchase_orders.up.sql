@@ -0,0 +1,39 @@
+CREATE TABLE suppliers (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL COMMENT 'Tên nhà cung cấp',
+    phone VARCHAR(20) NOT NULL DEFAULT '' COMMENT 'Số điện thoại',
+    address VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Địa chỉ',
+    note TEXT NULL COMMENT 'Ghi chú',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE purchase_orders (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    supplier_id INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' COMMENT 'PENDING, PARTIALLY_RECEIVED, RECEIVED, CANCELLED',
+    expected_date DATE NULL COMMENT 'Ngày dự kiến nhận hàng',
+    note TEXT NULL COMMENT 'Ghi chú',
+    created_by INT NOT NULL COMMENT 'Người tạo phiếu nhập',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    received_at DATETIME NULL COMMENT 'Thời điểm nhận đủ hàng',
+    cancelled_at DATETIME NULL COMMENT 'Thời điểm huỷ phiếu nhập',
+    INDEX idx_purchase_orders_status (status),
+    FOREIGN KEY (supplier_id) REFERENCES suppliers(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    CONSTRAINT check_purchase_order_status CHECK (status IN ('PENDING', 'PARTIALLY_RECEIVED', 'RECEIVED', 'CANCELLED'))
+);
+
+CREATE TABLE purchase_order_items (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    purchase_order_id INT NOT NULL,
+    product_id INT NOT NULL,
+    quantity INT NOT NULL COMMENT 'Số lượng đặt mua',
+    received_quantity INT NOT NULL DEFAULT 0 COMMENT 'Số lượng đã nhận vào kho',
+    purchase_price INT NOT NULL COMMENT 'Giá nhập mỗi đơn vị (VND)',
+    UNIQUE KEY unique_purchase_order_product (purchase_order_id, product_id),
+    FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    CONSTRAINT check_purchase_order_item_quantity_positive CHECK (quantity > 0),
+    CONSTRAINT check_purchase_order_item_received_quantity CHECK (received_quantity >= 0 AND received_quantity <= quantity),
+    CONSTRAINT check_purchase_order_item_price_non_negative CHECK (purchase_price >= 0)
+);
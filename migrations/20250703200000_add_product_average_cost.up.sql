@@ -0,0 +1,24 @@
+ALTER TABLE products
+    ADD COLUMN average_cost INT NOT NULL DEFAULT 0 COMMENT 'Giá vốn bình quân gia quyền (VND), cập nhật mỗi lần nhập hàng có giá' AFTER original_price;
+
+-- Existing products start from their manually entered original price
+UPDATE products SET average_cost = original_price;
+
+CREATE TABLE product_cost_histories (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    product_id INT NOT NULL,
+    source VARCHAR(30) NOT NULL COMMENT 'PURCHASE_ORDER, INVENTORY_ADJUSTMENT, OPENING_STOCK',
+    reference_id INT NULL COMMENT 'Mã phiếu nhập nếu nhập từ phiếu nhập',
+    quantity_before INT NOT NULL COMMENT 'Số lượng tồn trước khi nhập',
+    received_quantity INT NOT NULL COMMENT 'Số lượng nhập',
+    unit_cost INT NOT NULL COMMENT 'Giá nhập mỗi đơn vị (VND)',
+    previous_average_cost INT NOT NULL COMMENT 'Giá vốn bình quân trước khi nhập (VND)',
+    average_cost INT NOT NULL COMMENT 'Giá vốn bình quân sau khi nhập (VND)',
+    created_by INT NULL COMMENT 'Người nhập hàng',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_product_cost_histories_product (product_id, created_at),
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT check_product_cost_history_received_quantity_positive CHECK (received_quantity > 0),
+    CONSTRAINT check_product_cost_history_unit_cost_non_negative CHECK (unit_cost >= 0)
+);
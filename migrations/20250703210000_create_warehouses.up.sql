@@ -0,0 +1,54 @@
+CREATE TABLE warehouses (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL COMMENT 'Tên kho',
+    address VARCHAR(255) NOT NULL DEFAULT '' COMMENT 'Địa chỉ kho',
+    is_default BOOLEAN NOT NULL DEFAULT FALSE COMMENT 'Kho mặc định khi không chọn kho',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Existing stock lives at the workshop, which stays the default warehouse
+INSERT INTO warehouses (id, name, is_default) VALUES (1, 'Xưởng', TRUE);
+
+-- Inventory is now kept per (warehouse, product); the product index replaces the old unique key for the foreign key
+ALTER TABLE inventory
+    ADD COLUMN warehouse_id INT NOT NULL DEFAULT 1 COMMENT 'Kho chứa hàng' AFTER id,
+    ADD INDEX idx_inventory_product (product_id);
+ALTER TABLE inventory
+    DROP INDEX unique_product_inventory,
+    ADD UNIQUE KEY unique_warehouse_product_inventory (warehouse_id, product_id),
+    ADD CONSTRAINT fk_inventory_warehouse FOREIGN KEY (warehouse_id) REFERENCES warehouses(id),
+    ALTER COLUMN warehouse_id DROP DEFAULT;
+
+ALTER TABLE inventory_histories
+    ADD COLUMN warehouse_id INT NOT NULL DEFAULT 1 COMMENT 'Kho phát sinh thay đổi' AFTER product_id,
+    ADD CONSTRAINT fk_inventory_histories_warehouse FOREIGN KEY (warehouse_id) REFERENCES warehouses(id);
+ALTER TABLE inventory_histories ALTER COLUMN warehouse_id DROP DEFAULT;
+
+-- Only items exported from inventory come out of a warehouse
+ALTER TABLE order_items
+    ADD COLUMN warehouse_id INT NULL COMMENT 'Kho xuất hàng, chỉ có khi xuất từ kho' AFTER export_from,
+    ADD CONSTRAINT fk_order_items_warehouse FOREIGN KEY (warehouse_id) REFERENCES warehouses(id);
+UPDATE order_items SET warehouse_id = 1 WHERE export_from = 'INVENTORY';
+
+ALTER TABLE purchase_orders
+    ADD COLUMN warehouse_id INT NOT NULL DEFAULT 1 COMMENT 'Kho nhận hàng' AFTER supplier_id,
+    ADD CONSTRAINT fk_purchase_orders_warehouse FOREIGN KEY (warehouse_id) REFERENCES warehouses(id);
+ALTER TABLE purchase_orders ALTER COLUMN warehouse_id DROP DEFAULT;
+
+CREATE TABLE stock_transfers (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    product_id INT NOT NULL,
+    from_warehouse_id INT NOT NULL COMMENT 'Kho chuyển đi',
+    to_warehouse_id INT NOT NULL COMMENT 'Kho nhận',
+    quantity INT NOT NULL COMMENT 'Số lượng chuyển',
+    note TEXT NULL COMMENT 'Ghi chú',
+    created_by INT NOT NULL COMMENT 'Người chuyển kho',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_stock_transfers_product (product_id, created_at),
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (from_warehouse_id) REFERENCES warehouses(id),
+    FOREIGN KEY (to_warehouse_id) REFERENCES warehouses(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    CONSTRAINT check_stock_transfer_quantity_positive CHECK (quantity > 0),
+    CONSTRAINT check_stock_transfer_warehouses_differ CHECK (from_warehouse_id <> to_warehouse_id)
+);
@@ -0,0 +1,29 @@
+-- Pending orders reserve stock instead of deducting it; the goods only leave the shelf when the order is delivered
+ALTER TABLE inventory
+    ADD COLUMN reserved_quantity INT NOT NULL DEFAULT 0 COMMENT 'Số lượng đang giữ cho đơn hàng chờ giao' AFTER quantity,
+    ADD CONSTRAINT check_reserved_quantity_non_negative CHECK (reserved_quantity >= 0);
+
+-- Goods of orders still pending were deducted when the orders were created: put them back on hand as reservations
+CREATE TEMPORARY TABLE pending_order_reservations AS
+SELECT oi.warehouse_id, oi.product_id, SUM(oi.quantity) AS quantity
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+WHERE o.delivery_status = 'PENDING' AND oi.export_from = 'INVENTORY'
+GROUP BY oi.warehouse_id, oi.product_id;
+
+INSERT INTO inventory_histories (product_id, warehouse_id, quantity, final_quantity, importer_name, imported_at, note)
+SELECT i.product_id, i.warehouse_id, r.quantity, i.quantity + r.quantity, 'system', NOW(), 'Chuyển hàng của đơn chờ giao sang giữ hàng'
+FROM inventory i
+JOIN pending_order_reservations r ON r.warehouse_id = i.warehouse_id AND r.product_id = i.product_id;
+
+UPDATE inventory i
+JOIN pending_order_reservations r ON r.warehouse_id = i.warehouse_id AND r.product_id = i.product_id
+SET i.quantity = i.quantity + r.quantity,
+    i.reserved_quantity = r.quantity,
+    i.version = UUID();
+
+DROP TEMPORARY TABLE pending_order_reservations;
+
+-- Reserved goods have to stay on hand until their orders are delivered or cancelled
+ALTER TABLE inventory
+    ADD CONSTRAINT check_quantity_covers_reserved CHECK (quantity >= reserved_quantity);
@@ -0,0 +1,6 @@
+-- Existing products keep the previous fixed low-stock threshold of 10
+ALTER TABLE products
+    ADD COLUMN min_stock INT NOT NULL DEFAULT 10 COMMENT 'Tồn kho tối thiểu, dưới mức này cần nhập thêm' AFTER average_cost,
+    ADD COLUMN reorder_quantity INT NOT NULL DEFAULT 0 COMMENT 'Số lượng nhập thêm tối thiểu mỗi lần' AFTER min_stock,
+    ADD CONSTRAINT check_min_stock_non_negative CHECK (min_stock >= 0),
+    ADD CONSTRAINT check_reorder_quantity_non_negative CHECK (reorder_quantity >= 0);
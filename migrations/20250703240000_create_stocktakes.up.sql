@@ -0,0 +1,32 @@
+CREATE TABLE stocktakes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    warehouse_id INT NOT NULL COMMENT 'Kho được kiểm kê',
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' COMMENT 'OPEN, POSTED, CANCELLED',
+    note TEXT NULL COMMENT 'Ghi chú',
+    created_by INT NOT NULL COMMENT 'Người mở phiếu kiểm kê',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    posted_by INT NULL COMMENT 'Người chốt phiếu kiểm kê',
+    posted_at DATETIME NULL COMMENT 'Thời điểm chốt và điều chỉnh tồn kho',
+    cancelled_at DATETIME NULL COMMENT 'Thời điểm huỷ phiếu kiểm kê',
+    INDEX idx_stocktakes_status (status),
+    FOREIGN KEY (warehouse_id) REFERENCES warehouses(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    FOREIGN KEY (posted_by) REFERENCES users(id),
+    CONSTRAINT check_stocktake_status CHECK (status IN ('OPEN', 'POSTED', 'CANCELLED'))
+);
+
+CREATE TABLE stocktake_items (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    stocktake_id INT NOT NULL,
+    product_id INT NOT NULL,
+    snapshot_quantity INT NOT NULL COMMENT 'Số lượng trên sổ sách lúc chụp',
+    snapshot_version VARCHAR(255) NOT NULL COMMENT 'Version tồn kho lúc chụp',
+    counted_quantity INT NULL COMMENT 'Số lượng đếm thực tế, NULL nếu chưa đếm',
+    counted_by INT NULL COMMENT 'Người đếm',
+    counted_at DATETIME NULL COMMENT 'Thời điểm đếm',
+    UNIQUE KEY unique_stocktake_product (stocktake_id, product_id),
+    FOREIGN KEY (stocktake_id) REFERENCES stocktakes(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (counted_by) REFERENCES users(id),
+    CONSTRAINT check_stocktake_item_counted_quantity CHECK (counted_quantity IS NULL OR counted_quantity >= 0)
+);
@@ -0,0 +1,20 @@
+ALTER TABLE inventory_histories
+    ADD COLUMN movement_type VARCHAR(20) NOT NULL DEFAULT 'ADJUSTMENT' COMMENT 'SALE, RETURN, PURCHASE, TRANSFER, ADJUSTMENT' AFTER final_quantity,
+    ADD CONSTRAINT check_inventory_history_movement_type CHECK (movement_type IN ('SALE', 'RETURN', 'PURCHASE', 'TRANSFER', 'ADJUSTMENT')),
+    ADD INDEX idx_inventory_histories_imported_at (imported_at);
+
+-- Classify existing rows by the notes the services have been writing; anything else stays a manual adjustment
+UPDATE inventory_histories SET movement_type = 'SALE'
+WHERE note LIKE 'Hàng trừ cho hoá đơn%'
+   OR note LIKE 'Điều chỉnh hàng cho hoá đơn%'
+   OR note LIKE 'Hồi hàng về từ đơn%'
+   OR note = 'Chuyển hàng của đơn chờ giao sang giữ hàng';
+
+UPDATE inventory_histories SET movement_type = 'RETURN'
+WHERE note LIKE 'Nhập lại hàng khách trả%';
+
+UPDATE inventory_histories SET movement_type = 'PURCHASE'
+WHERE note LIKE 'Nhập hàng từ phiếu nhập%';
+
+UPDATE inventory_histories SET movement_type = 'TRANSFER'
+WHERE note LIKE 'Chuyển sang %' OR note LIKE 'Nhận từ %';
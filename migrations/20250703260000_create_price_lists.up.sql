@@ -0,0 +1,34 @@
+CREATE TABLE product_prices (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    product_id INT NOT NULL,
+    customer_id INT NULL COMMENT 'Khách hàng được áp giá riêng, NULL là giá bán mặc định',
+    selling_price INT NOT NULL COMMENT 'Giá bán (VND)',
+    effective_from DATE NOT NULL COMMENT 'Ngày bắt đầu áp dụng',
+    created_by INT NOT NULL COMMENT 'Người tạo',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_product_prices_lookup (product_id, customer_id, effective_from),
+    -- One price per product, customer and day; COALESCE so that default prices (NULL customer) are unique too
+    UNIQUE KEY unique_product_price_effective_from (product_id, (COALESCE(customer_id, 0)), effective_from),
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (customer_id) REFERENCES customers(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    CONSTRAINT check_product_price_non_negative CHECK (selling_price >= 0)
+);
+
+CREATE TABLE customer_discounts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    customer_id INT NOT NULL,
+    discount_percent INT NOT NULL COMMENT 'Chiết khấu mặc định (%)',
+    effective_from DATE NOT NULL COMMENT 'Ngày bắt đầu áp dụng',
+    created_by INT NOT NULL COMMENT 'Người tạo',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_customer_discount_effective_from (customer_id, effective_from),
+    FOREIGN KEY (customer_id) REFERENCES customers(id),
+    FOREIGN KEY (created_by) REFERENCES users(id),
+    CONSTRAINT check_customer_discount_percent CHECK (discount_percent BETWEEN 0 AND 100)
+);
+
+-- Items sold before price lists existed were all priced by hand
+ALTER TABLE order_items
+    ADD COLUMN price_source VARCHAR(20) NOT NULL DEFAULT 'MANUAL' COMMENT 'LIST: theo bảng giá, MANUAL: nhập tay' AFTER final_amount,
+    ADD CONSTRAINT check_order_item_price_source CHECK (price_source IN ('LIST', 'MANUAL'));
@@ -0,0 +1,14 @@
+ALTER TABLE customers
+    ADD COLUMN normalized_phone VARCHAR(70) NOT NULL DEFAULT '' COMMENT 'Số điện thoại chỉ gồm chữ số, đầu số 84 đổi thành 0' AFTER phone,
+    ADD COLUMN deleted_at TIMESTAMP NULL COMMENT 'Thời điểm xoá khách hàng, NULL là đang hoạt động',
+    ADD COLUMN merged_into_id INT NULL COMMENT 'Khách hàng được gộp vào khi xoá do trùng lặp',
+    ADD INDEX idx_customers_normalized_phone (normalized_phone),
+    ADD CONSTRAINT fk_customers_merged_into FOREIGN KEY (merged_into_id) REFERENCES customers(id);
+
+-- Cùng một số điện thoại có thể được nhập theo nhiều định dạng: 0912 345 678, +84912345678, 0084912345678, 0912.345.678
+-- Phải khớp với stringutils.NormalizePhone
+UPDATE customers SET normalized_phone = REGEXP_REPLACE(COALESCE(phone, ''), '[^0-9]', '');
+UPDATE customers SET normalized_phone = SUBSTRING(normalized_phone, 3)
+WHERE normalized_phone LIKE '0084%' AND LENGTH(normalized_phone) >= 13;
+UPDATE customers SET normalized_phone = CONCAT('0', SUBSTRING(normalized_phone, 3))
+WHERE normalized_phone LIKE '84%' AND LENGTH(normalized_phone) >= 11;